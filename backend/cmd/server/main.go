@@ -35,6 +35,21 @@ func main() {
 	}
 	log.Println("Connected to MongoDB")
 
+	// Keep each user in at most one team, even when two joins race
+	if err := services.InitTeamIndexes(); err != nil {
+		log.Printf("⚠️ Warning: Failed to create team indexes: %v", err)
+	}
+
+	// Keep one bot memory profile per user, even when two refreshes race
+	if err := services.InitBotMemoryIndexes(); err != nil {
+		log.Printf("⚠️ Warning: Failed to create bot memory indexes: %v", err)
+	}
+
+	// Load admin-curated evidence packs into the bot retrieval index
+	if err := services.InitEvidenceIndex(); err != nil {
+		log.Printf("⚠️ Warning: Failed to load evidence index: %v", err)
+	}
+
 	// Initialize Casbin RBAC
 	if err := middlewares.InitCasbin("./config/config.prod.yml"); err != nil {
 		log.Fatalf("Failed to initialize Casbin: %v", err)
@@ -50,16 +65,28 @@ func main() {
 		}
 		if err := debate.InitRedis(redisURL, cfg.Redis.Password, cfg.Redis.DB); err != nil {
 			log.Printf("⚠️ Warning: Failed to initialize Redis: %v", err)
-			log.Printf("⚠️ Some realtime features will be unavailable until Redis is reachable")
+			log.Printf("⚠️ Spectator polls, questions and reactions will run in memory on this instance only")
 		} else {
 			log.Println("Connected to Redis")
 		}
 	} else {
-		log.Println("Redis Addr not configured; continuing without Redis-backed features")
+		log.Println("Redis Addr not configured; spectator features will run in memory on this instance only")
 	}
+	// Stream server-generated events (e.g. exhibition turns) to spectators
+	services.SetSpectatorEventPublisher(websocket.PublishSpectatorEvent)
+	services.SetTeamDebateStateListener(websocket.ApplyTeamDebateState)
+	services.SetTeamSpeakingTimeSource(websocket.TeamSpeakingTimes)
+
+	if err := services.FailInterruptedExhibitions(); err != nil {
+		log.Printf("⚠️ Warning: Failed to clean up interrupted exhibitions: %v", err)
+	}
+
 	// Start the room watching service for matchmaking after DB connection
 	go websocket.WatchForNewRooms()
 
+	// Start scheduled team challenges when their slot opens
+	go services.StartTeamChallengeScheduler()
+
 	utils.SetJWTSecret(cfg.JWT.Secret)
 
 	// Seed initial debate-related data
@@ -81,7 +108,8 @@ func main() {
 func setupRouter(cfg *config.Config) *gin.Engine {
 	router := gin.Default()
 
-	// Set trusted proxies (adjust as needed)
+	// Set trusted proxies (adjust as needed). Only these may set X-Forwarded-For, which the per-IP
+	// limits on anonymous spectators depend on: add your load balancer here, and never trust all.
 	router.SetTrustedProxies([]string{"127.0.0.1", "localhost"})
 
 	// Configure CORS for your frontend (e.g., localhost:5173 for Vite)
@@ -125,6 +153,13 @@ func setupRouter(cfg *config.Config) *gin.Engine {
 		auth.GET("/api/leaderboard", routes.GetGamificationLeaderboardRouteHandler)
 
 		routes.SetupDebateVsBotRoutes(auth)
+		routes.SetupExhibitionRoutes(auth)
+		routes.SetupDebateQuestionRoutes(auth)
+		routes.SetupAudienceDecisionRoutes(auth)
+		routes.SetupSpectatorSettingsRoutes(auth)
+		routes.SetupDebatePollRoutes(auth)
+		routes.SetupReactionHeatmapRoutes(auth)
+		routes.SetupSpectatorChatRoutes(auth)
 
 		// WebSocket signaling endpoint (handles auth internally)
 		router.GET("/ws", websocket.WebsocketHandler)
@@ -148,6 +183,7 @@ func setupRouter(cfg *config.Config) *gin.Engine {
 		routes.SetupTeamDebateRoutes(auth)
 		routes.SetupTeamChatRoutes(auth)
 		routes.SetupTeamMatchmakingRoutes(auth)
+		routes.SetupTeamChallengeRoutes(auth)
 		log.Println("Team routes registered")
 
 		// Community routes
@@ -172,5 +208,8 @@ func setupRouter(cfg *config.Config) *gin.Engine {
 	// FIX: Use websocket.DebateWebsocketHandler (moved to websocket package)
 	router.GET("/ws/debate/:debateID", websocket.DebateWebsocketHandler)
 
+	// Live debate directory (public, like the spectator WebSocket)
+	routes.SetupLiveDebateRoutes(router)
+
 	return router
 }
@@ -3,6 +3,7 @@ package controllers
 import (
 	"arguehub/db"
 	"arguehub/models"
+	"arguehub/websocket"
 	"context"
 	"log"
 	"net/http"
@@ -157,6 +158,11 @@ func GetAnalytics(ctx *gin.Context) {
 	})
 }
 
+// GetSpectatorMetrics returns the spectator hub's open rooms, connections and goroutines
+func GetSpectatorMetrics(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"metrics": websocket.GetDebateHub().Metrics()})
+}
+
 // GetAnalyticsHistory returns analytics data over time
 func GetAnalyticsHistory(ctx *gin.Context) {
 	days := 7 // default to 7 days
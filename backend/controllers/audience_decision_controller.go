@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"arguehub/middlewares"
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EnableAudienceDecision puts a debate in audience decision mode and opens the opening poll
+// (debate hosts only)
+func EnableAudienceDecision(c *gin.Context) {
+	userID, ok := requireDebateHost(c, "Only the debate's hosts can enable audience decision mode")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Topic string `json:"topic"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	decision, err := services.EnableAudienceDecision(c.Param("debateID"), req.Topic, userID)
+	if errors.Is(err, services.ErrAudienceDecisionExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Failed to open the audience poll"})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"decision": decision})
+}
+
+// GetAudienceDecision returns a debate's audience decision and, once decided, the audience's verdict
+func GetAudienceDecision(c *gin.Context) {
+	decision, err := services.GetAudienceDecision(c.Param("debateID"))
+	if errors.Is(err, services.ErrNoAudienceDecision) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load audience decision"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"decision": decision})
+}
+
+// CloseAudienceDecision closes the closing poll before the voting window runs out. Cutting the vote
+// short can favour one side, so only a neutral host may; admins and moderators use
+// AdminCloseAudienceDecision.
+func CloseAudienceDecision(c *gin.Context) {
+	if _, ok := requireNeutralDebateHost(c, "Only the debate's host can close the audience vote"); !ok {
+		return
+	}
+	closeAudienceDecision(c)
+}
+
+// AdminCloseAudienceDecision closes any debate's closing poll before the voting window runs out
+func AdminCloseAudienceDecision(c *gin.Context) {
+	if result, ok := closeAudienceDecision(c); ok {
+		decisionID := primitive.NilObjectID
+		if decision, err := services.GetAudienceDecision(c.Param("debateID")); err == nil {
+			decisionID = decision.ID
+		}
+		role, _ := c.Get("adminRole")
+		middlewares.LogAdminAction(c, "close_audience_decision", "audience_decision", decisionID, map[string]interface{}{
+			"debateId":      c.Param("debateID"),
+			"moderatorRole": role,
+			"winner":        result.Winner,
+		})
+	}
+}
+
+func closeAudienceDecision(c *gin.Context) (*models.AudienceResult, bool) {
+	result, err := services.CloseAudienceDecision(c.Param("debateID"))
+	if errors.Is(err, services.ErrNoAudienceDecision) {
+		c.JSON(http.StatusConflict, gin.H{"error": "The closing poll is not open"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close the audience vote"})
+		return nil, false
+	}
+	c.JSON(http.StatusOK, gin.H{"result": result})
+	return result, true
+}
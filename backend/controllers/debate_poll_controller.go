@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"arguehub/internal/debate"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetDebatePollResults returns the archived results of a debate's closed polls
+func GetDebatePollResults(c *gin.Context) {
+	results, err := services.ListDebatePollResults(c.Param("debateID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load poll results"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
+
+// CreateDebatePoll opens a poll for a debate's spectators (debate hosts only)
+func CreateDebatePoll(c *gin.Context) {
+	userID, ok := requireDebateHost(c, "Only the debate's hosts can create polls")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Question        string   `json:"question" binding:"required"`
+		Options         []string `json:"options" binding:"required"`
+		DurationSeconds int      `json:"durationSeconds"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	poll := debate.PollMetadata{
+		Question:  req.Question,
+		Options:   req.Options,
+		CreatedBy: userID.Hex(),
+	}
+	pollID, err := services.OpenDebatePoll(c.Param("debateID"), poll, time.Duration(req.DurationSeconds)*time.Second)
+	if errors.Is(err, debate.ErrPollExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"pollId": pollID})
+}
+
+// CloseDebatePoll closes a poll and archives its results (the poll's creator or a debate host only)
+func CloseDebatePoll(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	debateID, pollID := c.Param("debateID"), c.Param("pollId")
+
+	meta, err := services.GetDebatePollMetadata(debateID, pollID)
+	if errors.Is(err, debate.ErrPollNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Polls are unavailable"})
+		return
+	}
+	if meta.CreatedBy != userID.(primitive.ObjectID).Hex() {
+		if _, ok := requireDebateHost(c, "Only the poll's creator or the debate's hosts can close it"); !ok {
+			return
+		}
+	}
+
+	result, err := services.CloseDebatePoll(debateID, pollID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to close poll"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"result": result})
+}
@@ -0,0 +1,180 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"arguehub/middlewares"
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetDebateQuestions lists a debate's spectator questions. Hosts see the whole moderation queue,
+// everyone else only the approved questions.
+func GetDebateQuestions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	debateID := c.Param("debateID")
+
+	isHost, err := services.IsDebateHost(debateID, userID.(primitive.ObjectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load debate"})
+		return
+	}
+
+	questions, err := services.ListDebateQuestions(debateID, !isHost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load questions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"questions": questions, "isHost": isHost})
+}
+
+// ReviewDebateQuestion approves or rejects a spectator question. Only a neutral host may, since
+// picking questions favours one side; admins and moderators use AdminReviewDebateQuestion.
+func ReviewDebateQuestion(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("questionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+	userID, ok := requireNeutralDebateHost(c, "Only the debate's host can moderate questions")
+	if !ok {
+		return
+	}
+	reviewDebateQuestion(c, userID, questionID)
+}
+
+// AdminReviewDebateQuestion approves or rejects a spectator question in any debate
+func AdminReviewDebateQuestion(c *gin.Context) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("questionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return
+	}
+	adminID, exists := c.Get("adminID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	if question, ok := reviewDebateQuestion(c, adminID.(primitive.ObjectID), questionID); ok {
+		role, _ := c.Get("adminRole")
+		middlewares.LogAdminAction(c, "review_debate_question", "debate_question", question.ID, map[string]interface{}{
+			"debateId":      question.DebateID,
+			"moderatorRole": role,
+			"status":        question.Status,
+		})
+	}
+}
+
+func reviewDebateQuestion(c *gin.Context, reviewerID, questionID primitive.ObjectID) (*models.DebateQuestion, bool) {
+	var req struct {
+		Approve *bool `json:"approve" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+
+	question, err := services.ReviewDebateQuestion(c.Param("debateID"), questionID, reviewerID, *req.Approve)
+	if errors.Is(err, services.ErrDebateQuestionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to review question"})
+		return nil, false
+	}
+	c.JSON(http.StatusOK, gin.H{"question": question})
+	return question, true
+}
+
+// UpdateDebateQuestionAnswered marks a spectator question as answered or unanswered (debate hosts only)
+func UpdateDebateQuestionAnswered(c *gin.Context) {
+	_, questionID, ok := loadDebateQuestionForHost(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Answered bool `json:"answered"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	question, err := services.SetDebateQuestionAnswered(c.Param("debateID"), questionID, req.Answered)
+	if errors.Is(err, services.ErrDebateQuestionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update question"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"question": question})
+}
+
+// loadDebateQuestionForHost checks the current user hosts the debate and parses the question ID.
+// It writes the error response and returns false when either check fails.
+func loadDebateQuestionForHost(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	questionID, err := primitive.ObjectIDFromHex(c.Param("questionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid question ID"})
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	userID, ok := requireDebateHost(c, "Only the debate's hosts can moderate questions")
+	if !ok {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return userID, questionID, true
+}
+
+// requireNeutralDebateHost checks the current user is the debate's neutral host, for actions a
+// debater must not take alone. It writes the error response and returns false when they aren't.
+func requireNeutralDebateHost(c *gin.Context, forbidden string) (primitive.ObjectID, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return primitive.NilObjectID, false
+	}
+
+	isHost, err := services.IsNeutralDebateHost(c.Param("debateID"), userID.(primitive.ObjectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load debate"})
+		return primitive.NilObjectID, false
+	}
+	if !isHost {
+		c.JSON(http.StatusForbidden, gin.H{"error": forbidden})
+		return primitive.NilObjectID, false
+	}
+	return userID.(primitive.ObjectID), true
+}
+
+// requireDebateHost checks the current user hosts the debate in the route. It writes the error
+// response and returns false when they don't.
+func requireDebateHost(c *gin.Context, forbidden string) (primitive.ObjectID, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return primitive.NilObjectID, false
+	}
+
+	isHost, err := services.IsDebateHost(c.Param("debateID"), userID.(primitive.ObjectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load debate"})
+		return primitive.NilObjectID, false
+	}
+	if !isHost {
+		c.JSON(http.StatusForbidden, gin.H{"error": forbidden})
+		return primitive.NilObjectID, false
+	}
+	return userID.(primitive.ObjectID), true
+}
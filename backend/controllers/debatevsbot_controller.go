@@ -53,10 +53,15 @@ type DebateMessageResponse struct {
 	Topic    string `json:"topic"`
 	Stance   string `json:"stance"`
 	Response string `json:"response"`
+	// Citations are the evidence passages referenced in Response
+	Citations []models.Citation `json:"citations,omitempty"`
+	// Analysis is the breakdown of the user's latest message
+	Analysis *models.ArgumentAnalysis `json:"analysis,omitempty"`
 }
 
 type JudgeResponse struct {
-	Result string `json:"result"`
+	Result   string                   `json:"result"`
+	Feedback *models.ArgumentFeedback `json:"feedback,omitempty"`
 }
 
 func CreateDebate(c *gin.Context) {
@@ -139,13 +144,32 @@ func SendDebateMessage(c *gin.Context) {
 		return
 	}
 
+	// Break down the user's latest argument so the bot can respond to its structure
+	var userAnalysis *models.ArgumentAnalysis
+	for i := len(req.History) - 1; i >= 0; i-- {
+		if req.History[i].Sender == "User" {
+			if req.History[i].Analysis == nil {
+				req.History[i].Analysis = services.AnalyzeArgument(req.Topic, req.History[i].Text)
+			}
+			userAnalysis = req.History[i].Analysis
+			break
+		}
+	}
+
+	// Bots remember what they learned from earlier debates with this user
+	memory, err := services.GetBotOpponentProfile(email)
+	if err != nil {
+		log.Printf("Failed to load bot memory for %s: %v", email, err)
+	}
+
 	// Generate bot response with the additional context field.
-	botResponse := services.GenerateBotResponse(req.BotName, req.BotLevel, req.Topic, req.History, req.Stance, req.Context, 150)
+	botResponse, citations := services.GenerateBotResponse(req.BotName, req.BotLevel, req.Topic, req.History, req.Stance, req.Context, 150, memory)
 
 	// Update debate history with the bot's response.
 	updatedHistory := append(req.History, models.Message{
-		Sender: "Bot",
-		Text:   botResponse,
+		Sender:    "Bot",
+		Text:      botResponse,
+		Citations: citations,
 		// You can also store the phase if needed.
 	})
 
@@ -167,12 +191,14 @@ func SendDebateMessage(c *gin.Context) {
 	}
 
 	response := DebateMessageResponse{
-		DebateId: debate.ID.Hex(),
-		BotName:  req.BotName,
-		BotLevel: req.BotLevel,
-		Topic:    req.Topic,
-		Stance:   req.Stance,
-		Response: botResponse,
+		DebateId:  debate.ID.Hex(),
+		BotName:   req.BotName,
+		BotLevel:  req.BotLevel,
+		Topic:     req.Topic,
+		Stance:    req.Stance,
+		Response:  botResponse,
+		Citations: citations,
+		Analysis:  userAnalysis,
 	}
 	c.JSON(200, response)
 }
@@ -207,6 +233,10 @@ func JudgeDebate(c *gin.Context) {
 	// Judge the debate
 	result := services.JudgeDebate(req.History)
 
+	// Summarise the user's argumentation for post-debate feedback
+	services.FillMissingAnalyses(req.History)
+	feedback := services.BuildArgumentFeedback(req.History)
+
 	// Update debate outcome
 	if err := db.UpdateDebateVsBotOutcome(email, result); err != nil {
 	}
@@ -277,6 +307,7 @@ func JudgeDebate(c *gin.Context) {
 		req.History,
 		nil,
 	)
+	go refreshBotMemory(userID, email)
 
 	// Update gamification (score, badges, streaks) after bot debate
 	log.Printf("About to call updateGamificationAfterBotDebate for user %s, result: %s, topic: %s",
@@ -293,10 +324,45 @@ func JudgeDebate(c *gin.Context) {
 	}()
 
 	c.JSON(200, JudgeResponse{
-		Result: result,
+		Result:   result,
+		Feedback: feedback,
 	})
 }
 
+// refreshBotMemory folds the newly saved transcript into what the bots remember about the user
+func refreshBotMemory(userID primitive.ObjectID, email string) {
+	if err := services.RefreshBotOpponentProfile(userID, email); err != nil {
+		log.Printf("Failed to refresh bot memory for %s: %v", email, err)
+	}
+}
+
+// GetBotMemory returns what the bots remember about the authenticated user
+func GetBotMemory(c *gin.Context) {
+	token := c.GetHeader("Authorization")
+	if token == "" {
+		c.JSON(401, gin.H{"error": "Authorization token required"})
+		return
+	}
+
+	token = strings.TrimPrefix(token, "Bearer ")
+	valid, email, err := utils.ValidateTokenAndFetchEmail("./config/config.prod.yml", token, c)
+	if err != nil || !valid {
+		c.JSON(401, gin.H{"error": "Invalid or expired token"})
+		return
+	}
+
+	profile, err := services.GetBotOpponentProfile(email)
+	if err != nil {
+		c.JSON(500, gin.H{"error": "Failed to load bot memory: " + err.Error()})
+		return
+	}
+	if profile == nil {
+		c.JSON(200, gin.H{"profile": nil})
+		return
+	}
+	c.JSON(200, gin.H{"profile": profile})
+}
+
 // updateGamificationAfterBotDebate updates user score, checks for badges, and updates streaks after a bot debate
 func updateGamificationAfterBotDebate(userID primitive.ObjectID, resultStatus, topic string) {
 	// Add recover to catch any panics
@@ -593,6 +659,7 @@ func ConcedeDebate(c *gin.Context) {
 		historyToSave,
 		nil,
 	)
+	go refreshBotMemory(user.ID, email)
 
 	// Update gamification (score, badges, streaks)
 	// Call synchronously but with recover to prevent panics
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"arguehub/middlewares"
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EvidencePackRequest is the payload admins upload to add evidence for a topic
+type EvidencePackRequest struct {
+	Topic       string                   `json:"topic" binding:"required"`
+	Title       string                   `json:"title"`
+	Description string                   `json:"description"`
+	Tags        []string                 `json:"tags"`
+	Passages    []models.EvidencePassage `json:"passages" binding:"required"`
+}
+
+// CreateEvidencePack uploads a topic evidence pack into the bot retrieval index
+func CreateEvidencePack(ctx *gin.Context) {
+	var req EvidencePackRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request", "message": err.Error()})
+		return
+	}
+
+	pack := models.EvidencePack{
+		Topic:       req.Topic,
+		Title:       req.Title,
+		Description: req.Description,
+		Tags:        req.Tags,
+		Passages:    req.Passages,
+	}
+	if adminID, exists := ctx.Get("adminID"); exists {
+		pack.CreatedBy = adminID.(primitive.ObjectID)
+	}
+
+	if err := services.CreateEvidencePack(&pack); err != nil {
+		status := http.StatusInternalServerError
+		if strings.HasPrefix(err.Error(), "invalid evidence pack") {
+			status = http.StatusBadRequest
+		}
+		ctx.JSON(status, gin.H{"error": "Failed to create evidence pack", "message": err.Error()})
+		return
+	}
+
+	middlewares.LogAdminAction(ctx, "create_evidence_pack", "evidence", pack.ID, map[string]interface{}{
+		"topic":    pack.Topic,
+		"passages": len(pack.Passages),
+	})
+
+	ctx.JSON(http.StatusCreated, gin.H{"pack": pack})
+}
+
+// GetEvidencePacks lists uploaded evidence packs, optionally filtered by topic
+func GetEvidencePacks(ctx *gin.Context) {
+	packs, err := services.ListEvidencePacks(ctx.Query("topic"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch evidence packs", "message": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, gin.H{"packs": packs, "total": len(packs)})
+}
+
+// DeleteEvidencePack removes an evidence pack from storage and the retrieval index
+func DeleteEvidencePack(ctx *gin.Context) {
+	objID, err := primitive.ObjectIDFromHex(ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid evidence pack ID"})
+		return
+	}
+
+	if err := services.DeleteEvidencePack(objID); err != nil {
+		status := http.StatusInternalServerError
+		if err.Error() == "evidence pack not found" {
+			status = http.StatusNotFound
+		}
+		ctx.JSON(status, gin.H{"error": "Failed to delete evidence pack", "message": err.Error()})
+		return
+	}
+
+	middlewares.LogAdminAction(ctx, "delete_evidence_pack", "evidence", objID, nil)
+
+	ctx.JSON(http.StatusOK, gin.H{"message": "Evidence pack deleted successfully"})
+}
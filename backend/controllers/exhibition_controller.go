@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StartExhibitionRequest describes a bot-vs-bot exhibition to run
+type StartExhibitionRequest struct {
+	Topic      string `json:"topic" binding:"required"`
+	ForBot     string `json:"forBot" binding:"required"`
+	AgainstBot string `json:"againstBot" binding:"required"`
+	Format     string `json:"format"`
+}
+
+// StartExhibition starts a bot-vs-bot exhibition hosted by the current user
+func StartExhibition(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	email := c.GetString("email")
+
+	var req StartExhibitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		return
+	}
+
+	exhibition, err := services.StartExhibition(userID.(primitive.ObjectID), email, req.Topic, req.ForBot, req.AgainstBot, req.Format)
+	if err != nil {
+		status := http.StatusBadRequest
+		if strings.Contains(err.Error(), "unavailable") {
+			status = http.StatusServiceUnavailable
+		} else if strings.Contains(err.Error(), "already have") {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"exhibition":  exhibition,
+		"spectateUrl": "/ws/debate/" + exhibition.ID.Hex(),
+	})
+}
+
+// GetExhibitions lists recent exhibitions, optionally filtered by status
+func GetExhibitions(c *gin.Context) {
+	limit, _ := strconv.ParseInt(c.DefaultQuery("limit", "20"), 10, 64)
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	exhibitions, err := services.ListExhibitions(c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch exhibitions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"exhibitions": exhibitions, "formats": services.ExhibitionFormats()})
+}
+
+// GetExhibition returns a single exhibition including its transcript so far
+func GetExhibition(c *gin.Context) {
+	exhibitionID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exhibition ID"})
+		return
+	}
+
+	exhibition, err := services.GetExhibition(exhibitionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exhibition not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"exhibition": exhibition})
+}
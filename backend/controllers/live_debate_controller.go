@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"arguehub/middlewares"
+	"arguehub/services"
+	"arguehub/websocket"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetLiveDebates lists the debates in progress that spectators can watch. Query parameters filter
+// by type, topic or debater (q), phase, rating range and featured, and pick the sort order.
+// The 1v1 and team rooms listed, and each debate's spectator count, come from the instance serving
+// the request, so behind a load balancer they are per instance until room state is shared.
+func GetLiveDebates(c *gin.Context) {
+	filter := services.LiveDebateFilter{
+		Type:         c.Query("type"),
+		Query:        c.Query("q"),
+		Phase:        c.Query("phase"),
+		FeaturedOnly: c.Query("featured") == "true",
+		Sort:         c.Query("sort"),
+	}
+	var err error
+	if value := c.Query("minRating"); value != "" {
+		if filter.MinRating, err = strconv.ParseFloat(value, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid minRating"})
+			return
+		}
+	}
+	if value := c.Query("maxRating"); value != "" {
+		if filter.MaxRating, err = strconv.ParseFloat(value, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maxRating"})
+			return
+		}
+	}
+	if value := c.Query("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit"})
+			return
+		}
+	}
+	if err := filter.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	debates, err := websocket.LiveDebates()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load live debates"})
+		return
+	}
+	featured, err := services.ListFeaturedDebates(false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load featured debates"})
+		return
+	}
+	services.AttachFeaturedDebates(debates, featured)
+
+	c.JSON(http.StatusOK, gin.H{"debates": services.FilterLiveDebates(debates, filter), "live": len(debates)})
+}
+
+// GetFeaturedDebates lists the featured debates, expired ones included
+func GetFeaturedDebates(c *gin.Context) {
+	featured, err := services.ListFeaturedDebates(true)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load featured debates"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"featured": featured})
+}
+
+// FeatureDebate features a debate at the top of the live directory
+func FeatureDebate(c *gin.Context) {
+	adminID, exists := c.Get("adminID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+
+	var req struct {
+		DebateID      string `json:"debateId" binding:"required"`
+		Note          string `json:"note"`
+		Priority      int    `json:"priority"`
+		DurationHours int    `json:"durationHours"` // 0 to keep it featured until removed
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	featured, err := services.FeatureDebate(req.DebateID, req.Note, req.Priority,
+		time.Duration(req.DurationHours)*time.Hour, adminID.(primitive.ObjectID))
+	if errors.Is(err, services.ErrInvalidFeaturedDebate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to feature debate"})
+		return
+	}
+
+	middlewares.LogAdminAction(c, "feature_debate", "featured_debate", featured.ID, map[string]interface{}{
+		"debateId":      featured.DebateID,
+		"priority":      featured.Priority,
+		"durationHours": req.DurationHours,
+	})
+	c.JSON(http.StatusOK, gin.H{"featured": featured})
+}
+
+// UnfeatureDebate removes a debate from the featured list
+func UnfeatureDebate(c *gin.Context) {
+	id, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid featured debate ID"})
+		return
+	}
+
+	featured, err := services.UnfeatureDebate(id)
+	if errors.Is(err, services.ErrFeaturedDebateNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove featured debate"})
+		return
+	}
+
+	middlewares.LogAdminAction(c, "unfeature_debate", "featured_debate", featured.ID, map[string]interface{}{
+		"debateId": featured.DebateID,
+	})
+	c.JSON(http.StatusOK, gin.H{"message": "Debate removed from featured"})
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetReactionHeatmap returns a debate's crowd heatmap: spectator reactions per second, lined up with
+// the debate's phases and speeches
+func GetReactionHeatmap(c *gin.Context) {
+	heatmap, err := services.GetReactionHeatmap(c.Param("debateID"))
+	if errors.Is(err, services.ErrNoReactionTimeline) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load reactions"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"heatmap": heatmap})
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"arguehub/middlewares"
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A debate's neutral host (see services.IsNeutralDebateHost) moderates its spectator chat under
+// /debates/:debateID/chat, admins and moderators any debate's under /admin/spectator-chat/:debateID.
+// Both are written to the admin action log.
+
+// GetSpectatorChat returns a debate's latest spectator chat messages. Hosts also see deleted ones.
+func GetSpectatorChat(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	debateID := c.Param("debateID")
+
+	isHost, err := services.IsDebateHost(debateID, userID.(primitive.ObjectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load debate"})
+		return
+	}
+	listSpectatorChat(c, isHost)
+}
+
+// DeleteSpectatorChatMessage removes a spectator chat message (neutral debate host only)
+func DeleteSpectatorChatMessage(c *gin.Context) {
+	if moderator, ok := hostChatModerator(c); ok {
+		deleteSpectatorChatMessage(c, moderator)
+	}
+}
+
+// GetSpectatorSanctions lists a debate's mutes and bans (neutral debate host only)
+func GetSpectatorSanctions(c *gin.Context) {
+	if _, ok := hostChatModerator(c); ok {
+		listSpectatorSanctions(c)
+	}
+}
+
+// SanctionSpectator mutes or bans a spectator (neutral debate host only)
+func SanctionSpectator(c *gin.Context) {
+	if moderator, ok := hostChatModerator(c); ok {
+		sanctionSpectator(c, moderator)
+	}
+}
+
+// LiftSpectatorSanction ends a mute or ban early (neutral debate host only)
+func LiftSpectatorSanction(c *gin.Context) {
+	if moderator, ok := hostChatModerator(c); ok {
+		liftSpectatorSanction(c, moderator)
+	}
+}
+
+// AdminGetSpectatorChat returns a debate's latest spectator chat messages, deleted ones included
+func AdminGetSpectatorChat(c *gin.Context) {
+	listSpectatorChat(c, true)
+}
+
+// AdminDeleteSpectatorChatMessage removes a spectator chat message
+func AdminDeleteSpectatorChatMessage(c *gin.Context) {
+	if moderator, ok := adminChatModerator(c); ok {
+		deleteSpectatorChatMessage(c, moderator)
+	}
+}
+
+// AdminGetSpectatorSanctions lists a debate's mutes and bans
+func AdminGetSpectatorSanctions(c *gin.Context) {
+	listSpectatorSanctions(c)
+}
+
+// AdminSanctionSpectator mutes or bans a spectator
+func AdminSanctionSpectator(c *gin.Context) {
+	if moderator, ok := adminChatModerator(c); ok {
+		sanctionSpectator(c, moderator)
+	}
+}
+
+// AdminLiftSpectatorSanction ends a mute or ban early
+func AdminLiftSpectatorSanction(c *gin.Context) {
+	if moderator, ok := adminChatModerator(c); ok {
+		liftSpectatorSanction(c, moderator)
+	}
+}
+
+func hostChatModerator(c *gin.Context) (services.ChatModerator, bool) {
+	userID, ok := requireNeutralDebateHost(c, "Only the debate's host can moderate the chat")
+	return services.ChatModerator{ID: userID, Role: "host"}, ok
+}
+
+func adminChatModerator(c *gin.Context) (services.ChatModerator, bool) {
+	adminID, exists := c.Get("adminID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return services.ChatModerator{}, false
+	}
+	role, _ := c.Get("adminRole")
+	roleName, _ := role.(string)
+	return services.ChatModerator{ID: adminID.(primitive.ObjectID), Role: roleName}, true
+}
+
+func listSpectatorChat(c *gin.Context, includeDeleted bool) {
+	messages, err := services.ListSpectatorChatMessages(c.Param("debateID"), includeDeleted)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load chat"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"messages": messages, "isModerator": includeDeleted})
+}
+
+func deleteSpectatorChatMessage(c *gin.Context, moderator services.ChatModerator) {
+	messageID, err := primitive.ObjectIDFromHex(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message ID"})
+		return
+	}
+
+	debateID := c.Param("debateID")
+	message, err := services.DeleteSpectatorChatMessage(debateID, messageID, moderator)
+	if errors.Is(err, services.ErrSpectatorChatMessageNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete message"})
+		return
+	}
+
+	middlewares.LogAdminAction(c, "delete_spectator_chat_message", "spectator_chat", message.ID, map[string]interface{}{
+		"debateId":      debateID,
+		"moderatorRole": moderator.Role,
+		"spectatorHash": message.SpectatorHash,
+		"content":       message.Content,
+	})
+	c.JSON(http.StatusOK, gin.H{"message": message})
+}
+
+func listSpectatorSanctions(c *gin.Context) {
+	sanctions, err := services.ListSpectatorSanctions(c.Param("debateID"), c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load mutes and bans"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sanctions": sanctions})
+}
+
+func sanctionSpectator(c *gin.Context, moderator services.ChatModerator) {
+	var req services.SpectatorChatSanctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	debateID := c.Param("debateID")
+	sanction, err := services.SanctionSpectator(debateID, req, moderator)
+	if errors.Is(err, services.ErrInvalidChatSanction) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrSpectatorChatMessageNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save the mute or ban"})
+		return
+	}
+
+	action := "mute_spectator"
+	if sanction.Kind == models.SpectatorChatBan {
+		action = "ban_spectator"
+	}
+	middlewares.LogAdminAction(c, action, "spectator_chat", sanction.ID, map[string]interface{}{
+		"debateId":        debateID,
+		"moderatorRole":   moderator.Role,
+		"spectatorHash":   sanction.SpectatorHash,
+		"userId":          sanction.UserID,
+		"reason":          sanction.Reason,
+		"durationSeconds": req.DurationSeconds,
+	})
+	c.JSON(http.StatusCreated, gin.H{"sanction": sanction})
+}
+
+func liftSpectatorSanction(c *gin.Context, moderator services.ChatModerator) {
+	sanctionID, err := primitive.ObjectIDFromHex(c.Param("sanctionId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sanction ID"})
+		return
+	}
+
+	debateID := c.Param("debateID")
+	sanction, err := services.LiftSpectatorSanction(debateID, sanctionID, moderator)
+	if errors.Is(err, services.ErrSpectatorChatSanctionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to lift the mute or ban"})
+		return
+	}
+
+	middlewares.LogAdminAction(c, "lift_spectator_sanction", "spectator_chat", sanction.ID, map[string]interface{}{
+		"debateId":      debateID,
+		"moderatorRole": moderator.Role,
+		"kind":          sanction.Kind,
+		"spectatorHash": sanction.SpectatorHash,
+	})
+	c.JSON(http.StatusOK, gin.H{"sanction": sanction})
+}
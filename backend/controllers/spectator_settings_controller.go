@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// GetSpectatorSettings returns what spectators of a debate need to do before they can vote or ask.
+// Only hosts see the chat's blocked words.
+func GetSpectatorSettings(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+		return
+	}
+	debateID := c.Param("debateID")
+
+	settings, err := services.GetSpectatorSettings(debateID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load spectator settings"})
+		return
+	}
+	isHost, err := services.IsDebateHost(debateID, userID.(primitive.ObjectID))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load debate"})
+		return
+	}
+	if !isHost {
+		settings = settings.ForSpectators()
+	}
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
+
+// UpdateSpectatorSettings changes a debate's spectator settings (debate hosts only)
+func UpdateSpectatorSettings(c *gin.Context) {
+	userID, ok := requireDebateHost(c, "Only the debate's hosts can change spectator settings")
+	if !ok {
+		return
+	}
+
+	var req services.SpectatorSettingsUpdate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	settings, err := services.UpdateSpectatorSettings(c.Param("debateID"), req, userID)
+	if errors.Is(err, services.ErrInvalidSpectatorSettings) || errors.Is(err, services.ErrInvalidChatSlowMode) ||
+		errors.Is(err, services.ErrInvalidChatBlockedWords) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update spectator settings"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"settings": settings})
+}
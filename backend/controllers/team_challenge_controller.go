@@ -0,0 +1,142 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateTeamChallenge challenges another team to a debate at a set time (captain or co-captain)
+func CreateTeamChallenge(c *gin.Context) {
+	team, userID, ok := loadTeamForUser(c, services.TeamPermLeadDebate, "Only the captain or a co-captain can challenge other teams")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		OpponentTeamID primitive.ObjectID `json:"opponentTeamId" binding:"required"`
+		Topic          string             `json:"topic" binding:"required"`
+		Format         string             `json:"format"`
+		ScheduledAt    time.Time          `json:"scheduledAt" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	challenge, err := services.CreateTeamChallenge(*team, userID, req.OpponentTeamID, req.Topic, req.Format, req.ScheduledAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, challenge)
+}
+
+// ListTeamChallenges returns the challenges a team sent or received; pass ?all=true to include closed ones
+func ListTeamChallenges(c *gin.Context) {
+	team, _, ok := loadTeamForUser(c, services.TeamPermViewPrep, "Only team members can see the team's challenges")
+	if !ok {
+		return
+	}
+
+	challenges, err := services.ListTeamChallenges(team.ID, c.Query("all") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve challenges"})
+		return
+	}
+	c.JSON(http.StatusOK, challenges)
+}
+
+// loadChallengeForLead loads the challenge from the route and returns the caller's side of it,
+// writing an error response unless the caller leads one of the two teams
+func loadChallengeForLead(c *gin.Context) (*models.TeamChallenge, primitive.ObjectID, bool) {
+	challengeID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid challenge ID"})
+		return nil, primitive.NilObjectID, false
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	challenge, err := services.GetTeamChallenge(challengeID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Challenge not found"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	for _, teamID := range []primitive.ObjectID{challenge.ChallengerTeamID, challenge.OpponentTeamID} {
+		var team models.Team
+		if err := db.GetCollection("teams").FindOne(context.Background(), bson.M{"_id": teamID}).Decode(&team); err != nil {
+			continue
+		}
+		if services.TeamCan(team, userID.(primitive.ObjectID), services.TeamPermLeadDebate) {
+			return challenge, teamID, true
+		}
+	}
+	c.JSON(http.StatusForbidden, gin.H{"error": "Only the captains and co-captains of the two teams can answer this challenge"})
+	return nil, primitive.NilObjectID, false
+}
+
+// respondToChallenge writes the outcome of a challenge action
+func respondToChallenge(c *gin.Context, challenge *models.TeamChallenge, err error) {
+	if err != nil {
+		if errors.Is(err, services.ErrChallengeNotFound) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, challenge)
+}
+
+// AcceptTeamChallenge accepts the time proposed by the other team
+func AcceptTeamChallenge(c *gin.Context) {
+	challenge, teamID, ok := loadChallengeForLead(c)
+	if !ok {
+		return
+	}
+	accepted, err := services.AcceptTeamChallenge(challenge.ID, teamID)
+	respondToChallenge(c, accepted, err)
+}
+
+// DeclineTeamChallenge declines a challenge, or cancels it when the challenging team does it
+func DeclineTeamChallenge(c *gin.Context) {
+	challenge, teamID, ok := loadChallengeForLead(c)
+	if !ok {
+		return
+	}
+	declined, err := services.DeclineTeamChallenge(challenge.ID, teamID)
+	respondToChallenge(c, declined, err)
+}
+
+// RescheduleTeamChallenge proposes a new time for a challenge
+func RescheduleTeamChallenge(c *gin.Context) {
+	challenge, teamID, ok := loadChallengeForLead(c)
+	if !ok {
+		return
+	}
+
+	var req struct {
+		ScheduledAt time.Time `json:"scheduledAt" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rescheduled, err := services.RescheduleTeamChallenge(challenge.ID, teamID, req.ScheduledAt)
+	respondToChallenge(c, rescheduled, err)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	cryptoRand "crypto/rand"
+	"fmt"
 	"math/big"
 	"net/http"
 	"strings"
@@ -129,9 +130,9 @@ func UpdateTeamSize(c *gin.Context) {
 		return
 	}
 
-	// Validate maxSize (only 2 or 4 allowed)
-	if updateData.MaxSize != 2 && updateData.MaxSize != 4 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Max size must be either 2 or 4"})
+	// Validate maxSize
+	if !services.ValidTeamSize(updateData.MaxSize) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Max size must be between %d and %d", services.MinTeamSize, services.MaxTeamSize)})
 		return
 	}
 
@@ -177,7 +178,16 @@ func UpdateTeamSize(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Team size updated successfully"})
+	// A queued team is re-checked against its new size
+	inMatchmaking := services.RefreshTeamMatchmaking(objectID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Team size updated successfully",
+		"maxSize":       updateData.MaxSize,
+		"minimumRoster": services.MinimumRoster(updateData.MaxSize),
+		"formats":       services.TeamFormatsFor(updateData.MaxSize),
+		"inMatchmaking": inMatchmaking,
+	})
 }
 
 // CreateTeam creates a new team
@@ -222,12 +232,9 @@ func CreateTeam(c *gin.Context) {
 	team.CreatedAt = time.Now()
 	team.UpdatedAt = time.Now()
 
-	// Set default maxSize if not provided (only 2 or 4)
-	if team.MaxSize == 0 {
-		team.MaxSize = 4 // Default to 4 members
-	}
-	if team.MaxSize != 2 && team.MaxSize != 4 {
-		team.MaxSize = 4 // Force to 4 if invalid
+	// Set default maxSize if not provided or invalid
+	if !services.ValidTeamSize(team.MaxSize) {
+		team.MaxSize = services.MaxTeamSize
 	}
 
 	// Add captain as first member
@@ -249,6 +256,10 @@ func CreateTeam(c *gin.Context) {
 
 	// Insert team into database
 	result, err := collection.InsertOne(context.Background(), team)
+	if mongo.IsDuplicateKeyError(err) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are already in a team. Leave your current team before creating a new one."})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create team"})
 		return
@@ -282,6 +293,27 @@ func GetTeam(c *gin.Context) {
 	c.JSON(http.StatusOK, team)
 }
 
+// GetTeamStats returns a team's record, rating history, best speakers and head-to-head results
+func GetTeamStats(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	stats, err := services.GetTeamStats(objectID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute team statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
 // JoinTeam allows a user to join a team
 func JoinTeam(c *gin.Context) {
 	teamID := c.Param("id")
@@ -382,15 +414,10 @@ func JoinTeam(c *gin.Context) {
 		return
 	}
 
-	// Notify team captain
+	// Notify the team
 	go func() {
-		services.CreateNotification(
-			team.CaptainID,
-			models.NotificationTypeSystem,
-			"New Team Member",
-			user.DisplayName+" has joined your team "+team.Name,
-			"/team/"+teamID,
-		)
+		services.CancelUserJoinRequests(newMember.UserID)
+		services.NotifyTeam(team, newMember.UserID, "New Team Member", user.DisplayName+" has joined your team "+team.Name)
 	}()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully joined team"})
@@ -453,11 +480,20 @@ func LeaveTeam(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to leave team"})
 		return
 	}
+	services.RefreshTeamMatchmaking(objectID)
+
+	go func() {
+		for _, member := range team.Members {
+			if member.UserID == userID.(primitive.ObjectID) {
+				services.NotifyTeam(team, member.UserID, "Member Left", member.DisplayName+" has left "+team.Name)
+			}
+		}
+	}()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Successfully left team"})
 }
 
-// GetUserTeams retrieves all teams a user is part of
+// GetUserTeams retrieves all teams a user is part of, as a member or a coach
 func GetUserTeams(c *gin.Context) {
 	// Get user from context
 	userID, exists := c.Get("userID")
@@ -468,7 +504,10 @@ func GetUserTeams(c *gin.Context) {
 
 	collection := db.GetCollection("teams")
 	cursor, err := collection.Find(context.Background(), bson.M{
-		"members.userId": userID.(primitive.ObjectID),
+		"$or": []bson.M{
+			{"members.userId": userID.(primitive.ObjectID)},
+			{"coaches.userId": userID.(primitive.ObjectID)},
+		},
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve teams"})
@@ -485,7 +524,8 @@ func GetUserTeams(c *gin.Context) {
 	c.JSON(http.StatusOK, teams)
 }
 
-// RemoveMember removes a member from a team (captain only)
+// RemoveMember removes a member from a team. The captain can remove anyone else; co-captains can
+// remove regular members only.
 func RemoveMember(c *gin.Context) {
 	teamID := c.Param("teamId")
 	objectID, err := primitive.ObjectIDFromHex(teamID)
@@ -517,9 +557,13 @@ func RemoveMember(c *gin.Context) {
 		return
 	}
 
-	// Check if user is captain
-	if team.CaptainID != userID.(primitive.ObjectID) {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Only the captain can remove members"})
+	// Check if user may remove this member
+	if !services.TeamCan(team, userID.(primitive.ObjectID), services.TeamPermManageMembers) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the captain or a co-captain can remove members"})
+		return
+	}
+	if team.RoleOf(memberObjectID) == models.TeamRoleCoCaptain && !services.TeamCan(team, userID.(primitive.ObjectID), services.TeamPermManageTeam) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the captain can remove a co-captain"})
 		return
 	}
 
@@ -555,6 +599,22 @@ func RemoveMember(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove member"})
 		return
 	}
+	services.RefreshTeamMatchmaking(objectID)
+
+	go func() {
+		for _, member := range team.Members {
+			if member.UserID == memberObjectID {
+				services.CreateNotification(
+					memberObjectID,
+					models.NotificationTypeSystem,
+					"Removed from Team",
+					"You were removed from "+team.Name,
+					"/team/"+teamID,
+				)
+				services.NotifyTeam(team, memberObjectID, "Member Removed", member.DisplayName+" was removed from "+team.Name)
+			}
+		}
+	}()
 
 	c.JSON(http.StatusOK, gin.H{"message": "Member removed successfully"})
 }
@@ -597,6 +657,8 @@ func DeleteTeam(c *gin.Context) {
 		return
 	}
 
+	go services.NotifyTeam(team, team.CaptainID, "Team Deleted", team.Name+" was deleted by its captain")
+
 	c.JSON(http.StatusOK, gin.H{"message": "Team deleted successfully"})
 }
 
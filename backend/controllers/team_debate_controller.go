@@ -2,8 +2,8 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"net/http"
-	"time"
 
 	"arguehub/db"
 	"arguehub/models"
@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // CreateTeamDebate creates a new team debate between two matched teams
@@ -20,6 +21,7 @@ func CreateTeamDebate(c *gin.Context) {
 		Team1ID primitive.ObjectID `json:"team1Id" binding:"required"`
 		Team2ID primitive.ObjectID `json:"team2Id" binding:"required"`
 		Topic   string             `json:"topic" binding:"required"`
+		Format  string             `json:"format"` // "standard" (default) or "lightning"
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -44,53 +46,12 @@ func CreateTeamDebate(c *gin.Context) {
 		return
 	}
 
-	if req.Team1ID == req.Team2ID {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Cannot create a debate with the same team on both sides"})
-		return
-	}
-
-	// Determine stances
-	var team1Stance, team2Stance string
-	stances := []string{"for", "against"}
-	if time.Now().Unix()%2 == 0 {
-		team1Stance = stances[0]
-		team2Stance = stances[1]
-	} else {
-		team1Stance = stances[1]
-		team2Stance = stances[0]
-	}
-
-	// Create debate
-	debate := models.TeamDebate{
-		Team1ID:      req.Team1ID,
-		Team2ID:      req.Team2ID,
-		Team1Name:    team1.Name,
-		Team2Name:    team2.Name,
-		Team1Members: team1.Members,
-		Team2Members: team2.Members,
-		Topic:        req.Topic,
-		Team1Stance:  team1Stance,
-		Team2Stance:  team2Stance,
-		Status:       "active",
-		CurrentTurn:  "team1",
-		TurnCount:    0,
-		MaxTurns:     12, // 12 total turns (6 per team)
-		Team1Elo:     team1.AverageElo,
-		Team2Elo:     team2.AverageElo,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
-	}
-
-	// Insert debate
-	debateCollection := db.GetCollection("team_debates")
-	result, err := debateCollection.InsertOne(context.Background(), debate)
+	debate, err := services.CreateTeamDebate(team1, team2, req.Topic, req.Format)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create debate"})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	debate.ID = result.InsertedID.(primitive.ObjectID)
-
 	// Remove teams from matchmaking
 	services.RemoveFromMatchmaking(req.Team1ID)
 	services.RemoveFromMatchmaking(req.Team2ID)
@@ -132,6 +93,71 @@ func GetTeamDebate(c *gin.Context) {
 	c.JSON(http.StatusOK, debate)
 }
 
+// GetTeamDebateState returns the turn state of a team debate
+func GetTeamDebateState(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid debate ID"})
+		return
+	}
+
+	state, err := services.GetTeamDebateState(objectID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found"})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
+// UpdateTeamDebatePhase moves a team debate on to its next phase. Only a captain or co-captain of
+// either team may do so, and the change must be based on the current state version; otherwise it
+// is refused with the current state.
+func UpdateTeamDebatePhase(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid debate ID"})
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req struct {
+		Phase   string `json:"phase" binding:"required"`
+		Version *int   `json:"version" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := services.LeadTeamDebatePhase(objectID, userID.(primitive.ObjectID), *req.Version, req.Phase)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found"})
+			return
+		}
+		if errors.Is(err, services.ErrNotTeamDebateLead) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrTeamDebateStateConflict) || errors.Is(err, services.ErrTeamDebateNotActive) ||
+			errors.Is(err, services.ErrTeamDebatePhaseOutOfOrder) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "state": state})
+			return
+		}
+		if errors.Is(err, services.ErrUnknownTeamDebatePhase) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update debate state"})
+		return
+	}
+	c.JSON(http.StatusOK, state)
+}
+
 // GetActiveTeamDebate gets the active debate for a team
 func GetActiveTeamDebate(c *gin.Context) {
 	teamID := c.Param("teamId")
@@ -163,3 +189,192 @@ func GetActiveTeamDebate(c *gin.Context) {
 		"status":          debate.Status,
 	})
 }
+
+// CreateTeamBotDebate starts a debate between the caller's team and a team of bot personalities
+func CreateTeamBotDebate(c *gin.Context) {
+	var req struct {
+		TeamID primitive.ObjectID `json:"teamId" binding:"required"`
+		Topic  string             `json:"topic" binding:"required"`
+		Bots   []string           `json:"bots" binding:"required"`
+		Stance string             `json:"stance"` // "for", "against" or empty for a random side
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var team models.Team
+	err := db.GetCollection("teams").FindOne(context.Background(), bson.M{"_id": req.TeamID}).Decode(&team)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	if team.CaptainID != userID.(primitive.ObjectID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the captain can start a debate against bots"})
+		return
+	}
+
+	debate, err := services.CreateTeamBotDebate(team, req.Topic, req.Bots, req.Stance)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	services.RemoveFromMatchmaking(req.TeamID)
+
+	go func() {
+		for _, member := range team.Members {
+			services.CreateNotification(
+				member.UserID,
+				models.NotificationTypeTournament,
+				"Team Debate Started",
+				"Your team debate against "+debate.Team2Name+" on '"+debate.Topic+"' has started!",
+				"/team-debate/"+debate.ID.Hex(),
+			)
+		}
+	}()
+
+	c.JSON(http.StatusOK, debate)
+}
+
+// CompleteTeamDebate judges a team debate once it has reached its finished phase and returns its
+// outcome; only members may trigger it
+func CompleteTeamDebate(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid debate ID"})
+		return
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var debate models.TeamDebate
+	err = db.GetCollection("team_debates").FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&debate)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found"})
+		return
+	}
+
+	isMember := false
+	for _, member := range append(debate.Team1Members, debate.Team2Members...) {
+		if member.UserID == userID.(primitive.ObjectID) {
+			isMember = true
+			break
+		}
+	}
+	if !isMember {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only debate participants can complete the debate"})
+		return
+	}
+
+	completed, err := services.CompleteTeamDebate(objectID)
+	if errors.Is(err, services.ErrTeamDebateNotFinished) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to complete debate"})
+		return
+	}
+
+	c.JSON(http.StatusOK, completed)
+}
+
+// loadDebateTeam loads a team debate and the team the caller plays for or coaches in it, writing
+// an error response when the caller is neither
+func loadDebateTeam(c *gin.Context) (*models.TeamDebate, primitive.ObjectID, bool) {
+	objectID, err := primitive.ObjectIDFromHex(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid debate ID"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	var debate models.TeamDebate
+	err = db.GetCollection("team_debates").FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&debate)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Debate not found"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	teamID, ok := services.DebateTeamOf(debate, userID.(primitive.ObjectID))
+	if !ok {
+		// Coaches can follow their team's prep without debating
+		teamID, ok = services.CoachedDebateTeam(debate, userID.(primitive.ObjectID))
+	}
+	if !ok {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only debate participants and their coaches can access a team's prep room"})
+		return nil, primitive.NilObjectID, false
+	}
+	return &debate, teamID, true
+}
+
+// GetTeamPrepRoom returns the caller's team prep room for a debate, with recent prep messages
+func GetTeamPrepRoom(c *gin.Context) {
+	debate, teamID, ok := loadDebateTeam(c)
+	if !ok {
+		return
+	}
+
+	prep, err := services.GetTeamPrepRoom(debate.ID, teamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load prep room"})
+		return
+	}
+	messages, err := services.GetTeamPrepMessages(debate.ID, teamID, 200)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load prep messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"prep": prep, "messages": messages})
+}
+
+// GetTeamDebateReview returns a finished debate together with the caller's team notes, pinned
+// arguments and prep messages
+func GetTeamDebateReview(c *gin.Context) {
+	debate, teamID, ok := loadDebateTeam(c)
+	if !ok {
+		return
+	}
+	if debate.Status != "finished" {
+		c.JSON(http.StatusConflict, gin.H{"error": "The review is available once the debate has finished"})
+		return
+	}
+
+	prep, err := services.GetTeamPrepRoom(debate.ID, teamID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load prep room"})
+		return
+	}
+	messages, err := services.GetTeamPrepMessages(debate.ID, teamID, 500)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load prep messages"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"debate":   debate,
+		"teamId":   teamID.Hex(),
+		"notes":    prep.Notes,
+		"pinned":   prep.Pinned,
+		"messages": messages,
+	})
+}
@@ -2,6 +2,8 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -47,14 +49,19 @@ func JoinMatchmaking(c *gin.Context) {
 		return
 	}
 
-	// Check if team is full
-	if len(team.Members) < team.MaxSize {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Team is not full yet"})
+	// Teams may queue one member short of full
+	minimumRoster := services.MinimumRoster(team.MaxSize)
+	if len(team.Members) < minimumRoster {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Team needs at least %d members to join matchmaking", minimumRoster)})
 		return
 	}
 
 	// Add to matchmaking
 	err = services.StartTeamMatchmaking(objectID)
+	if errors.Is(err, services.ErrRosterTooSmall) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Team needs at least %d members to join matchmaking", minimumRoster)})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to join matchmaking"})
 		return
@@ -63,10 +70,12 @@ func JoinMatchmaking(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"message": "Team added to matchmaking pool",
 		"teamInfo": gin.H{
-			"id":           team.ID.Hex(),
-			"averageElo":   team.AverageElo,
-			"maxSize":      team.MaxSize,
-			"membersCount": len(team.Members),
+			"id":            team.ID.Hex(),
+			"averageElo":    team.AverageElo,
+			"maxSize":       team.MaxSize,
+			"membersCount":  len(team.Members),
+			"minimumRoster": minimumRoster,
+			"formats":       services.TeamFormatsFor(len(team.Members)),
 		},
 	})
 }
@@ -118,7 +127,8 @@ func GetMatchmakingPool(c *gin.Context) {
 			"captainId":    entry.Team.CaptainID.Hex(),
 			"maxSize":      entry.MaxSize,
 			"averageElo":   entry.AverageElo,
-			"membersCount": len(entry.Team.Members),
+			"membersCount": entry.RosterSize,
+			"formats":      entry.Formats,
 			"timestamp":    entry.Timestamp.Format("2006-01-02 15:04:05"),
 		})
 	}
@@ -0,0 +1,520 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// currentTeamMember builds a team member entry for the authenticated user
+func currentTeamMember(c *gin.Context) models.TeamMember {
+	userID, _ := c.Get("userID")
+	return models.TeamMember{
+		UserID:      userID.(primitive.ObjectID),
+		Email:       c.GetString("email"),
+		DisplayName: c.GetString("displayName"),
+		AvatarURL:   c.GetString("avatarUrl"),
+		Elo:         c.GetFloat64("rating"),
+	}
+}
+
+// teamIDParam returns the team ID route parameter. GET and POST team routes name it :id, while
+// PUT and DELETE routes name it :teamId.
+func teamIDParam(c *gin.Context) string {
+	if teamID := c.Param("teamId"); teamID != "" {
+		return teamID
+	}
+	return c.Param("id")
+}
+
+// loadTeamForUser loads the team from the team ID route parameter and checks the caller's role
+// grants the permission, writing an error response otherwise
+func loadTeamForUser(c *gin.Context, permission services.TeamPermission, forbidden string) (*models.Team, primitive.ObjectID, bool) {
+	objectID, err := primitive.ObjectIDFromHex(teamIDParam(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	var team models.Team
+	err = db.GetCollection("teams").FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&team)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return nil, primitive.NilObjectID, false
+	}
+
+	if !services.TeamCan(team, userID.(primitive.ObjectID), permission) {
+		c.JSON(http.StatusForbidden, gin.H{"error": forbidden})
+		return nil, primitive.NilObjectID, false
+	}
+	return &team, userID.(primitive.ObjectID), true
+}
+
+// CreateTeamInvitation creates an invitation link for the team (captain or co-captain)
+func CreateTeamInvitation(c *gin.Context) {
+	team, userID, ok := loadTeamForUser(c, services.TeamPermManageMembers, "Only the captain or a co-captain can invite people")
+	if !ok {
+		return
+	}
+
+	var req struct {
+		Role           string `json:"role"`
+		ExpiresInHours int    `json:"expiresInHours"`
+		MaxUses        int    `json:"maxUses"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	invitation, err := services.CreateTeamInvitation(*team, userID, req.Role, time.Duration(req.ExpiresInHours)*time.Hour, req.MaxUses)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"invitation": invitation,
+		"link":       "/team/invite/" + invitation.Token,
+	})
+}
+
+// ListTeamInvitations returns the team's invitations that can still be used (captain or co-captain)
+func ListTeamInvitations(c *gin.Context) {
+	team, _, ok := loadTeamForUser(c, services.TeamPermManageMembers, "Only the captain or a co-captain can see invitations")
+	if !ok {
+		return
+	}
+
+	invitations, err := services.ListTeamInvitations(team.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invitations"})
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// RevokeTeamInvitation stops an invitation link from working (captain or co-captain)
+func RevokeTeamInvitation(c *gin.Context) {
+	team, _, ok := loadTeamForUser(c, services.TeamPermManageMembers, "Only the captain or a co-captain can revoke invitations")
+	if !ok {
+		return
+	}
+
+	invitationID, err := primitive.ObjectIDFromHex(c.Param("invitationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invitation ID"})
+		return
+	}
+	if err := services.RevokeTeamInvitation(team.ID, invitationID); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Invitation not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke invitation"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation revoked"})
+}
+
+// GetTeamInvitation shows what an invitation link is for before it is accepted
+func GetTeamInvitation(c *gin.Context) {
+	invitation, err := services.GetTeamInvitation(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvitationInvalid) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"teamId":    invitation.TeamID.Hex(),
+		"teamName":  invitation.TeamName,
+		"role":      invitation.Role,
+		"expiresAt": invitation.ExpiresAt,
+	})
+}
+
+// AcceptTeamInvitation joins the caller to the team of an invitation link, as a member or a coach
+func AcceptTeamInvitation(c *gin.Context) {
+	if _, exists := c.Get("userID"); !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	invitation, err := services.ClaimTeamInvitation(c.Param("token"))
+	if err != nil {
+		if errors.Is(err, services.ErrInvitationInvalid) {
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to accept invitation"})
+		return
+	}
+
+	newMember := currentTeamMember(c)
+	var team *models.Team
+	if invitation.Role == models.TeamRoleCoach {
+		team, err = services.AddTeamCoach(invitation.TeamID, newMember)
+	} else {
+		team, err = services.AddTeamMember(invitation.TeamID, newMember)
+	}
+	if err != nil {
+		services.ReleaseTeamInvitation(invitation.ID)
+		switch {
+		case errors.Is(err, services.ErrAlreadyInTeam), errors.Is(err, services.ErrTeamFull):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		case errors.Is(err, mongo.ErrNoDocuments):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		default:
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		}
+		return
+	}
+
+	go func() {
+		if invitation.Role == models.TeamRoleCoach {
+			services.NotifyTeam(*team, newMember.UserID, "New Team Coach", newMember.DisplayName+" is now coaching "+team.Name)
+			return
+		}
+		services.CancelUserJoinRequests(newMember.UserID)
+		services.NotifyTeam(*team, newMember.UserID, "New Team Member", newMember.DisplayName+" has joined your team "+team.Name+" via an invitation")
+	}()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Invitation accepted", "teamId": invitation.TeamID.Hex(), "role": invitation.Role})
+}
+
+// RequestToJoinTeam asks the team's captain and co-captains to let the caller in
+func RequestToJoinTeam(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(teamIDParam(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	if _, exists := c.Get("userID"); !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	var req struct {
+		Message string `json:"message"`
+	}
+	// The message is optional, so an empty body is fine
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	collection := db.GetCollection("teams")
+	var team models.Team
+	if err := collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&team); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	requester := currentTeamMember(c)
+	if team.RoleOf(requester.UserID) != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are already part of this team"})
+		return
+	}
+	count, err := collection.CountDocuments(context.Background(), bson.M{"members.userId": requester.UserID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify existing team membership"})
+		return
+	}
+	if count > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "User is already in a team"})
+		return
+	}
+
+	message := strings.TrimSpace(req.Message)
+	if len(message) > 500 {
+		message = message[:500]
+	}
+	request, err := services.CreateTeamJoinRequest(models.TeamJoinRequest{
+		TeamID:      team.ID,
+		UserID:      requester.UserID,
+		Email:       requester.Email,
+		DisplayName: requester.DisplayName,
+		AvatarURL:   requester.AvatarURL,
+		Elo:         requester.Elo,
+		Message:     message,
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	go func() {
+		for _, member := range team.Members {
+			if services.TeamCan(team, member.UserID, services.TeamPermManageMembers) {
+				services.CreateNotification(
+					member.UserID,
+					models.NotificationTypeSystem,
+					"Join Request",
+					requester.DisplayName+" asked to join "+team.Name,
+					"/team/"+team.ID.Hex(),
+				)
+			}
+		}
+	}()
+
+	c.JSON(http.StatusCreated, request)
+}
+
+// ListTeamJoinRequests returns the team's pending join requests (captain or co-captain)
+func ListTeamJoinRequests(c *gin.Context) {
+	team, _, ok := loadTeamForUser(c, services.TeamPermManageMembers, "Only the captain or a co-captain can see join requests")
+	if !ok {
+		return
+	}
+
+	requests, err := services.ListTeamJoinRequests(team.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve join requests"})
+		return
+	}
+	c.JSON(http.StatusOK, requests)
+}
+
+// ApproveTeamJoinRequest adds the requester to the team (captain or co-captain)
+func ApproveTeamJoinRequest(c *gin.Context) {
+	decideTeamJoinRequest(c, "approved")
+}
+
+// RejectTeamJoinRequest turns a join request down (captain or co-captain)
+func RejectTeamJoinRequest(c *gin.Context) {
+	decideTeamJoinRequest(c, "rejected")
+}
+
+func decideTeamJoinRequest(c *gin.Context, status string) {
+	team, userID, ok := loadTeamForUser(c, services.TeamPermManageMembers, "Only the captain or a co-captain can decide join requests")
+	if !ok {
+		return
+	}
+
+	requestID, err := primitive.ObjectIDFromHex(c.Param("requestId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request ID"})
+		return
+	}
+
+	request, err := services.DecideTeamJoinRequest(team.ID, requestID, userID, status)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Join request not found or already decided"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update join request"})
+		return
+	}
+
+	if status == "rejected" {
+		go services.CreateNotification(
+			request.UserID,
+			models.NotificationTypeSystem,
+			"Join Request Declined",
+			"Your request to join "+team.Name+" was declined",
+			"/team/"+team.ID.Hex(),
+		)
+		c.JSON(http.StatusOK, request)
+		return
+	}
+
+	_, err = services.AddTeamMember(team.ID, models.TeamMember{
+		UserID:      request.UserID,
+		Email:       request.Email,
+		DisplayName: request.DisplayName,
+		AvatarURL:   request.AvatarURL,
+		Elo:         request.Elo,
+	})
+	if err != nil {
+		services.ReopenTeamJoinRequest(request.ID)
+		if errors.Is(err, services.ErrAlreadyInTeam) || errors.Is(err, services.ErrTeamFull) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add member"})
+		return
+	}
+
+	go func() {
+		services.CancelUserJoinRequests(request.UserID)
+		services.CreateNotification(
+			request.UserID,
+			models.NotificationTypeSystem,
+			"Join Request Approved",
+			"You are now a member of "+team.Name,
+			"/team/"+team.ID.Hex(),
+		)
+		services.NotifyTeam(*team, userID, "New Team Member", request.DisplayName+" has joined your team "+team.Name)
+	}()
+
+	c.JSON(http.StatusOK, request)
+}
+
+// UpdateMemberRole makes a member a co-captain, a regular member, or the new captain (captain only).
+// Handing over captaincy makes the previous captain a co-captain.
+func UpdateMemberRole(c *gin.Context) {
+	team, userID, ok := loadTeamForUser(c, services.TeamPermManageTeam, "Only the captain can change roles")
+	if !ok {
+		return
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(c.Param("memberId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid member ID"})
+		return
+	}
+
+	var req struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	current := team.RoleOf(memberID)
+	if current != models.TeamRoleMember && current != models.TeamRoleCoCaptain {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Roles can only be changed for team members other than the captain"})
+		return
+	}
+
+	var member models.TeamMember
+	for _, m := range team.Members {
+		if m.UserID == memberID {
+			member = m
+		}
+	}
+
+	collection := db.GetCollection("teams")
+	var update bson.M
+	switch req.Role {
+	case models.TeamRoleCoCaptain:
+		update = bson.M{"$set": bson.M{"members.$[m].role": models.TeamRoleCoCaptain, "updatedAt": time.Now()}}
+	case models.TeamRoleMember:
+		update = bson.M{"$unset": bson.M{"members.$[m].role": ""}, "$set": bson.M{"updatedAt": time.Now()}}
+	case models.TeamRoleCaptain:
+		_, err = collection.UpdateOne(context.Background(), bson.M{"_id": team.ID, "captainId": userID}, bson.M{
+			"$set": bson.M{
+				"captainId":           memberID,
+				"captainEmail":        member.Email,
+				"members.$[old].role": models.TeamRoleCoCaptain,
+				"updatedAt":           time.Now(),
+			},
+			"$unset": bson.M{"members.$[m].role": ""},
+		}, memberArrayFilters(bson.M{"m.userId": memberID}, bson.M{"old.userId": userID}))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to transfer captaincy"})
+			return
+		}
+		go services.NotifyTeam(*team, userID, "New Team Captain", member.DisplayName+" is now the captain of "+team.Name)
+		c.JSON(http.StatusOK, gin.H{"message": "Captaincy transferred", "captainId": memberID.Hex()})
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be captain, co_captain or member"})
+		return
+	}
+
+	_, err = collection.UpdateOne(context.Background(), bson.M{"_id": team.ID}, update, memberArrayFilters(bson.M{"m.userId": memberID}))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update role"})
+		return
+	}
+
+	go func() {
+		if req.Role == models.TeamRoleCoCaptain {
+			services.NotifyTeam(*team, userID, "New Co-Captain", member.DisplayName+" is now a co-captain of "+team.Name)
+			return
+		}
+		services.CreateNotification(
+			memberID,
+			models.NotificationTypeSystem,
+			"Role Changed",
+			"You are no longer a co-captain of "+team.Name,
+			"/team/"+team.ID.Hex(),
+		)
+	}()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Role updated", "role": req.Role})
+}
+
+// RemoveCoach removes a coach from the team (captain, co-captain, or the coach themselves)
+func RemoveCoach(c *gin.Context) {
+	objectID, err := primitive.ObjectIDFromHex(teamIDParam(c))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+	coachID, err := primitive.ObjectIDFromHex(c.Param("coachId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid coach ID"})
+		return
+	}
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	collection := db.GetCollection("teams")
+	var team models.Team
+	if err := collection.FindOne(context.Background(), bson.M{"_id": objectID}).Decode(&team); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+	if coachID != userID.(primitive.ObjectID) && !services.TeamCan(team, userID.(primitive.ObjectID), services.TeamPermManageMembers) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the captain or a co-captain can remove coaches"})
+		return
+	}
+	if team.RoleOf(coachID) != models.TeamRoleCoach {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Coach not found"})
+		return
+	}
+
+	_, err = collection.UpdateOne(context.Background(), bson.M{"_id": objectID}, bson.M{
+		"$pull": bson.M{"coaches": bson.M{"userId": coachID}},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to remove coach"})
+		return
+	}
+
+	go func() {
+		if coachID != userID.(primitive.ObjectID) {
+			services.CreateNotification(coachID, models.NotificationTypeSystem, "Removed as Coach",
+				"You are no longer coaching "+team.Name, "/team/"+team.ID.Hex())
+		}
+		services.NotifyTeam(team, coachID, "Coach Left", "A coach is no longer coaching "+team.Name)
+	}()
+
+	c.JSON(http.StatusOK, gin.H{"message": "Coach removed successfully"})
+}
+
+// memberArrayFilters builds update options that target team members through array filters
+func memberArrayFilters(filters ...interface{}) *options.UpdateOptions {
+	return options.Update().SetArrayFilters(options.ArrayFilters{Filters: filters})
+}
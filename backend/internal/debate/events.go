@@ -7,6 +7,7 @@ import (
 
 // Event represents a debate event published to Redis Stream
 type Event struct {
+	ID        string          `json:"id,omitempty"` // Stream entry ID, set when read back from the stream
 	Type      string          `json:"type"`
 	Payload   json.RawMessage `json:"payload"`
 	Timestamp int64           `json:"timestamp"`
@@ -18,6 +19,7 @@ type VotePayload struct {
 	Option        string `json:"option"`
 	SpectatorHash string `json:"spectatorHash"`
 	ClientEventID string `json:"clientEventId"`
+	Authenticated bool   `json:"authenticated"` // Cast by a logged-in spectator
 	Timestamp     int64  `json:"timestamp"`
 }
 
@@ -26,6 +28,7 @@ type CreatePollPayload struct {
 	PollID        string   `json:"pollId,omitempty"`
 	Question      string   `json:"question"`
 	Options       []string `json:"options"`
+	Duration      int      `json:"durationSeconds,omitempty"` // Closes the poll automatically after this many seconds
 	SpectatorHash string   `json:"spectatorHash,omitempty"`
 	Timestamp     int64    `json:"timestamp,omitempty"`
 }
@@ -35,17 +38,38 @@ type PollCreatedPayload struct {
 	PollID    string   `json:"pollId"`
 	Question  string   `json:"question"`
 	Options   []string `json:"options"`
+	ClosesAt  int64    `json:"closesAt,omitempty"`
 	Timestamp int64    `json:"timestamp"`
 }
 
+// PollClosedPayload represents a poll's final results when it closes
+type PollClosedPayload struct {
+	PollID              string           `json:"pollId"`
+	Counts              map[string]int64 `json:"counts"`
+	AuthenticatedCounts map[string]int64 `json:"authenticatedCounts"`
+	Voters              int64            `json:"voters"`
+	ClosedAt            int64            `json:"closedAt"`
+}
+
 // QuestionPayload represents a question event payload
 type QuestionPayload struct {
 	QID           string `json:"qId"`
 	Text          string `json:"text"`
 	SpectatorHash string `json:"spectatorHash"`
+	Upvotes       int    `json:"upvotes,omitempty"`
 	Timestamp     int64  `json:"timestamp"`
 }
 
+// QuestionUpdatePayload represents a change to a question in the moderated Q&A queue
+type QuestionUpdatePayload struct {
+	QID       string `json:"qId"`
+	Status    string `json:"status"`
+	Upvotes   int    `json:"upvotes"`
+	Pushed    bool   `json:"pushed"`
+	Answered  bool   `json:"answered"`
+	Timestamp int64  `json:"timestamp"`
+}
+
 // ReactionPayload represents a reaction event payload
 type ReactionPayload struct {
 	Reaction      string `json:"reaction"`
@@ -53,6 +77,36 @@ type ReactionPayload struct {
 	Timestamp     int64  `json:"timestamp"`
 }
 
+// PhasePayload represents a phase change in a live debate room
+type PhasePayload struct {
+	Phase     string `json:"phase"`
+	Side      string `json:"side,omitempty"`
+	TeamID    string `json:"teamId,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// SpeechPayload represents a finished speech in a live debate room
+type SpeechPayload struct {
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	TeamID    string `json:"teamId,omitempty"`
+	Side      string `json:"side,omitempty"`
+	Phase     string `json:"phase,omitempty"`
+	Text      string `json:"text"`
+	IsBot     bool   `json:"isBot,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// ChatPayload represents a chat message sent by a debater in a live debate room
+type ChatPayload struct {
+	UserID    string `json:"userId"`
+	Username  string `json:"username"`
+	TeamID    string `json:"teamId,omitempty"`
+	Phase     string `json:"phase,omitempty"`
+	Content   string `json:"content"`
+	Timestamp int64  `json:"timestamp"`
+}
+
 // PollSnapshotPayload represents a poll snapshot event payload
 type PollSnapshotPayload struct {
 	PollState   map[string]map[string]int64 `json:"pollState"`   // pollId -> option -> count
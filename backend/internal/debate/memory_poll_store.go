@@ -0,0 +1,206 @@
+package debate
+
+import (
+	"sync"
+	"time"
+)
+
+// memoryPolls is the poll store used when Redis isn't configured (single-instance mode)
+var memoryPolls = newMemoryPollStore()
+
+// memoryPollStore keeps poll state in process memory
+type memoryPollStore struct {
+	mu    sync.Mutex
+	polls map[string]map[string]*memoryPoll // debateID -> pollID -> poll
+	now   func() time.Time
+}
+
+type memoryPoll struct {
+	meta       PollMetadata
+	counts     map[string]int64
+	authCounts map[string]int64
+	voters     map[string]struct{}
+	expiresAt  time.Time // like the TTL on the Redis keys
+}
+
+func newMemoryPollStore() *memoryPollStore {
+	return &memoryPollStore{
+		polls: make(map[string]map[string]*memoryPoll),
+		now:   time.Now,
+	}
+}
+
+// CreatePoll creates a new poll with the provided question and options, or returns ErrPollExists
+// if the debate already has a poll with that ID. Polls are dropped after pollKeyTTL, like the
+// Redis keys.
+func (ms *memoryPollStore) CreatePoll(debateID string, poll PollMetadata) (string, error) {
+	poll, err := preparePoll(poll)
+	if err != nil {
+		return "", err
+	}
+
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := ms.now()
+	ms.pruneExpired(now)
+	if ms.poll(debateID, poll.PollID) != nil {
+		return "", ErrPollExists
+	}
+
+	stored := &memoryPoll{
+		meta:       poll,
+		counts:     make(map[string]int64, len(poll.Options)),
+		authCounts: make(map[string]int64),
+		voters:     make(map[string]struct{}),
+		expiresAt:  now.Add(pollKeyTTL),
+	}
+	for _, opt := range poll.Options {
+		stored.counts[opt] = 0
+	}
+
+	if ms.polls[debateID] == nil {
+		ms.polls[debateID] = make(map[string]*memoryPoll)
+	}
+	ms.polls[debateID][poll.PollID] = stored
+	return poll.PollID, nil
+}
+
+// ClosePoll stops a poll taking votes and returns whether this call closed it
+func (ms *memoryPollStore) ClosePoll(debateID, pollID string, closedAt time.Time) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	poll := ms.poll(debateID, pollID)
+	if poll == nil {
+		return false, ErrPollNotFound
+	}
+	if poll.meta.ClosedAt != 0 {
+		return false, nil
+	}
+	poll.meta.ClosedAt = closedAt.Unix()
+	poll.expiresAt = ms.now().Add(closedPollKeyTTL)
+	return true, nil
+}
+
+// Vote handles a vote request and returns whether it was successful. Like Redis, only polls that
+// were created take votes, and only for their own options.
+func (ms *memoryPollStore) Vote(debateID, pollID, option, spectatorHash string, authenticated bool) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := ms.now()
+	poll := ms.poll(debateID, pollID)
+	if poll == nil {
+		return false, ErrPollNotFound
+	}
+	if !poll.meta.acceptsVotes(now) {
+		return false, ErrPollClosed
+	}
+	if !poll.meta.hasOption(option) {
+		return false, ErrPollOption
+	}
+
+	if _, voted := poll.voters[spectatorHash]; voted {
+		// Duplicate vote
+		return false, nil
+	}
+	poll.voters[spectatorHash] = struct{}{}
+	poll.expiresAt = now.Add(pollKeyTTL)
+	poll.counts[option]++
+	if authenticated {
+		poll.authCounts[option]++
+	}
+	return true, nil
+}
+
+// GetPollState returns the current poll state for all polls in a debate
+func (ms *memoryPollStore) GetPollState(debateID string) (map[string]map[string]int64, map[string]int64, map[string]PollMetadata, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	now := ms.now()
+	polls := ms.polls[debateID]
+	pollState := make(map[string]map[string]int64, len(polls))
+	votersCount := make(map[string]int64, len(polls))
+	metadataMap := make(map[string]PollMetadata, len(polls))
+
+	for pollID, poll := range polls {
+		if now.After(poll.expiresAt) {
+			continue
+		}
+		pollState[pollID] = copyCounts(poll.counts)
+		votersCount[pollID] = int64(len(poll.voters))
+
+		meta := poll.meta
+		meta.Options = append([]string(nil), poll.meta.Options...)
+		if len(meta.Options) == 0 {
+			for option := range poll.counts {
+				meta.Options = append(meta.Options, option)
+			}
+		}
+		metadataMap[pollID] = meta
+	}
+
+	return pollState, votersCount, metadataMap, nil
+}
+
+// GetAuthenticatedCounts returns the votes logged-in spectators cast in each of a debate's polls
+func (ms *memoryPollStore) GetAuthenticatedCounts(debateID string, pollIDs []string) (map[string]map[string]int64, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	authCounts := make(map[string]map[string]int64, len(pollIDs))
+	for _, pollID := range pollIDs {
+		if poll := ms.poll(debateID, pollID); poll != nil {
+			authCounts[pollID] = copyCounts(poll.authCounts)
+		} else {
+			authCounts[pollID] = make(map[string]int64)
+		}
+	}
+	return authCounts, nil
+}
+
+// HasVoted checks if a spectator has already voted
+func (ms *memoryPollStore) HasVoted(debateID, pollID, spectatorHash string) (bool, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+
+	poll := ms.poll(debateID, pollID)
+	if poll == nil {
+		return false, nil
+	}
+	_, voted := poll.voters[spectatorHash]
+	return voted, nil
+}
+
+// poll returns a poll that hasn't expired. Callers hold ms.mu.
+func (ms *memoryPollStore) poll(debateID, pollID string) *memoryPoll {
+	poll := ms.polls[debateID][pollID]
+	if poll == nil || ms.now().After(poll.expiresAt) {
+		return nil
+	}
+	return poll
+}
+
+// pruneExpired drops expired polls, and debates left without any. Callers hold ms.mu.
+func (ms *memoryPollStore) pruneExpired(now time.Time) {
+	for debateID, polls := range ms.polls {
+		for pollID, poll := range polls {
+			if now.After(poll.expiresAt) {
+				delete(polls, pollID)
+			}
+		}
+		if len(polls) == 0 {
+			delete(ms.polls, debateID)
+		}
+	}
+}
+
+func copyCounts(counts map[string]int64) map[string]int64 {
+	copied := make(map[string]int64, len(counts))
+	for option, count := range counts {
+		copied[option] = count
+	}
+	return copied
+}
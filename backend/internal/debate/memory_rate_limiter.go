@@ -0,0 +1,197 @@
+package debate
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// memoryPruneThreshold is how many counters build up before expired ones are dropped
+const memoryPruneThreshold = 1000
+
+// memoryLimits is the rate limiter used when Redis isn't configured (single-instance mode)
+var memoryLimits = newMemoryRateLimiter()
+
+// memoryRateLimiter keeps rate limit counters in process memory
+type memoryRateLimiter struct {
+	mu         sync.Mutex
+	counters   map[string]*memoryCounter
+	anonVoters map[string]*memoryVoterSet
+	now        func() time.Time
+}
+
+type memoryCounter struct {
+	count     int
+	expiresAt time.Time
+}
+
+type memoryVoterSet struct {
+	voters    map[string]struct{}
+	expiresAt time.Time
+}
+
+func newMemoryRateLimiter() *memoryRateLimiter {
+	return &memoryRateLimiter{
+		counters:   make(map[string]*memoryCounter),
+		anonVoters: make(map[string]*memoryVoterSet),
+		now:        time.Now,
+	}
+}
+
+// CheckVoteRateLimit checks if spectator can vote (1 vote per poll)
+func (rl *memoryRateLimiter) CheckVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error) {
+	return checkVoteRateLimit(debateID, pollID, spectatorHash)
+}
+
+// CheckReactionRateLimit checks if spectator can send a reaction
+func (rl *memoryRateLimiter) CheckReactionRateLimit(debateID, spectatorHash string, config RateLimitConfig) (bool, error) {
+	key := fmt.Sprintf("rate:reaction:%s:%s", debateID, spectatorHash)
+	return rl.count(key) < config.MaxReactions, nil
+}
+
+// RecordReaction records a reaction for rate limiting
+func (rl *memoryRateLimiter) RecordReaction(debateID, spectatorHash string, config RateLimitConfig) error {
+	rl.increment(fmt.Sprintf("rate:reaction:%s:%s", debateID, spectatorHash), config.ReactionWindow)
+	return nil
+}
+
+// AllowAnonymousVote checks an anonymous spectator's vote against the limit on anonymous votes per
+// poll from one IP address, and counts it if it is allowed
+func (rl *memoryRateLimiter) AllowAnonymousVote(debateID, pollID, ipHash, spectatorHash string, maxPerIP int) (bool, error) {
+	key := fmt.Sprintf("rate:anonvote:%s:%s:%s", debateID, pollID, ipHash)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	set := rl.anonVoters[key]
+	if set == nil || now.After(set.expiresAt) {
+		set = &memoryVoterSet{voters: make(map[string]struct{}), expiresAt: now.Add(24 * time.Hour)}
+		rl.anonVoters[key] = set
+	}
+	if _, counted := set.voters[spectatorHash]; counted {
+		// Already counted against this IP address
+		return true, nil
+	}
+	if len(set.voters) >= maxPerIP {
+		return false, nil
+	}
+	set.voters[spectatorHash] = struct{}{}
+	return true, nil
+}
+
+// AllowQuestion counts a question against the limit for key (a spectator or an IP address) and
+// reports whether it is within the limit
+func (rl *memoryRateLimiter) AllowQuestion(debateID, key string, maxQuestions int, window time.Duration) (bool, error) {
+	counterKey := fmt.Sprintf("rate:question:%s:%s", debateID, key)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	counter := rl.counter(counterKey, window)
+	if counter.count >= maxQuestions {
+		return false, nil
+	}
+	counter.count++
+	return true, nil
+}
+
+// AcquireAnonymousViewer takes one of the anonymous viewer slots of an IP address for a debate,
+// or reports that they are all taken. Callers release the slot when the viewer leaves.
+func (rl *memoryRateLimiter) AcquireAnonymousViewer(debateID, ipHash string, maxPerIP int) (bool, error) {
+	key := fmt.Sprintf("rate:anonviewers:%s:%s", debateID, ipHash)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	counter := rl.counter(key, anonymousViewerTTL)
+	if counter.count >= maxPerIP {
+		return false, nil
+	}
+	counter.count++
+	counter.expiresAt = rl.now().Add(anonymousViewerTTL)
+	return true, nil
+}
+
+// ReleaseAnonymousViewer frees a slot taken by AcquireAnonymousViewer
+func (rl *memoryRateLimiter) ReleaseAnonymousViewer(debateID, ipHash string) error {
+	key := fmt.Sprintf("rate:anonviewers:%s:%s", debateID, ipHash)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	if counter := rl.counters[key]; counter != nil {
+		counter.count--
+		if counter.count <= 0 {
+			delete(rl.counters, key)
+		}
+	}
+	return nil
+}
+
+// AllowChatMessage lets a spectator send one chat message per interval. When they have to wait it
+// returns how long for.
+func (rl *memoryRateLimiter) AllowChatMessage(debateID, spectatorHash string, interval time.Duration) (bool, time.Duration, error) {
+	key := fmt.Sprintf("rate:chat:%s:%s", debateID, spectatorHash)
+
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	now := rl.now()
+	if counter := rl.counters[key]; counter != nil && !now.After(counter.expiresAt) {
+		return false, counter.expiresAt.Sub(now), nil
+	}
+	if len(rl.counters) >= memoryPruneThreshold {
+		rl.pruneExpired(now)
+	}
+	rl.counters[key] = &memoryCounter{count: 1, expiresAt: now.Add(interval)}
+	return true, 0, nil
+}
+
+// count returns a counter's value, or zero once its window has passed
+func (rl *memoryRateLimiter) count(key string) int {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	counter := rl.counters[key]
+	if counter == nil || rl.now().After(counter.expiresAt) {
+		return 0
+	}
+	return counter.count
+}
+
+// increment adds one to a counter, starting its window on the first increment like Redis EXPIRE
+func (rl *memoryRateLimiter) increment(key string, window time.Duration) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.counter(key, window).count++
+}
+
+// counter returns a live counter, starting a new window if it has none. Callers hold rl.mu.
+func (rl *memoryRateLimiter) counter(key string, window time.Duration) *memoryCounter {
+	now := rl.now()
+	counter := rl.counters[key]
+	if counter == nil || now.After(counter.expiresAt) {
+		if len(rl.counters) >= memoryPruneThreshold {
+			rl.pruneExpired(now)
+		}
+		counter = &memoryCounter{expiresAt: now.Add(window)}
+		rl.counters[key] = counter
+	}
+	return counter
+}
+
+// pruneExpired drops counters and voter sets whose window has passed. Callers hold rl.mu.
+func (rl *memoryRateLimiter) pruneExpired(now time.Time) {
+	for key, counter := range rl.counters {
+		if now.After(counter.expiresAt) {
+			delete(rl.counters, key)
+		}
+	}
+	for key, set := range rl.anonVoters {
+		if now.After(set.expiresAt) {
+			delete(rl.anonVoters, key)
+		}
+	}
+}
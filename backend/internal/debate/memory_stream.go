@@ -0,0 +1,169 @@
+package debate
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryStreamMaxLen bounds each in-memory stream, like MAXLEN on the Redis stream
+const memoryStreamMaxLen = 10000
+
+// memoryStreams holds debate event streams when Redis isn't configured (single-instance mode)
+var memoryStreams = &memoryStreamRegistry{streams: make(map[string]*memoryStream)}
+
+type memoryStreamRegistry struct {
+	mu      sync.Mutex
+	streams map[string]*memoryStream
+}
+
+// get returns a debate's stream, creating it if needed
+func (r *memoryStreamRegistry) get(debateID string) *memoryStream {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stream, exists := r.streams[debateID]
+	if !exists {
+		r.pruneIdle(time.Now())
+		stream = &memoryStream{notify: make(chan struct{}), lastAdded: time.Now()}
+		r.streams[debateID] = stream
+	}
+	return stream
+}
+
+// pruneIdle drops streams nothing has been added to for streamTTL, like the TTL on the Redis
+// stream. Callers hold r.mu.
+func (r *memoryStreamRegistry) pruneIdle(now time.Time) {
+	for debateID, stream := range r.streams {
+		stream.mu.Lock()
+		idle := now.Sub(stream.lastAdded) > streamTTL
+		stream.mu.Unlock()
+		if idle {
+			delete(r.streams, debateID)
+		}
+	}
+}
+
+// memoryStream is an append-only event log with Redis-style "<milliseconds>-<sequence>" IDs
+type memoryStream struct {
+	mu         sync.Mutex
+	entries    []memoryStreamEntry
+	lastMillis uint64
+	lastSeq    uint64
+	lastAdded  time.Time
+	notify     chan struct{} // closed and replaced whenever an entry is added
+}
+
+type memoryStreamEntry struct {
+	id   string
+	data string
+}
+
+// add appends a marshaled event and wakes up the stream's consumers
+func (s *memoryStream) add(data string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	millis := uint64(time.Now().UnixMilli())
+	if millis <= s.lastMillis {
+		millis = s.lastMillis
+		s.lastSeq++
+	} else {
+		s.lastSeq = 0
+	}
+	s.lastMillis = millis
+	s.lastAdded = time.Now()
+	id := fmt.Sprintf("%d-%d", millis, s.lastSeq)
+
+	s.entries = append(s.entries, memoryStreamEntry{id: id, data: data})
+	if len(s.entries) > memoryStreamMaxLen {
+		s.entries = append([]memoryStreamEntry(nil), s.entries[len(s.entries)-memoryStreamMaxLen:]...)
+	}
+
+	close(s.notify)
+	s.notify = make(chan struct{})
+	return id
+}
+
+// lastID returns the ID of the newest entry, or "" for an empty stream
+func (s *memoryStream) lastID() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.entries) == 0 {
+		return ""
+	}
+	return s.entries[len(s.entries)-1].id
+}
+
+// events returns the events added after the given ID ("" for all of them), oldest first
+func (s *memoryStream) events(after string) []*Event {
+	events, _ := s.eventsAfter(after)
+	return events
+}
+
+// eventsAfter returns the events added after the given ID, and a channel closed when more arrive
+func (s *memoryStream) eventsAfter(after string) ([]*Event, <-chan struct{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	start := 0
+	if after != "" {
+		start = sort.Search(len(s.entries), func(i int) bool {
+			return CompareEventIDs(s.entries[i].id, after) > 0
+		})
+	}
+
+	events := make([]*Event, 0, len(s.entries)-start)
+	for _, entry := range s.entries[start:] {
+		event, err := UnmarshalEvent(entry.data)
+		if err != nil {
+			continue
+		}
+		event.ID = entry.id
+		events = append(events, event)
+	}
+	return events, s.notify
+}
+
+// memoryStreamConsumer forwards a debate's in-memory stream to the hub
+type memoryStreamConsumer struct {
+	hub      DebateHub
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// StartConsumerGroup starts forwarding new events for a debate. Like a new Redis consumer group it
+// starts at the end of the stream.
+func (mc *memoryStreamConsumer) StartConsumerGroup(debateID string) error {
+	stream := memoryStreams.get(debateID)
+	runningConsumers.Add(1)
+	go mc.consumeLoop(debateID, stream, stream.lastID())
+	return nil
+}
+
+// Stop ends the consume loop
+func (mc *memoryStreamConsumer) Stop() {
+	mc.stopOnce.Do(func() { close(mc.done) })
+}
+
+func (mc *memoryStreamConsumer) consumeLoop(debateID string, stream *memoryStream, cursor string) {
+	defer runningConsumers.Add(-1)
+
+	for {
+		events, notify := stream.eventsAfter(cursor)
+		for _, event := range events {
+			mc.hub.BroadcastToDebate(debateID, event)
+			cursor = event.ID
+		}
+		if len(events) > 0 {
+			continue
+		}
+		select {
+		case <-notify:
+		case <-mc.done:
+			return
+		}
+	}
+}
@@ -0,0 +1,270 @@
+package debate
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMemoryPollStoreCountsEachSpectatorOnce(t *testing.T) {
+	store := newMemoryPollStore()
+	pollID, err := store.CreatePoll("d1", PollMetadata{Question: "Who is winning?", Options: []string{"For", "Against", " for "}})
+	if err != nil {
+		t.Fatalf("CreatePoll: %v", err)
+	}
+
+	if ok, _ := store.Vote("d1", pollID, "For", "alice", true); !ok {
+		t.Fatal("first vote should count")
+	}
+	if ok, _ := store.Vote("d1", pollID, "Against", "alice", true); ok {
+		t.Fatal("second vote from the same spectator should not count")
+	}
+	store.Vote("d1", pollID, "Against", "bob", false)
+
+	state, voters, meta, err := store.GetPollState("d1")
+	if err != nil {
+		t.Fatalf("GetPollState: %v", err)
+	}
+	if state[pollID]["For"] != 1 || state[pollID]["Against"] != 1 || voters[pollID] != 2 {
+		t.Errorf("got counts %v with %d voters, want one vote each from 2 voters", state[pollID], voters[pollID])
+	}
+	if len(meta[pollID].Options) != 2 {
+		t.Errorf("options = %v, want duplicates dropped", meta[pollID].Options)
+	}
+
+	auth, _ := store.GetAuthenticatedCounts("d1", []string{pollID})
+	if auth[pollID]["For"] != 1 || auth[pollID]["Against"] != 0 {
+		t.Errorf("authenticated counts = %v, want only the logged-in vote", auth[pollID])
+	}
+	if voted, _ := store.HasVoted("d1", pollID, "bob"); !voted {
+		t.Error("HasVoted should report bob's vote")
+	}
+}
+
+func TestMemoryPollStoreOnlyCountsRealOptions(t *testing.T) {
+	store := newMemoryPollStore()
+	pollID, _ := store.CreatePoll("d1", PollMetadata{Question: "Who is winning?", Options: []string{"For", "Against"}})
+
+	if _, err := store.Vote("d1", "made-up", "For", "alice", false); err != ErrPollNotFound {
+		t.Errorf("vote for an unknown poll: err = %v, want ErrPollNotFound", err)
+	}
+	if _, err := store.Vote("d1", pollID, "Neither", "alice", false); err != ErrPollOption {
+		t.Errorf("vote for an unknown option: err = %v, want ErrPollOption", err)
+	}
+
+	state, voters, _, _ := store.GetPollState("d1")
+	if len(state) != 1 || len(state[pollID]) != 2 || voters[pollID] != 0 {
+		t.Errorf("got polls %v with %d voters, want only the created poll and no votes", state, voters[pollID])
+	}
+	if ok, _ := store.Vote("d1", pollID, "For", "alice", false); !ok {
+		t.Error("a rejected vote should not use up the spectator's vote")
+	}
+}
+
+func TestMemoryPollStoreClosesPolls(t *testing.T) {
+	now := time.Now()
+	store := newMemoryPollStore()
+	store.now = func() time.Time { return now }
+
+	timed, _ := store.CreatePoll("d1", PollMetadata{Question: "Timed?", Options: []string{"Yes", "No"}, ClosesAt: now.Add(time.Minute).Unix()})
+	manual, _ := store.CreatePoll("d1", PollMetadata{Question: "Manual?", Options: []string{"Yes", "No"}})
+
+	if ok, err := store.Vote("d1", timed, "Yes", "alice", false); !ok || err != nil {
+		t.Fatalf("vote before the closing time = %v, %v; want counted", ok, err)
+	}
+	now = now.Add(2 * time.Minute)
+	if _, err := store.Vote("d1", timed, "Yes", "bob", false); err != ErrPollClosed {
+		t.Errorf("vote after the closing time returned %v, want ErrPollClosed", err)
+	}
+
+	if closed, _ := store.ClosePoll("d1", manual, now); !closed {
+		t.Fatal("first ClosePoll should close the poll")
+	}
+	if closed, _ := store.ClosePoll("d1", manual, now); closed {
+		t.Error("second ClosePoll should report the poll was already closed")
+	}
+	if _, err := store.Vote("d1", manual, "No", "carol", false); err != ErrPollClosed {
+		t.Errorf("vote in a closed poll returned %v, want ErrPollClosed", err)
+	}
+	if _, err := store.ClosePoll("d1", "missing", now); err != ErrPollNotFound {
+		t.Errorf("closing an unknown poll returned %v, want ErrPollNotFound", err)
+	}
+
+	now = now.Add(closedPollKeyTTL + time.Minute)
+	if state, _, _, _ := store.GetPollState("d1"); len(state) != 1 {
+		t.Errorf("got %d polls after the closed poll expired, want only the timed one", len(state))
+	}
+}
+
+func TestMemoryPollStoreKeepsExistingPolls(t *testing.T) {
+	now := time.Now()
+	store := newMemoryPollStore()
+	store.now = func() time.Time { return now }
+
+	pollID, _ := store.CreatePoll("d1", PollMetadata{PollID: "winner", Question: "Who wins?", Options: []string{"A", "B"}})
+	store.Vote("d1", pollID, "A", "alice", false)
+	store.ClosePoll("d1", pollID, now)
+
+	if _, err := store.CreatePoll("d1", PollMetadata{PollID: "winner", Question: "Again?", Options: []string{"A", "B"}}); err != ErrPollExists {
+		t.Fatalf("recreating an existing poll returned %v, want ErrPollExists", err)
+	}
+	state, _, meta, _ := store.GetPollState("d1")
+	if state[pollID]["A"] != 1 || meta[pollID].ClosedAt == 0 || meta[pollID].Question != "Who wins?" {
+		t.Errorf("existing poll was changed: counts %v, meta %+v", state[pollID], meta[pollID])
+	}
+
+	if _, err := store.CreatePoll("d2", PollMetadata{PollID: "winner", Question: "Who wins?", Options: []string{"A", "B"}}); err != nil {
+		t.Errorf("the same poll ID in another debate returned %v", err)
+	}
+	now = now.Add(closedPollKeyTTL + time.Minute)
+	if _, err := store.CreatePoll("d1", PollMetadata{PollID: "winner", Question: "Rematch?", Options: []string{"A", "B"}}); err != nil {
+		t.Errorf("reusing the ID of an expired poll returned %v", err)
+	}
+}
+
+func TestMemoryRateLimiterWindows(t *testing.T) {
+	now := time.Now()
+	rl := newMemoryRateLimiter()
+	rl.now = func() time.Time { return now }
+	config := DefaultRateLimitConfig()
+
+	if ok, _ := rl.AllowQuestion("d1", "alice", config.MaxQuestions, config.QuestionWindow); !ok {
+		t.Fatal("first question should be allowed")
+	}
+	if ok, _ := rl.AllowQuestion("d1", "alice", config.MaxQuestions, config.QuestionWindow); ok {
+		t.Fatal("second question inside the window should be refused")
+	}
+	now = now.Add(config.QuestionWindow + time.Second)
+	if ok, _ := rl.AllowQuestion("d1", "alice", config.MaxQuestions, config.QuestionWindow); !ok {
+		t.Fatal("question after the window should be allowed")
+	}
+
+	for _, spectator := range []string{"a", "b"} {
+		if ok, _ := rl.AllowAnonymousVote("d1", "p1", "ip", spectator, 2); !ok {
+			t.Fatalf("anonymous vote from %s should be allowed", spectator)
+		}
+	}
+	if ok, _ := rl.AllowAnonymousVote("d1", "p1", "ip", "c", 2); ok {
+		t.Error("third anonymous voter from one IP should be refused")
+	}
+	if ok, _ := rl.AllowAnonymousVote("d1", "p1", "ip", "a", 2); !ok {
+		t.Error("a voter already counted should still be allowed")
+	}
+
+	if ok, _, _ := rl.AllowChatMessage("d1", "alice", 10*time.Second); !ok {
+		t.Fatal("first chat message should be allowed")
+	}
+	now = now.Add(4 * time.Second)
+	if ok, wait, _ := rl.AllowChatMessage("d1", "alice", 10*time.Second); ok || wait != 6*time.Second {
+		t.Fatalf("chat message in slow mode: allowed %v, wait %v; want refused with 6s to wait", ok, wait)
+	}
+	now = now.Add(7 * time.Second)
+	if ok, _, _ := rl.AllowChatMessage("d1", "alice", 10*time.Second); !ok {
+		t.Error("chat message after the slow mode interval should be allowed")
+	}
+}
+
+func TestMemoryRateLimiterCountsConcurrentRequestsOnce(t *testing.T) {
+	rl := newMemoryRateLimiter()
+
+	var wg sync.WaitGroup
+	var questions, viewers atomic.Int32
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if ok, _ := rl.AllowQuestion("d1", "ip:a", 3, time.Minute); ok {
+				questions.Add(1)
+			}
+			if ok, _ := rl.AcquireAnonymousViewer("d1", "a", 5); ok {
+				viewers.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if questions.Load() != 3 || viewers.Load() != 5 {
+		t.Fatalf("allowed %d questions and %d viewers, want exactly 3 and 5", questions.Load(), viewers.Load())
+	}
+
+	rl.ReleaseAnonymousViewer("d1", "a")
+	if ok, _ := rl.AcquireAnonymousViewer("d1", "a", 5); !ok {
+		t.Error("a released viewer slot should be free again")
+	}
+	if ok, _ := rl.AcquireAnonymousViewer("d1", "b", 5); !ok {
+		t.Error("another IP address should have its own viewer slots")
+	}
+}
+
+type recordingHub struct {
+	events chan *Event
+}
+
+func (h *recordingHub) BroadcastToDebate(debateID string, event *Event) {
+	h.events <- event
+}
+
+func TestMemoryStreamDeliversNewEventsInOrder(t *testing.T) {
+	memoryStreams.mu.Lock()
+	delete(memoryStreams.streams, "memory-stream-test")
+	memoryStreams.mu.Unlock()
+
+	stream := memoryStreams.get("memory-stream-test")
+	old, _ := NewEvent("phase", PhasePayload{Phase: "openingFor"})
+	data, _ := MarshalEvent(old)
+	stream.add(data)
+
+	before := RunningConsumers()
+	hub := &recordingHub{events: make(chan *Event, 4)}
+	consumer := &memoryStreamConsumer{hub: hub, done: make(chan struct{})}
+	consumer.StartConsumerGroup("memory-stream-test")
+	defer waitForConsumers(t, before)
+	defer consumer.Stop()
+
+	for _, phase := range []string{"openingAgainst", "closingFor"} {
+		event, _ := NewEvent("phase", PhasePayload{Phase: phase})
+		if err := PublishEvent("memory-stream-test", event); err != nil {
+			t.Fatalf("PublishEvent: %v", err)
+		}
+	}
+
+	var ids []string
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-hub.events:
+			ids = append(ids, event.ID)
+		case <-time.After(time.Second):
+			t.Fatalf("got %d events, want 2 published after the consumer started", len(ids))
+		}
+	}
+	if CompareEventIDs(ids[0], ids[1]) >= 0 {
+		t.Errorf("event IDs %v are not increasing", ids)
+	}
+
+	if replay, _ := ReadStream("memory-stream-test"); len(replay) != 3 {
+		t.Errorf("ReadStream returned %d events, want all 3", len(replay))
+	}
+}
+
+func TestMemoryStreamConsumerStops(t *testing.T) {
+	before := RunningConsumers()
+	consumer := NewStreamConsumer(&recordingHub{events: make(chan *Event, 1)})
+	consumer.StartConsumerGroup("memory-stop-test")
+	if RunningConsumers() != before+1 {
+		t.Fatalf("running consumers = %d, want %d", RunningConsumers(), before+1)
+	}
+
+	consumer.Stop()
+	waitForConsumers(t, before)
+}
+
+func waitForConsumers(t *testing.T, want int64) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for RunningConsumers() != want {
+		if time.Now().After(deadline) {
+			t.Fatalf("running consumers = %d after Stop, want %d", RunningConsumers(), want)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -3,48 +3,80 @@ package debate
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
-// PollStore handles poll state operations in Redis
-type PollStore struct {
+const (
+	pollKeyTTL       = 48 * time.Hour // how long an open poll's keys live without activity
+	closedPollKeyTTL = 6 * time.Hour  // how long a closed poll stays in the store once its results are archived
+)
+
+var (
+	ErrPollNotFound = errors.New("poll not found")
+	ErrPollClosed   = errors.New("poll is closed")
+	ErrPollExists   = errors.New("a poll with this ID already exists")
+	ErrPollOption   = errors.New("not one of the poll's options")
+)
+
+// PollStore handles spectator poll state
+type PollStore interface {
+	CreatePoll(debateID string, poll PollMetadata) (string, error)
+	ClosePoll(debateID, pollID string, closedAt time.Time) (bool, error)
+	Vote(debateID, pollID, option, spectatorHash string, authenticated bool) (bool, error)
+	GetPollState(debateID string) (map[string]map[string]int64, map[string]int64, map[string]PollMetadata, error)
+	GetAuthenticatedCounts(debateID string, pollIDs []string) (map[string]map[string]int64, error)
+	HasVoted(debateID, pollID, spectatorHash string) (bool, error)
+}
+
+// redisPollStore keeps poll state in Redis, shared by every instance
+type redisPollStore struct {
 	rdb *redis.Client
 	ctx context.Context
 }
 
 // PollMetadata represents metadata about a poll
 type PollMetadata struct {
-	PollID   string   `json:"pollId"`
-	Question string   `json:"question"`
-	Options  []string `json:"options"`
+	PollID    string   `json:"pollId"`
+	Question  string   `json:"question"`
+	Options   []string `json:"options"`
+	CreatedBy string   `json:"createdBy,omitempty"` // User ID of the host who created the poll
+	CreatedAt int64    `json:"createdAt,omitempty"`
+	ClosesAt  int64    `json:"closesAt,omitempty"` // Unix time the poll closes by itself, 0 for never
+	ClosedAt  int64    `json:"closedAt,omitempty"`
 }
 
-// NewPollStore creates a new PollStore instance
-func NewPollStore() *PollStore {
-	return &PollStore{
-		rdb: GetRedisClient(),
-		ctx: GetContext(),
-	}
+// acceptsVotes reports whether the poll is still open at the given time
+func (m PollMetadata) acceptsVotes(now time.Time) bool {
+	return m.ClosedAt == 0 && (m.ClosesAt == 0 || now.Unix() < m.ClosesAt)
 }
 
-// CreatePoll creates a new poll with the provided question and options
-func (ps *PollStore) CreatePoll(debateID, pollID, question string, options []string) (string, error) {
-	if ps == nil || ps.rdb == nil {
-		return "", fmt.Errorf("Redis client not available")
+// hasOption reports whether option is one of the poll's options
+func (m PollMetadata) hasOption(option string) bool {
+	for _, opt := range m.Options {
+		if opt == option {
+			return true
+		}
 	}
+	return false
+}
 
-	question = strings.TrimSpace(question)
-	if question == "" {
-		return "", fmt.Errorf("question is required")
+// preparePoll trims the question, drops blank and duplicate options and fills in the poll ID and
+// creation time
+func preparePoll(poll PollMetadata) (PollMetadata, error) {
+	poll.Question = strings.TrimSpace(poll.Question)
+	if poll.Question == "" {
+		return poll, fmt.Errorf("question is required")
 	}
 
-	cleanOptions := make([]string, 0, len(options))
+	cleanOptions := make([]string, 0, len(poll.Options))
 	seen := make(map[string]struct{})
-	for _, opt := range options {
+	for _, opt := range poll.Options {
 		opt = strings.TrimSpace(opt)
 		if opt == "" {
 			continue
@@ -57,55 +89,149 @@ func (ps *PollStore) CreatePoll(debateID, pollID, question string, options []str
 	}
 
 	if len(cleanOptions) < 2 {
-		return "", fmt.Errorf("at least two unique options are required")
+		return poll, fmt.Errorf("at least two unique options are required")
 	}
+	poll.Options = cleanOptions
 
-	if pollID == "" {
-		pollID = uuid.NewString()
+	if poll.PollID == "" {
+		poll.PollID = uuid.NewString()
 	}
+	poll.CreatedAt = time.Now().Unix()
+	poll.ClosedAt = 0
+	return poll, nil
+}
 
-	countsKey := fmt.Sprintf("debate:%s:poll:%s:counts", debateID, pollID)
-	votersKey := fmt.Sprintf("debate:%s:poll:%s:voters", debateID, pollID)
-	metaKey := fmt.Sprintf("debate:%s:poll:%s:meta", debateID, pollID)
-	pollsKey := fmt.Sprintf("debate:%s:polls", debateID)
+// NewPollStore returns the Redis poll store, or the in-memory one when Redis isn't configured
+func NewPollStore() PollStore {
+	rdb := GetRedisClient()
+	if rdb == nil {
+		return memoryPolls
+	}
+	return &redisPollStore{
+		rdb: rdb,
+		ctx: GetContext(),
+	}
+}
 
-	metadata := PollMetadata{
-		PollID:   pollID,
-		Question: question,
-		Options:  cleanOptions,
+// pollKeys returns the Redis keys holding a poll's state
+func pollKeys(debateID, pollID string) (counts, authCounts, voters, meta, closed string) {
+	prefix := fmt.Sprintf("debate:%s:poll:%s", debateID, pollID)
+	return prefix + ":counts", prefix + ":counts:auth", prefix + ":voters", prefix + ":meta", prefix + ":closed"
+}
+
+// CreatePoll creates a new poll with the provided question and options, or returns ErrPollExists
+// if the debate already has a poll with that ID. Its keys expire after pollKeyTTL so abandoned
+// debates don't accumulate.
+func (ps *redisPollStore) CreatePoll(debateID string, poll PollMetadata) (string, error) {
+	if ps == nil || ps.rdb == nil {
+		return "", fmt.Errorf("Redis client not available")
 	}
-	metaBytes, err := json.Marshal(metadata)
+
+	poll, err := preparePoll(poll)
+	if err != nil {
+		return "", err
+	}
+
+	countsKey, authCountsKey, votersKey, metaKey, closedKey := pollKeys(debateID, poll.PollID)
+	pollsKey := fmt.Sprintf("debate:%s:polls", debateID)
+
+	metaBytes, err := json.Marshal(poll)
 	if err != nil {
 		return "", fmt.Errorf("failed to marshal poll metadata: %w", err)
 	}
 
+	// Claiming the metadata key first means an existing poll is never reset or reopened
+	created, err := ps.rdb.SetNX(ps.ctx, metaKey, metaBytes, pollKeyTTL).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to create poll: %w", err)
+	}
+	if !created {
+		return "", ErrPollExists
+	}
+
 	pipe := ps.rdb.TxPipeline()
 
 	// Initialize counts to zero for each option
-	countFields := make(map[string]interface{}, len(cleanOptions))
-	for _, opt := range cleanOptions {
+	countFields := make(map[string]interface{}, len(poll.Options))
+	for _, opt := range poll.Options {
 		countFields[opt] = 0
 	}
-	pipe.Del(ps.ctx, votersKey)
+	pipe.Del(ps.ctx, votersKey, authCountsKey, closedKey)
 	pipe.HSet(ps.ctx, countsKey, countFields)
-	pipe.Set(ps.ctx, metaKey, metaBytes, 0)
-	pipe.SAdd(ps.ctx, pollsKey, pollID)
+	pipe.Expire(ps.ctx, countsKey, pollKeyTTL)
+	pipe.SAdd(ps.ctx, pollsKey, poll.PollID)
+	pipe.Expire(ps.ctx, pollsKey, pollKeyTTL)
 
 	if _, err := pipe.Exec(ps.ctx); err != nil {
+		ps.rdb.Del(ps.ctx, metaKey)
 		return "", fmt.Errorf("failed to create poll: %w", err)
 	}
 
-	return pollID, nil
+	return poll.PollID, nil
 }
 
-// Vote handles a vote request and returns whether it was successful
-func (ps *PollStore) Vote(debateID, pollID, option, spectatorHash string) (bool, error) {
+// ClosePoll stops a poll taking votes and returns whether this call closed it. Its keys are kept
+// for closedPollKeyTTL, by which time the results have been archived.
+func (ps *redisPollStore) ClosePoll(debateID, pollID string, closedAt time.Time) (bool, error) {
 	if ps == nil || ps.rdb == nil {
 		return false, fmt.Errorf("Redis client not available")
 	}
 
-	votersKey := fmt.Sprintf("debate:%s:poll:%s:voters", debateID, pollID)
-	countsKey := fmt.Sprintf("debate:%s:poll:%s:counts", debateID, pollID)
+	countsKey, authCountsKey, votersKey, metaKey, closedKey := pollKeys(debateID, pollID)
+
+	exists, err := ps.rdb.Exists(ps.ctx, metaKey).Result()
+	if err != nil {
+		return false, err
+	}
+	if exists == 0 {
+		return false, ErrPollNotFound
+	}
+
+	closed, err := ps.rdb.SetNX(ps.ctx, closedKey, closedAt.Unix(), closedPollKeyTTL).Result()
+	if err != nil || !closed {
+		return false, err
+	}
+
+	pipe := ps.rdb.Pipeline()
+	for _, key := range []string{countsKey, authCountsKey, votersKey, metaKey} {
+		pipe.Expire(ps.ctx, key, closedPollKeyTTL)
+	}
+	pipe.Exec(ps.ctx)
+	return true, nil
+}
+
+// Vote handles a vote request and returns whether it was successful. Votes from logged-in
+// spectators are also counted separately. Only polls that were created take votes, and only for
+// their own options, so a vote never creates poll keys.
+func (ps *redisPollStore) Vote(debateID, pollID, option, spectatorHash string, authenticated bool) (bool, error) {
+	if ps == nil || ps.rdb == nil {
+		return false, fmt.Errorf("Redis client not available")
+	}
+
+	countsKey, authCountsKey, votersKey, metaKey, closedKey := pollKeys(debateID, pollID)
+
+	pipe := ps.rdb.Pipeline()
+	closedCmd := pipe.Exists(ps.ctx, closedKey)
+	metaCmd := pipe.Get(ps.ctx, metaKey)
+	pipe.Exec(ps.ctx)
+	metaBytes, err := metaCmd.Bytes()
+	if errors.Is(err, redis.Nil) {
+		return false, ErrPollNotFound
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to load poll: %w", err)
+	}
+	var meta PollMetadata
+	if err := json.Unmarshal(metaBytes, &meta); err != nil {
+		return false, fmt.Errorf("failed to decode poll metadata: %w", err)
+	}
+	// Closed polls, and timed polls past their closing time, don't take votes
+	if closedCmd.Val() > 0 || !meta.acceptsVotes(time.Now()) {
+		return false, ErrPollClosed
+	}
+	if !meta.hasOption(option) {
+		return false, ErrPollOption
+	}
 
 	// Check if spectator already voted (using SET)
 	added, err := ps.rdb.SAdd(ps.ctx, votersKey, spectatorHash).Result()
@@ -125,11 +251,44 @@ func (ps *PollStore) Vote(debateID, pollID, option, spectatorHash string) (bool,
 		return false, fmt.Errorf("failed to increment count: %w", err)
 	}
 
+	// The vote already counts, so failures here only skew the split or the expiry
+	pipe = ps.rdb.Pipeline()
+	if authenticated {
+		pipe.HIncrBy(ps.ctx, authCountsKey, option, 1)
+		pipe.Expire(ps.ctx, authCountsKey, pollKeyTTL)
+	}
+	pipe.Expire(ps.ctx, votersKey, pollKeyTTL)
+	pipe.Exec(ps.ctx)
+
 	return true, nil
 }
 
+// GetAuthenticatedCounts returns the votes logged-in spectators cast in each of a debate's polls
+func (ps *redisPollStore) GetAuthenticatedCounts(debateID string, pollIDs []string) (map[string]map[string]int64, error) {
+	if ps == nil || ps.rdb == nil {
+		return nil, fmt.Errorf("Redis client not available")
+	}
+
+	authCounts := make(map[string]map[string]int64, len(pollIDs))
+	for _, pollID := range pollIDs {
+		authCountsKey := fmt.Sprintf("debate:%s:poll:%s:counts:auth", debateID, pollID)
+		counts, err := ps.rdb.HGetAll(ps.ctx, authCountsKey).Result()
+		if err != nil {
+			return nil, err
+		}
+		authCounts[pollID] = make(map[string]int64, len(counts))
+		for option, countStr := range counts {
+			var count int64
+			if _, err := fmt.Sscanf(countStr, "%d", &count); err == nil {
+				authCounts[pollID][option] = count
+			}
+		}
+	}
+	return authCounts, nil
+}
+
 // GetPollState returns the current poll state for all polls in a debate
-func (ps *PollStore) GetPollState(debateID string) (map[string]map[string]int64, map[string]int64, map[string]PollMetadata, error) {
+func (ps *redisPollStore) GetPollState(debateID string) (map[string]map[string]int64, map[string]int64, map[string]PollMetadata, error) {
 	if ps == nil || ps.rdb == nil {
 		return nil, nil, nil, fmt.Errorf("Redis client not available")
 	}
@@ -178,9 +337,7 @@ func (ps *PollStore) GetPollState(debateID string) (map[string]map[string]int64,
 	metadataMap := make(map[string]PollMetadata)
 
 	for pollID := range pollIDs {
-		countsKey := fmt.Sprintf("debate:%s:poll:%s:counts", debateID, pollID)
-		metaKey := fmt.Sprintf("debate:%s:poll:%s:meta", debateID, pollID)
-		votersKey := fmt.Sprintf("debate:%s:poll:%s:voters", debateID, pollID)
+		countsKey, _, votersKey, metaKey, closedKey := pollKeys(debateID, pollID)
 
 		// Get counts
 		counts, err := ps.rdb.HGetAll(ps.ctx, countsKey).Result()
@@ -202,9 +359,19 @@ func (ps *PollStore) GetPollState(debateID string) (map[string]map[string]int64,
 
 		// Get metadata
 		metaStr, err := ps.rdb.Get(ps.ctx, metaKey).Result()
+		if err == redis.Nil && len(counts) == 0 {
+			// The poll's keys have expired
+			delete(pollState, pollID)
+			delete(votersCount, pollID)
+			ps.rdb.SRem(ps.ctx, pollsKey, pollID)
+			continue
+		}
 		if err == nil && metaStr != "" {
 			var meta PollMetadata
 			if err := json.Unmarshal([]byte(metaStr), &meta); err == nil {
+				if closedAt, err := ps.rdb.Get(ps.ctx, closedKey).Int64(); err == nil {
+					meta.ClosedAt = closedAt
+				}
 				if len(meta.Options) == 0 {
 					// fallback to counts keys if options missing
 					if counts != nil {
@@ -237,7 +404,7 @@ func (ps *PollStore) GetPollState(debateID string) (map[string]map[string]int64,
 }
 
 // HasVoted checks if a spectator has already voted
-func (ps *PollStore) HasVoted(debateID, pollID, spectatorHash string) (bool, error) {
+func (ps *redisPollStore) HasVoted(debateID, pollID, spectatorHash string) (bool, error) {
 	if ps == nil || ps.rdb == nil {
 		return false, fmt.Errorf("Redis client not available")
 	}
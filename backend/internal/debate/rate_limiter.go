@@ -9,15 +9,35 @@ import (
 )
 
 // RateLimiter handles rate limiting for spectator actions
-type RateLimiter struct {
+type RateLimiter interface {
+	CheckVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error)
+	CheckReactionRateLimit(debateID, spectatorHash string, config RateLimitConfig) (bool, error)
+	RecordReaction(debateID, spectatorHash string, config RateLimitConfig) error
+	AllowAnonymousVote(debateID, pollID, ipHash, spectatorHash string, maxPerIP int) (bool, error)
+	AllowQuestion(debateID, key string, maxQuestions int, window time.Duration) (bool, error)
+	AcquireAnonymousViewer(debateID, ipHash string, maxPerIP int) (bool, error)
+	ReleaseAnonymousViewer(debateID, ipHash string) error
+	AllowChatMessage(debateID, spectatorHash string, interval time.Duration) (bool, time.Duration, error)
+}
+
+// anonymousViewerTTL bounds how long a viewer slot outlives an instance that died without
+// releasing it
+const anonymousViewerTTL = 12 * time.Hour
+
+// redisRateLimiter keeps rate limit counters in Redis, shared by every instance
+type redisRateLimiter struct {
 	rdb *redis.Client
 	ctx context.Context
 }
 
-// NewRateLimiter creates a new RateLimiter instance
-func NewRateLimiter() *RateLimiter {
-	return &RateLimiter{
-		rdb: GetRedisClient(),
+// NewRateLimiter returns the Redis rate limiter, or the in-memory one when Redis isn't configured
+func NewRateLimiter() RateLimiter {
+	rdb := GetRedisClient()
+	if rdb == nil {
+		return memoryLimits
+	}
+	return &redisRateLimiter{
+		rdb: rdb,
 		ctx: GetContext(),
 	}
 }
@@ -43,8 +63,13 @@ func DefaultRateLimitConfig() RateLimitConfig {
 }
 
 // CheckVoteRateLimit checks if spectator can vote (1 vote per poll)
-func (rl *RateLimiter) CheckVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error) {
-	// This is handled by the voter SET in poll_store.go
+func (rl *redisRateLimiter) CheckVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error) {
+	return checkVoteRateLimit(debateID, pollID, spectatorHash)
+}
+
+// checkVoteRateLimit allows a spectator one vote per poll
+func checkVoteRateLimit(debateID, pollID, spectatorHash string) (bool, error) {
+	// This is handled by the poll store's voter set
 	// Return true if not already voted
 	store := NewPollStore()
 	hasVoted, err := store.HasVoted(debateID, pollID, spectatorHash)
@@ -54,37 +79,37 @@ func (rl *RateLimiter) CheckVoteRateLimit(debateID, pollID, spectatorHash string
 	return !hasVoted, nil
 }
 
-// CheckQuestionRateLimit checks if spectator can ask a question
-func (rl *RateLimiter) CheckQuestionRateLimit(debateID, spectatorHash string, config RateLimitConfig) (bool, error) {
+// CheckReactionRateLimit checks if spectator can send a reaction
+func (rl *redisRateLimiter) CheckReactionRateLimit(debateID, spectatorHash string, config RateLimitConfig) (bool, error) {
 	if rl == nil || rl.rdb == nil {
 		return false, fmt.Errorf("Redis client not available")
 	}
 
-	key := fmt.Sprintf("rate:question:%s:%s", debateID, spectatorHash)
+	key := fmt.Sprintf("rate:reaction:%s:%s", debateID, spectatorHash)
 
 	// Check current count
 	count, err := rl.rdb.Get(rl.ctx, key).Int()
 	if err == redis.Nil {
-		// First question, allow it
+		// First reaction, allow it
 		return true, nil
 	} else if err != nil {
 		return false, err
 	}
 
-	if count >= config.MaxQuestions {
+	if count >= config.MaxReactions {
 		return false, nil
 	}
 
 	return true, nil
 }
 
-// RecordQuestion records a question for rate limiting
-func (rl *RateLimiter) RecordQuestion(debateID, spectatorHash string, config RateLimitConfig) error {
+// RecordReaction records a reaction for rate limiting
+func (rl *redisRateLimiter) RecordReaction(debateID, spectatorHash string, config RateLimitConfig) error {
 	if rl == nil || rl.rdb == nil {
 		return fmt.Errorf("Redis client not available")
 	}
 
-	key := fmt.Sprintf("rate:question:%s:%s", debateID, spectatorHash)
+	key := fmt.Sprintf("rate:reaction:%s:%s", debateID, spectatorHash)
 
 	// Increment count
 	count, err := rl.rdb.Incr(rl.ctx, key).Result()
@@ -94,54 +119,122 @@ func (rl *RateLimiter) RecordQuestion(debateID, spectatorHash string, config Rat
 
 	// Set expiration if first time
 	if count == 1 {
-		rl.rdb.Expire(rl.ctx, key, config.QuestionWindow)
+		rl.rdb.Expire(rl.ctx, key, config.ReactionWindow)
 	}
 
 	return nil
 }
 
-// CheckReactionRateLimit checks if spectator can send a reaction
-func (rl *RateLimiter) CheckReactionRateLimit(debateID, spectatorHash string, config RateLimitConfig) (bool, error) {
+// AllowAnonymousVote checks an anonymous spectator's vote against the limit on anonymous votes per
+// poll from one IP address, and counts it if it is allowed
+func (rl *redisRateLimiter) AllowAnonymousVote(debateID, pollID, ipHash, spectatorHash string, maxPerIP int) (bool, error) {
 	if rl == nil || rl.rdb == nil {
 		return false, fmt.Errorf("Redis client not available")
 	}
 
-	key := fmt.Sprintf("rate:reaction:%s:%s", debateID, spectatorHash)
+	key := fmt.Sprintf("rate:anonvote:%s:%s:%s", debateID, pollID, ipHash)
 
-	// Check current count
-	count, err := rl.rdb.Get(rl.ctx, key).Int()
-	if err == redis.Nil {
-		// First reaction, allow it
-		return true, nil
-	} else if err != nil {
+	added, err := rl.rdb.SAdd(rl.ctx, key, spectatorHash).Result()
+	if err != nil {
 		return false, err
 	}
+	if added == 0 {
+		// Already counted against this IP address
+		return true, nil
+	}
+	rl.rdb.Expire(rl.ctx, key, 24*time.Hour)
 
-	if count >= config.MaxReactions {
+	count, err := rl.rdb.SCard(rl.ctx, key).Result()
+	if err != nil {
+		return false, err
+	}
+	if count > int64(maxPerIP) {
+		rl.rdb.SRem(rl.ctx, key, spectatorHash)
 		return false, nil
 	}
 
 	return true, nil
 }
 
-// RecordReaction records a reaction for rate limiting
-func (rl *RateLimiter) RecordReaction(debateID, spectatorHash string, config RateLimitConfig) error {
+// AllowQuestion counts a question against the limit for key (a spectator or an IP address) and
+// reports whether it is within the limit. Checking and counting is one INCR, so concurrent
+// questions can't both slip under the limit.
+func (rl *redisRateLimiter) AllowQuestion(debateID, key string, maxQuestions int, window time.Duration) (bool, error) {
 	if rl == nil || rl.rdb == nil {
-		return fmt.Errorf("Redis client not available")
+		return false, fmt.Errorf("Redis client not available")
 	}
 
-	key := fmt.Sprintf("rate:reaction:%s:%s", debateID, spectatorHash)
+	counterKey := fmt.Sprintf("rate:question:%s:%s", debateID, key)
+
+	count, err := rl.rdb.Incr(rl.ctx, counterKey).Result()
+	if err != nil {
+		return false, err
+	}
+	if count == 1 {
+		rl.rdb.Expire(rl.ctx, counterKey, window)
+	}
+	return count <= int64(maxQuestions), nil
+}
+
+// AcquireAnonymousViewer takes one of the anonymous viewer slots of an IP address for a debate,
+// or reports that they are all taken. Callers release the slot when the viewer leaves.
+func (rl *redisRateLimiter) AcquireAnonymousViewer(debateID, ipHash string, maxPerIP int) (bool, error) {
+	if rl == nil || rl.rdb == nil {
+		return false, fmt.Errorf("Redis client not available")
+	}
+
+	key := fmt.Sprintf("rate:anonviewers:%s:%s", debateID, ipHash)
 
-	// Increment count
 	count, err := rl.rdb.Incr(rl.ctx, key).Result()
 	if err != nil {
-		return err
+		return false, err
 	}
+	rl.rdb.Expire(rl.ctx, key, anonymousViewerTTL)
+	if count > int64(maxPerIP) {
+		rl.rdb.Decr(rl.ctx, key)
+		return false, nil
+	}
+	return true, nil
+}
 
-	// Set expiration if first time
-	if count == 1 {
-		rl.rdb.Expire(rl.ctx, key, config.ReactionWindow)
+// ReleaseAnonymousViewer frees a slot taken by AcquireAnonymousViewer
+func (rl *redisRateLimiter) ReleaseAnonymousViewer(debateID, ipHash string) error {
+	if rl == nil || rl.rdb == nil {
+		return fmt.Errorf("Redis client not available")
 	}
 
+	key := fmt.Sprintf("rate:anonviewers:%s:%s", debateID, ipHash)
+
+	count, err := rl.rdb.Decr(rl.ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if count <= 0 {
+		rl.rdb.Del(rl.ctx, key)
+	}
 	return nil
 }
+
+// AllowChatMessage lets a spectator send one chat message per interval. When they have to wait it
+// returns how long for.
+func (rl *redisRateLimiter) AllowChatMessage(debateID, spectatorHash string, interval time.Duration) (bool, time.Duration, error) {
+	if rl == nil || rl.rdb == nil {
+		return false, 0, fmt.Errorf("Redis client not available")
+	}
+
+	key := fmt.Sprintf("rate:chat:%s:%s", debateID, spectatorHash)
+
+	allowed, err := rl.rdb.SetNX(rl.ctx, key, 1, interval).Result()
+	if err != nil {
+		return false, 0, err
+	}
+	if allowed {
+		return true, 0, nil
+	}
+
+	wait, err := rl.rdb.PTTL(rl.ctx, key).Result()
+	if err != nil || wait < 0 {
+		wait = interval
+	}
+	return false, wait, nil
+}
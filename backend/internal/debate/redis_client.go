@@ -12,7 +12,8 @@ var (
 	ctx = context.Background()
 )
 
-// InitRedis initializes Redis client
+// InitRedis initializes Redis client. If Redis can't be reached the client is left unset and
+// spectator features run in memory (single-instance mode).
 func InitRedis(redisURL string, password string, db int) error {
 	opt := &redis.Options{
 		Addr:     redisURL,
@@ -20,18 +21,20 @@ func InitRedis(redisURL string, password string, db int) error {
 		DB:       db,
 	}
 
-	rdb = redis.NewClient(opt)
+	client := redis.NewClient(opt)
 
 	// Test connection
-	_, err := rdb.Ping(ctx).Result()
+	_, err := client.Ping(ctx).Result()
 	if err != nil {
+		client.Close()
 		return fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
+	rdb = client
 	return nil
 }
 
-// GetRedisClient returns the Redis client instance
+// GetRedisClient returns the Redis client instance, or nil when Redis isn't configured
 func GetRedisClient() *redis.Client {
 	return rdb
 }
@@ -4,8 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 )
 
@@ -14,20 +18,45 @@ type DebateHub interface {
 	BroadcastToDebate(debateID string, event *Event)
 }
 
-// StreamConsumer handles Redis Stream consumer group operations
-type StreamConsumer struct {
+// StreamConsumer forwards a debate's events to the hub's spectators until it is stopped
+type StreamConsumer interface {
+	StartConsumerGroup(debateID string) error
+	Stop()
+}
+
+// streamTTL is how long a debate's event stream is kept after its last event
+const streamTTL = 48 * time.Hour
+
+// reclaimInterval is how often a consumer looks for messages another consumer left unacknowledged
+const reclaimInterval = 30 * time.Second
+
+// runningConsumers counts consume loops that haven't exited yet
+var runningConsumers atomic.Int64
+
+// RunningConsumers returns how many stream consumers are running
+func RunningConsumers() int64 {
+	return runningConsumers.Load()
+}
+
+// redisStreamConsumer reads a debate's stream through a consumer group of its own. Each consumer
+// in a group gets a share of the events, so a group shared between instances would leave every
+// instance missing most of them; with a group per consumer, every instance sees every event.
+type redisStreamConsumer struct {
 	rdb          *redis.Client
 	ctx          context.Context
+	cancel       context.CancelFunc
 	consumerName string
 	instanceID   string
+	groupID      string // Unique to this consumer, so stopping it never removes another's group
 	hub          DebateHub
 }
 
-// NewStreamConsumer creates a new StreamConsumer instance
-func NewStreamConsumer(hub DebateHub) *StreamConsumer {
+// NewStreamConsumer returns a Redis Stream consumer, or one reading the in-memory stream when Redis
+// isn't configured
+func NewStreamConsumer(hub DebateHub) StreamConsumer {
 	rdb := GetRedisClient()
 	if rdb == nil {
-		return nil
+		return &memoryStreamConsumer{hub: hub, done: make(chan struct{})}
 	}
 
 	hostname, _ := os.Hostname()
@@ -35,40 +64,54 @@ func NewStreamConsumer(hub DebateHub) *StreamConsumer {
 	instanceID := fmt.Sprintf("%s-%d", hostname, pid)
 	consumerName := fmt.Sprintf("consumer-%s", instanceID)
 
-	return &StreamConsumer{
+	ctx, cancel := context.WithCancel(GetContext())
+	return &redisStreamConsumer{
 		rdb:          rdb,
-		ctx:          GetContext(),
+		ctx:          ctx,
+		cancel:       cancel,
 		consumerName: consumerName,
 		instanceID:   instanceID,
+		groupID:      fmt.Sprintf("%s-%s", instanceID, uuid.NewString()[:8]),
 		hub:          hub,
 	}
 }
 
-// StartConsumerGroup starts consuming from Redis Stream for a debate
-func (sc *StreamConsumer) StartConsumerGroup(debateID string) error {
+// StartConsumerGroup starts consuming from Redis Stream for a debate. The group is removed when
+// the consumer stops; groups left by instances that died go with the stream when it expires.
+func (sc *redisStreamConsumer) StartConsumerGroup(debateID string) error {
 	if sc == nil || sc.rdb == nil {
 		return fmt.Errorf("Redis client not available")
 	}
 
 	streamKey := fmt.Sprintf("debate:%s:events", debateID)
-	groupName := fmt.Sprintf("debate:%s:group", debateID)
+	groupName := fmt.Sprintf("debate:%s:group:%s", debateID, sc.groupID)
 
-	// Create consumer group if it doesn't exist
-	err := sc.rdb.XGroupCreateMkStream(sc.ctx, streamKey, groupName, "0").Err()
+	// Create consumer group if it doesn't exist. New groups start at the end of the stream;
+	// spectators get the earlier history through ReadStream when they join.
+	err := sc.rdb.XGroupCreateMkStream(sc.ctx, streamKey, groupName, "$").Err()
 	if err != nil && err.Error() != "BUSYGROUP Consumer Group name already exists" {
 		// Continue anyway, group might already exist
 	}
 
 	// Start consuming in a goroutine
+	runningConsumers.Add(1)
 	go sc.consumeLoop(debateID, streamKey, groupName)
 
 	return nil
 }
 
-// consumeLoop continuously reads from the stream and forwards to WebSocket clients
-func (sc *StreamConsumer) consumeLoop(debateID, streamKey, groupName string) {
+// Stop ends the consume loop, interrupting a blocked read
+func (sc *redisStreamConsumer) Stop() {
+	sc.cancel()
+}
+
+// consumeLoop reads from the stream and forwards to WebSocket clients until the consumer is stopped
+func (sc *redisStreamConsumer) consumeLoop(debateID, streamKey, groupName string) {
+	defer runningConsumers.Add(-1)
+	defer sc.rdb.XGroupDestroy(GetContext(), streamKey, groupName)
 
-	for {
+	lastReclaim := time.Now()
+	for sc.ctx.Err() == nil {
 		// Read from stream with consumer group
 		streams, err := sc.rdb.XReadGroup(sc.ctx, &redis.XReadGroupArgs{
 			Group:    groupName,
@@ -83,7 +126,10 @@ func (sc *StreamConsumer) consumeLoop(debateID, streamKey, groupName string) {
 				// No messages, continue
 				continue
 			}
-			time.Sleep(time.Second)
+			select {
+			case <-sc.ctx.Done():
+			case <-time.After(time.Second):
+			}
 			continue
 		}
 
@@ -102,12 +148,15 @@ func (sc *StreamConsumer) consumeLoop(debateID, streamKey, groupName string) {
 		}
 
 		// Handle pending messages (reclaim stalled messages)
-		go sc.reclaimPendingMessages(debateID, streamKey, groupName)
+		if time.Since(lastReclaim) >= reclaimInterval {
+			sc.reclaimPendingMessages(debateID, streamKey, groupName)
+			lastReclaim = time.Now()
+		}
 	}
 }
 
 // processMessage processes a stream message and forwards to WebSocket clients
-func (sc *StreamConsumer) processMessage(debateID string, message redis.XMessage) error {
+func (sc *redisStreamConsumer) processMessage(debateID string, message redis.XMessage) error {
 	// Extract event data from message
 	eventData, ok := message.Values["data"].(string)
 	if !ok {
@@ -119,6 +168,7 @@ func (sc *StreamConsumer) processMessage(debateID string, message redis.XMessage
 	if err != nil {
 		return fmt.Errorf("failed to unmarshal event: %w", err)
 	}
+	event.ID = message.ID
 
 	// Forward event to all connected WebSocket clients for this debate
 	// The BroadcastToDebate method will format it correctly
@@ -128,7 +178,7 @@ func (sc *StreamConsumer) processMessage(debateID string, message redis.XMessage
 }
 
 // reclaimPendingMessages reclaims pending messages that haven't been ACKed
-func (sc *StreamConsumer) reclaimPendingMessages(debateID, streamKey, groupName string) {
+func (sc *redisStreamConsumer) reclaimPendingMessages(debateID, streamKey, groupName string) {
 	// Check for pending messages older than 30 seconds
 	pending, err := sc.rdb.XPendingExt(sc.ctx, &redis.XPendingExtArgs{
 		Stream: streamKey,
@@ -165,35 +215,90 @@ func (sc *StreamConsumer) reclaimPendingMessages(debateID, streamKey, groupName
 	}
 }
 
-// PublishEvent publishes an event to the Redis Stream
+// PublishEvent publishes an event to the debate's stream
 func PublishEvent(debateID string, event *Event) error {
 	rdb := GetRedisClient()
-	if rdb == nil {
-		return fmt.Errorf("Redis client not available")
-	}
 	ctx := GetContext()
 
-	streamKey := fmt.Sprintf("debate:%s:events", debateID)
-
 	// Marshal event to JSON
 	eventData, err := MarshalEvent(event)
 	if err != nil {
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
-	// Add to stream with MAXLEN to bound history
-	_, err = rdb.XAdd(ctx, &redis.XAddArgs{
+	if rdb == nil {
+		memoryStreams.get(debateID).add(eventData)
+		return nil
+	}
+
+	streamKey := fmt.Sprintf("debate:%s:events", debateID)
+
+	// Add to stream with MAXLEN to bound history, and expire it once the debate goes quiet
+	pipe := rdb.Pipeline()
+	xadd := pipe.XAdd(ctx, &redis.XAddArgs{
 		Stream: streamKey,
 		Values: map[string]interface{}{
 			"data": eventData,
 		},
 		MaxLen: 10000,
 		Approx: true, // Use ~ for approximate trimming
-	}).Result()
+	})
+	pipe.Expire(ctx, streamKey, streamTTL)
+	pipe.Exec(ctx)
 
-	if err != nil {
+	if err := xadd.Err(); err != nil {
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
 
 	return nil
 }
+
+// ReadStream returns every event in a debate's stream, oldest first
+func ReadStream(debateID string) ([]*Event, error) {
+	rdb := GetRedisClient()
+	if rdb == nil {
+		return memoryStreams.get(debateID).events(""), nil
+	}
+
+	streamKey := fmt.Sprintf("debate:%s:events", debateID)
+	messages, err := rdb.XRange(GetContext(), streamKey, "-", "+").Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stream: %w", err)
+	}
+
+	events := make([]*Event, 0, len(messages))
+	for _, message := range messages {
+		eventData, ok := message.Values["data"].(string)
+		if !ok {
+			continue
+		}
+		event, err := UnmarshalEvent(eventData)
+		if err != nil {
+			continue
+		}
+		event.ID = message.ID
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// CompareEventIDs orders two stream entry IDs ("<milliseconds>-<sequence>"), returning -1, 0 or 1
+func CompareEventIDs(a, b string) int {
+	aMillis, aSeq := splitEventID(a)
+	bMillis, bSeq := splitEventID(b)
+	switch {
+	case aMillis < bMillis, aMillis == bMillis && aSeq < bSeq:
+		return -1
+	case aMillis == bMillis && aSeq == bSeq:
+		return 0
+	default:
+		return 1
+	}
+}
+
+func splitEventID(id string) (uint64, uint64) {
+	millis, seq, _ := strings.Cut(id, "-")
+	m, _ := strconv.ParseUint(millis, 10, 64)
+	s, _ := strconv.ParseUint(seq, 10, 64)
+	return m, s
+}
@@ -0,0 +1,209 @@
+package evidence
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// BM25 tuning parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Stance values a passage can be tagged with. Neutral passages can be used by either side.
+const (
+	StanceFor     = "for"
+	StanceAgainst = "against"
+	StanceNeutral = ""
+)
+
+// Document is a single retrievable evidence passage
+type Document struct {
+	PackID    string
+	PassageID string
+	Topic     string
+	Kind      string
+	Text      string
+	Source    string
+	URL       string
+	Stance    string
+}
+
+// Result is a document matched by a search, with its relevance score
+type Result struct {
+	Document
+	Score float64
+}
+
+type indexedDocument struct {
+	doc    Document
+	terms  map[string]int
+	length int
+}
+
+// Index is an in-memory BM25 index over evidence passages, grouped by pack
+type Index struct {
+	mu        sync.RWMutex
+	packs     map[string][]*indexedDocument
+	docFreq   map[string]int
+	docCount  int
+	totalTerm int
+}
+
+// NewIndex creates an empty evidence index
+func NewIndex() *Index {
+	return &Index{
+		packs:   make(map[string][]*indexedDocument),
+		docFreq: make(map[string]int),
+	}
+}
+
+// AddPack indexes the passages of a pack, replacing any previously indexed version of it
+func (idx *Index) AddPack(packID string, docs []Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.removePackLocked(packID)
+
+	indexed := make([]*indexedDocument, 0, len(docs))
+	for _, doc := range docs {
+		doc.PackID = packID
+		doc.Stance = NormalizeStance(doc.Stance)
+		// Topic words are indexed alongside the passage so a topic-only query still finds the pack
+		tokens := Tokenize(doc.Topic + " " + doc.Text)
+		if len(tokens) == 0 {
+			continue
+		}
+		terms := make(map[string]int, len(tokens))
+		for _, token := range tokens {
+			terms[token]++
+		}
+		for term := range terms {
+			idx.docFreq[term]++
+		}
+		idx.docCount++
+		idx.totalTerm += len(tokens)
+		indexed = append(indexed, &indexedDocument{doc: doc, terms: terms, length: len(tokens)})
+	}
+	if len(indexed) > 0 {
+		idx.packs[packID] = indexed
+	}
+}
+
+// RemovePack drops every passage belonging to a pack
+func (idx *Index) RemovePack(packID string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.removePackLocked(packID)
+}
+
+func (idx *Index) removePackLocked(packID string) {
+	docs, ok := idx.packs[packID]
+	if !ok {
+		return
+	}
+	for _, d := range docs {
+		for term := range d.terms {
+			idx.docFreq[term]--
+			if idx.docFreq[term] <= 0 {
+				delete(idx.docFreq, term)
+			}
+		}
+		idx.docCount--
+		idx.totalTerm -= d.length
+	}
+	delete(idx.packs, packID)
+}
+
+// Size returns the number of indexed passages
+func (idx *Index) Size() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return idx.docCount
+}
+
+// Search returns up to limit passages ranked by BM25 relevance to the query. When stance is
+// "for" or "against", passages tagged for the opposite side are skipped.
+func (idx *Index) Search(query, stance string, limit int) []Result {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	queryTerms := Tokenize(query)
+	if len(queryTerms) == 0 || idx.docCount == 0 || limit <= 0 {
+		return nil
+	}
+	stance = NormalizeStance(stance)
+	avgLength := float64(idx.totalTerm) / float64(idx.docCount)
+
+	var results []Result
+	for _, docs := range idx.packs {
+		for _, d := range docs {
+			if stance != StanceNeutral && d.doc.Stance != StanceNeutral && d.doc.Stance != stance {
+				continue
+			}
+			score := 0.0
+			for _, term := range queryTerms {
+				tf := float64(d.terms[term])
+				if tf == 0 {
+					continue
+				}
+				df := float64(idx.docFreq[term])
+				idf := math.Log(1 + (float64(idx.docCount)-df+0.5)/(df+0.5))
+				score += idf * (tf * (bm25K1 + 1)) / (tf + bm25K1*(1-bm25B+bm25B*float64(d.length)/avgLength))
+			}
+			if score > 0 {
+				results = append(results, Result{Document: d.doc, Score: score})
+			}
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].PassageID < results[j].PassageID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// NormalizeStance maps free-form stance labels ("For", "against the motion", ...) to a stance constant
+func NormalizeStance(stance string) string {
+	for _, word := range strings.Fields(strings.ToLower(stance)) {
+		switch strings.Trim(word, ".,:;!") {
+		case "against", "con", "oppose", "opposed", "opposing":
+			return StanceAgainst
+		case "for", "pro", "support", "supporting":
+			return StanceFor
+		}
+	}
+	return StanceNeutral
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true, "be": true, "by": true,
+	"for": true, "from": true, "has": true, "have": true, "in": true, "is": true, "it": true, "its": true,
+	"of": true, "on": true, "or": true, "that": true, "the": true, "this": true, "to": true, "was": true,
+	"were": true, "will": true, "with": true, "should": true, "we": true, "i": true, "you": true,
+}
+
+// Tokenize lowercases text, splits it on non-alphanumeric characters and drops stop words
+func Tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	tokens := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if len(f) < 2 || stopWords[f] {
+			continue
+		}
+		tokens = append(tokens, f)
+	}
+	return tokens
+}
@@ -0,0 +1,61 @@
+package evidence
+
+import "testing"
+
+func TestIndexSearchRespectsStanceAndRelevance(t *testing.T) {
+	idx := NewIndex()
+	idx.AddPack("pack1", []Document{
+		{PassageID: "p1", Topic: "Remote work", Text: "Remote workers reported 13% higher productivity in a two-year study.", Stance: "for"},
+		{PassageID: "p2", Topic: "Remote work", Text: "Isolation and loneliness rise among fully remote employees.", Stance: "against"},
+		{PassageID: "p3", Topic: "Remote work", Text: "Commuting accounts for a large share of urban emissions.", Stance: ""},
+	})
+
+	results := idx.Search("remote work productivity", "For", 5)
+	if len(results) == 0 || results[0].PassageID != "p1" {
+		t.Fatalf("expected p1 to rank first, got %+v", results)
+	}
+	for _, r := range results {
+		if r.PassageID == "p2" {
+			t.Errorf("passage for the opposing stance should be excluded")
+		}
+	}
+
+	if got := idx.Search("remote work", "", 5); len(got) != 3 {
+		t.Errorf("expected all passages without a stance filter, got %d", len(got))
+	}
+}
+
+func TestIndexRemovePack(t *testing.T) {
+	idx := NewIndex()
+	idx.AddPack("pack1", []Document{{PassageID: "p1", Topic: "School uniforms", Text: "Uniforms reduce bullying."}})
+	idx.AddPack("pack2", []Document{{PassageID: "p1", Topic: "Space exploration", Text: "Satellites enable weather forecasting."}})
+
+	idx.RemovePack("pack1")
+	if idx.Size() != 1 {
+		t.Fatalf("expected 1 passage after removal, got %d", idx.Size())
+	}
+	if got := idx.Search("uniforms bullying", "", 5); len(got) != 0 {
+		t.Errorf("removed pack should not be searchable, got %+v", got)
+	}
+
+	// Re-adding a pack replaces its previous passages instead of duplicating them
+	idx.AddPack("pack2", []Document{{PassageID: "p1", Topic: "Space exploration", Text: "Satellites enable GPS."}})
+	if idx.Size() != 1 {
+		t.Errorf("expected re-indexed pack to replace old passages, got %d", idx.Size())
+	}
+}
+
+func TestNormalizeStance(t *testing.T) {
+	cases := map[string]string{
+		"For":                StanceFor,
+		"against the motion": StanceAgainst,
+		"Pro":                StanceFor,
+		"":                   StanceNeutral,
+		"improve":            StanceNeutral,
+	}
+	for in, want := range cases {
+		if got := NormalizeStance(in); got != want {
+			t.Errorf("NormalizeStance(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -84,8 +84,16 @@ m = g(r.sub, p.sub) && r.obj == p.obj && r.act == p.act
 		enforcer.AddPolicy("admin", "comment", "delete")
 		enforcer.AddPolicy("admin", "user", "read")
 		enforcer.AddPolicy("admin", "analytics", "read")
+		enforcer.AddPolicy("admin", "evidence", "read")
+		enforcer.AddPolicy("admin", "evidence", "create")
+		enforcer.AddPolicy("admin", "evidence", "delete")
+		enforcer.AddPolicy("admin", "spectator_chat", "moderate")
+		enforcer.AddPolicy("admin", "debate_audience", "moderate")
+		enforcer.AddPolicy("admin", "featured_debate", "manage")
 		enforcer.AddPolicy("moderator", "comment", "delete")
 		enforcer.AddPolicy("moderator", "user", "read")
+		enforcer.AddPolicy("moderator", "spectator_chat", "moderate")
+		enforcer.AddPolicy("moderator", "debate_audience", "moderate")
 	}
 
 	// Load policies
@@ -112,8 +120,16 @@ func ensureDefaultPolicies() {
 		{"admin", "comment", "delete"},
 		{"admin", "user", "read"},
 		{"admin", "analytics", "read"},
+		{"admin", "evidence", "read"},
+		{"admin", "evidence", "create"},
+		{"admin", "evidence", "delete"},
+		{"admin", "spectator_chat", "moderate"},
+		{"admin", "debate_audience", "moderate"},
+		{"admin", "featured_debate", "manage"},
 		{"moderator", "comment", "delete"},
 		{"moderator", "user", "read"},
+		{"moderator", "spectator_chat", "moderate"},
+		{"moderator", "debate_audience", "moderate"},
 	}
 
 	// Add policies if they don't exist
@@ -238,11 +254,18 @@ func AddAdminRole(email, role string) error {
 // LogAdminAction logs an admin action for audit purposes
 func LogAdminAction(c *gin.Context, action, resourceType string, resourceID primitive.ObjectID, details map[string]interface{}) error {
 	adminID, exists := c.Get("adminID")
+	if !exists {
+		// Debate hosts moderating their own debate are logged under their user account
+		adminID, exists = c.Get("userID")
+	}
 	if !exists {
 		return fmt.Errorf("adminID not found in context")
 	}
 	
 	adminEmail, exists := c.Get("adminEmail")
+	if !exists {
+		adminEmail, exists = c.Get("email")
+	}
 	if !exists {
 		return fmt.Errorf("adminEmail not found in context")
 	}
@@ -0,0 +1,28 @@
+package models
+
+// Fallacy is a reasoning error detected in an argument
+type Fallacy struct {
+	Type        string `json:"type" bson:"type"`
+	Excerpt     string `json:"excerpt" bson:"excerpt"`
+	Explanation string `json:"explanation,omitempty" bson:"explanation,omitempty"`
+}
+
+// ArgumentAnalysis breaks a single debate message down into its argumentative parts
+type ArgumentAnalysis struct {
+	Claims    []string  `json:"claims" bson:"claims"`
+	Warrants  []string  `json:"warrants" bson:"warrants"` // Reasoning that links evidence to claims
+	Evidence  []string  `json:"evidence" bson:"evidence"`
+	Fallacies []Fallacy `json:"fallacies" bson:"fallacies"`
+	Style     string    `json:"style" bson:"style"` // Opponent style derived from the structure, e.g. "Logical opponent"
+}
+
+// ArgumentFeedback summarises a user's argumentation across a whole debate
+type ArgumentFeedback struct {
+	MessagesAnalyzed int            `json:"messagesAnalyzed"`
+	Claims           int            `json:"claims"`
+	Warrants         int            `json:"warrants"`
+	Evidence         int            `json:"evidence"`
+	Fallacies        map[string]int `json:"fallacies"`
+	Strengths        []string       `json:"strengths"`
+	Suggestions      []string       `json:"suggestions"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Audience decision states
+const (
+	AudienceDecisionPreOpen  = "pre_open"  // Opening poll taking votes
+	AudienceDecisionDebating = "debating"  // Opening poll counted, debate under way
+	AudienceDecisionPostOpen = "post_open" // Closing poll taking votes
+	AudienceDecisionDecided  = "decided"
+)
+
+// AudienceDecision runs the opening and closing opinion polls of a debate decided by its audience
+type AudienceDecision struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DebateID   string             `json:"debateId" bson:"debateId"` // Spectator hub debate ID (room ID or debate ID)
+	Topic      string             `json:"topic,omitempty" bson:"topic,omitempty"`
+	Status     string             `json:"status" bson:"status"`
+	PrePollID  string             `json:"prePollId" bson:"prePollId"`
+	PostPollID string             `json:"postPollId,omitempty" bson:"postPollId,omitempty"`
+	PreVotes   map[string]int64   `json:"preVotes,omitempty" bson:"preVotes,omitempty"` // Opening poll counts when the debate started
+	Result     *AudienceResult    `json:"result,omitempty" bson:"result,omitempty"`
+	OpenedBy   primitive.ObjectID `json:"openedBy" bson:"openedBy"`
+	CreatedAt  time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt  time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+func (d AudienceDecision) MarshalJSON() ([]byte, error) {
+	type Alias AudienceDecision
+	a := Alias(d)
+	a.ID = primitive.NilObjectID
+	a.OpenedBy = primitive.NilObjectID
+	return json.Marshal(&struct {
+		ID       string `json:"id"`
+		OpenedBy string `json:"openedBy"`
+		Alias
+	}{
+		ID:       d.ID.Hex(),
+		OpenedBy: d.OpenedBy.Hex(),
+		Alias:    a,
+	})
+}
+
+// AudienceResult is the audience's verdict: how opinion moved between the opening and closing polls.
+// The side that gained the most ground wins, as in Intelligence Squared debates.
+type AudienceResult struct {
+	Pre        map[string]int64   `json:"pre" bson:"pre"`   // Option -> votes before the debate
+	Post       map[string]int64   `json:"post" bson:"post"` // Option -> votes after the debate
+	PreVoters  int64              `json:"preVoters" bson:"preVoters"`
+	PostVoters int64              `json:"postVoters" bson:"postVoters"`
+	Swing      map[string]float64 `json:"swing" bson:"swing"`   // Option -> change in share of the vote, in percentage points
+	Winner     string             `json:"winner" bson:"winner"` // "for", "against" or "draw"; empty when either poll got no votes
+	DecidedAt  time.Time          `json:"decidedAt" bson:"decidedAt"`
+}
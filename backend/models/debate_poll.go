@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DebatePollResult is the final result of a spectator poll, archived when the poll closes
+type DebatePollResult struct {
+	ID                  primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DebateID            string             `json:"debateId" bson:"debateId"` // Spectator hub debate ID (room ID or debate ID)
+	PollID              string             `json:"pollId" bson:"pollId"`
+	Question            string             `json:"question" bson:"question"`
+	Options             []string           `json:"options" bson:"options"`
+	Counts              map[string]int64   `json:"counts" bson:"counts"`
+	AuthenticatedCounts map[string]int64   `json:"authenticatedCounts" bson:"authenticatedCounts"` // Votes from logged-in spectators
+	Voters              int64              `json:"voters" bson:"voters"`
+	CreatedBy           string             `json:"createdBy,omitempty" bson:"createdBy,omitempty"`
+	CreatedAt           time.Time          `json:"createdAt" bson:"createdAt"`
+	ClosedAt            time.Time          `json:"closedAt" bson:"closedAt"`
+}
+
+func (r DebatePollResult) MarshalJSON() ([]byte, error) {
+	type Alias DebatePollResult
+	a := Alias(r)
+	a.ID = primitive.NilObjectID
+	return json.Marshal(&struct {
+		ID string `json:"id"`
+		Alias
+	}{
+		ID:    r.ID.Hex(),
+		Alias: a,
+	})
+}
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Spectator question review states
+const (
+	DebateQuestionPending  = "pending"
+	DebateQuestionApproved = "approved"
+	DebateQuestionRejected = "rejected"
+)
+
+// DebateQuestion is a spectator question waiting in a debate's Q&A queue
+type DebateQuestion struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DebateID       string             `json:"debateId" bson:"debateId"` // Spectator hub debate ID (room ID or debate ID)
+	Text           string             `json:"text" bson:"text"`
+	NormalizedText string             `json:"-" bson:"normalizedText"` // Used to spot duplicates
+	AskerHash      string             `json:"-" bson:"askerHash"`
+	Status         string             `json:"status" bson:"status"`
+	Upvotes        int                `json:"upvotes" bson:"upvotes"`
+	Voters         []string           `json:"-" bson:"voters"` // Spectator hashes that asked or upvoted
+	ReviewedBy     primitive.ObjectID `json:"reviewedBy,omitempty" bson:"reviewedBy,omitempty"`
+	ReviewedAt     *time.Time         `json:"reviewedAt,omitempty" bson:"reviewedAt,omitempty"`
+	PushedAt       *time.Time         `json:"pushedAt,omitempty" bson:"pushedAt,omitempty"` // When it was sent to the debaters
+	Answered       bool               `json:"answered" bson:"answered"`
+	AnsweredAt     *time.Time         `json:"answeredAt,omitempty" bson:"answeredAt,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+func (q DebateQuestion) MarshalJSON() ([]byte, error) {
+	type Alias DebateQuestion
+	a := Alias(q)
+	a.ID = primitive.NilObjectID
+	a.ReviewedBy = primitive.NilObjectID
+	out := struct {
+		ID         string `json:"id"`
+		ReviewedBy string `json:"reviewedBy,omitempty"`
+		Alias
+	}{
+		ID:    q.ID.Hex(),
+		Alias: a,
+	}
+	if !q.ReviewedBy.IsZero() {
+		out.ReviewedBy = q.ReviewedBy.Hex()
+	}
+	return json.Marshal(&out)
+}
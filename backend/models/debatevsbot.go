@@ -1,12 +1,20 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // Message represents a single message in the debate
 type Message struct {
 	Sender string `json:"sender" bson:"sender"` // "User", "Bot", or "Judge"
 	Text   string `json:"text" bson:"text"`
 	Phase  string `json:"phase,omitempty" bson:"phase,omitempty"` // Added for phase-specific tracking
+	// Citations lists the evidence passages referenced in Text via [E1]-style markers
+	Citations []Citation `json:"citations,omitempty" bson:"citations,omitempty"`
+	// Analysis holds the claims, warrants, evidence and fallacies found in a user message
+	Analysis *ArgumentAnalysis `json:"analysis,omitempty" bson:"analysis,omitempty"`
 }
 
 // PhaseTiming represents the timing configuration for a debate phase
@@ -29,3 +37,26 @@ type DebateVsBot struct {
 	Outcome      string             `json:"outcome" bson:"outcome"`           // Result of the debate (e.g., "User wins")
 	CreatedAt    int64              `json:"createdAt" bson:"createdAt"`
 }
+
+// BotMemoryEntry is a recurring argument or weakness the bots remember about a user
+type BotMemoryEntry struct {
+	Text     string    `json:"text" bson:"text"`
+	Count    int       `json:"count" bson:"count"`
+	LastSeen time.Time `json:"lastSeen" bson:"lastSeen"`
+}
+
+// BotOpponentProfile is the per-user memory bots build from past debates with that user
+type BotOpponentProfile struct {
+	ID              primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID          primitive.ObjectID `json:"userId" bson:"userId"`
+	Email           string             `json:"email" bson:"email"`
+	Arguments       []BotMemoryEntry   `json:"arguments" bson:"arguments"`
+	Weaknesses      []BotMemoryEntry   `json:"weaknesses" bson:"weaknesses"`
+	RecentTopics    []string           `json:"recentTopics" bson:"recentTopics"`
+	DebatesAnalyzed int                `json:"debatesAnalyzed" bson:"debatesAnalyzed"`
+	Wins            int                `json:"wins" bson:"wins"`
+	Losses          int                `json:"losses" bson:"losses"`
+	Draws           int                `json:"draws" bson:"draws"`
+	LastAnalyzedAt  time.Time          `json:"lastAnalyzedAt" bson:"lastAnalyzedAt"`
+	UpdatedAt       time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EvidencePassage is a single citable piece of evidence inside a pack
+type EvidencePassage struct {
+	ID     string `json:"id" bson:"id"`
+	Kind   string `json:"kind" bson:"kind"` // "article", "statistic" or "quote"
+	Text   string `json:"text" bson:"text"`
+	Source string `json:"source" bson:"source"`
+	URL    string `json:"url,omitempty" bson:"url,omitempty"`
+	Stance string `json:"stance,omitempty" bson:"stance,omitempty"` // "for", "against" or empty for neutral
+}
+
+// EvidencePack is an admin-curated collection of evidence for a debate topic
+type EvidencePack struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Topic       string             `json:"topic" bson:"topic"`
+	Title       string             `json:"title" bson:"title"`
+	Description string             `json:"description,omitempty" bson:"description,omitempty"`
+	Tags        []string           `json:"tags,omitempty" bson:"tags,omitempty"`
+	Passages    []EvidencePassage  `json:"passages" bson:"passages"`
+	CreatedBy   primitive.ObjectID `json:"createdBy" bson:"createdBy"`
+	CreatedAt   time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt   time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// MarshalJSON customizes JSON serialization for EvidencePack to convert ObjectIDs to hex strings
+func (p EvidencePack) MarshalJSON() ([]byte, error) {
+	type Alias EvidencePack
+	a := Alias(p)
+	a.ID = primitive.NilObjectID
+	a.CreatedBy = primitive.NilObjectID
+	return json.Marshal(&struct {
+		ID        string `json:"id"`
+		CreatedBy string `json:"createdBy"`
+		Alias
+	}{
+		ID:        p.ID.Hex(),
+		CreatedBy: p.CreatedBy.Hex(),
+		Alias:     a,
+	})
+}
+
+// Citation links a bot message to the evidence passage it cited
+type Citation struct {
+	Label     string `json:"label" bson:"label"` // Marker used in the message text, e.g. "E1"
+	PackID    string `json:"packId" bson:"packId"`
+	PassageID string `json:"passageId" bson:"passageId"`
+	Kind      string `json:"kind,omitempty" bson:"kind,omitempty"`
+	Source    string `json:"source" bson:"source"`
+	URL       string `json:"url,omitempty" bson:"url,omitempty"`
+	Excerpt   string `json:"excerpt" bson:"excerpt"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExhibitionDebate is a bot-vs-bot debate streamed to spectators
+type ExhibitionDebate struct {
+	ID           primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Topic        string             `json:"topic" bson:"topic"`
+	Format       string             `json:"format" bson:"format"`
+	ForBot       string             `json:"forBot" bson:"forBot"`
+	AgainstBot   string             `json:"againstBot" bson:"againstBot"`
+	Status       string             `json:"status" bson:"status"` // "running", "finished" or "failed"
+	CurrentPhase string             `json:"currentPhase,omitempty" bson:"currentPhase,omitempty"`
+	Messages     []Message          `json:"messages" bson:"messages"`
+	Transcripts  map[string]string  `json:"transcripts,omitempty" bson:"transcripts,omitempty"` // phase -> text, as used by the judge
+	PollID       string             `json:"pollId,omitempty" bson:"pollId,omitempty"`
+	AudienceVote map[string]int64   `json:"audienceVote,omitempty" bson:"audienceVote,omitempty"`
+	Winner       string             `json:"winner,omitempty" bson:"winner,omitempty"` // Winning bot name or "draw"
+	Result       string             `json:"result,omitempty" bson:"result,omitempty"` // Judge output
+	HostID       primitive.ObjectID `json:"hostId" bson:"hostId"`
+	HostEmail    string             `json:"hostEmail" bson:"hostEmail"`
+	TranscriptID primitive.ObjectID `json:"transcriptId,omitempty" bson:"transcriptId,omitempty"`
+	PostID       primitive.ObjectID `json:"postId,omitempty" bson:"postId,omitempty"`
+	CreatedAt    time.Time          `json:"createdAt" bson:"createdAt"`
+	FinishedAt   *time.Time         `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+}
+
+// MarshalJSON customizes JSON serialization for ExhibitionDebate to convert ObjectIDs to hex strings
+func (e ExhibitionDebate) MarshalJSON() ([]byte, error) {
+	type Alias ExhibitionDebate
+	a := Alias(e)
+	a.ID = primitive.NilObjectID
+	a.HostID = primitive.NilObjectID
+	a.TranscriptID = primitive.NilObjectID
+	a.PostID = primitive.NilObjectID
+	out := struct {
+		ID           string `json:"id"`
+		HostID       string `json:"hostId"`
+		TranscriptID string `json:"transcriptId,omitempty"`
+		PostID       string `json:"postId,omitempty"`
+		Alias
+	}{
+		ID:     e.ID.Hex(),
+		HostID: e.HostID.Hex(),
+		Alias:  a,
+	}
+	if !e.TranscriptID.IsZero() {
+		out.TranscriptID = e.TranscriptID.Hex()
+	}
+	if !e.PostID.IsZero() {
+		out.PostID = e.PostID.Hex()
+	}
+	return json.Marshal(&out)
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// FeaturedDebate is a debate admins picked to show at the top of the live directory
+type FeaturedDebate struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DebateID   string             `json:"debateId" bson:"debateId"`             // Spectator hub debate ID (room ID or debate ID)
+	Note       string             `json:"note,omitempty" bson:"note,omitempty"` // Shown with the debate, e.g. why it's worth watching
+	Priority   int                `json:"priority" bson:"priority"`             // Higher comes first
+	FeaturedBy primitive.ObjectID `json:"featuredBy" bson:"featuredBy"`
+	FeaturedAt time.Time          `json:"featuredAt" bson:"featuredAt"`
+	ExpiresAt  *time.Time         `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"`
+}
+
+func (f FeaturedDebate) MarshalJSON() ([]byte, error) {
+	type Alias FeaturedDebate
+	a := Alias(f)
+	a.ID = primitive.NilObjectID
+	a.FeaturedBy = primitive.NilObjectID
+	return json.Marshal(&struct {
+		ID         string `json:"id"`
+		FeaturedBy string `json:"featuredBy"`
+		Alias
+	}{
+		ID:         f.ID.Hex(),
+		FeaturedBy: f.FeaturedBy.Hex(),
+		Alias:      a,
+	})
+}
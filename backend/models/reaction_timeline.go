@@ -0,0 +1,53 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ReactionTimeline collects a debate's spectator reactions per second, with the phases and speeches
+// they happened during
+type ReactionTimeline struct {
+	ID         primitive.ObjectID          `json:"id" bson:"_id,omitempty"`
+	DebateID   string                      `json:"debateId" bson:"debateId"` // Spectator hub debate ID (room ID or debate ID)
+	Buckets    map[string]map[string]int64 `json:"buckets" bson:"buckets"`   // Unix second -> reaction -> count
+	Phases     []TimelinePhase             `json:"phases" bson:"phases"`
+	Segments   []TimelineSegment           `json:"segments" bson:"segments"`
+	CreatedAt  time.Time                   `json:"createdAt" bson:"createdAt"`
+	FinishedAt *time.Time                  `json:"finishedAt,omitempty" bson:"finishedAt,omitempty"`
+}
+
+func (t ReactionTimeline) MarshalJSON() ([]byte, error) {
+	type Alias ReactionTimeline
+	a := Alias(t)
+	a.ID = primitive.NilObjectID
+	return json.Marshal(&struct {
+		ID string `json:"id"`
+		Alias
+	}{
+		ID:    t.ID.Hex(),
+		Alias: a,
+	})
+}
+
+// TimelinePhase marks the start of a debate phase
+type TimelinePhase struct {
+	Phase     string    `json:"phase" bson:"phase"`
+	Side      string    `json:"side,omitempty" bson:"side,omitempty"`
+	TeamID    string    `json:"teamId,omitempty" bson:"teamId,omitempty"`
+	StartedAt time.Time `json:"startedAt" bson:"startedAt"`
+}
+
+// TimelineSegment is a finished speech, recorded when its transcript arrived
+type TimelineSegment struct {
+	UserID   string    `json:"userId,omitempty" bson:"userId,omitempty"`
+	Username string    `json:"username,omitempty" bson:"username,omitempty"`
+	TeamID   string    `json:"teamId,omitempty" bson:"teamId,omitempty"`
+	Side     string    `json:"side,omitempty" bson:"side,omitempty"`
+	Phase    string    `json:"phase,omitempty" bson:"phase,omitempty"`
+	Text     string    `json:"text" bson:"text"`
+	IsBot    bool      `json:"isBot,omitempty" bson:"isBot,omitempty"`
+	EndedAt  time.Time `json:"endedAt" bson:"endedAt"`
+}
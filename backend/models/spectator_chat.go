@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Spectator chat sanction kinds
+const (
+	SpectatorChatMute = "mute" // Can watch but not chat
+	SpectatorChatBan  = "ban"  // Removed from the debate's spectator hub
+)
+
+// SpectatorChatMessage is a message in a debate's spectator chat
+type SpectatorChatMessage struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DebateID      string             `json:"debateId" bson:"debateId"` // Spectator hub debate ID (room ID or debate ID)
+	SpectatorHash string             `json:"spectatorHash" bson:"spectatorHash"`
+	IPHash        string             `json:"-" bson:"ipHash"`
+	UserID        string             `json:"userId,omitempty" bson:"userId,omitempty"` // Set for logged-in spectators
+	Username      string             `json:"username,omitempty" bson:"username,omitempty"`
+	Content       string             `json:"content" bson:"content"`
+	Filtered      bool               `json:"filtered,omitempty" bson:"filtered,omitempty"` // Blocked words were masked
+	Deleted       bool               `json:"deleted,omitempty" bson:"deleted,omitempty"`
+	DeletedBy     primitive.ObjectID `json:"deletedBy,omitempty" bson:"deletedBy,omitempty"`
+	DeletedAt     *time.Time         `json:"deletedAt,omitempty" bson:"deletedAt,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+func (m SpectatorChatMessage) MarshalJSON() ([]byte, error) {
+	type Alias SpectatorChatMessage
+	a := Alias(m)
+	a.ID = primitive.NilObjectID
+	a.DeletedBy = primitive.NilObjectID
+	out := struct {
+		ID        string `json:"id"`
+		DeletedBy string `json:"deletedBy,omitempty"`
+		Alias
+	}{
+		ID:    m.ID.Hex(),
+		Alias: a,
+	}
+	if !m.DeletedBy.IsZero() {
+		out.DeletedBy = m.DeletedBy.Hex()
+	}
+	return json.Marshal(out)
+}
+
+// SpectatorChatSanction mutes or bans a spectator from a debate, until it expires or is lifted
+type SpectatorChatSanction struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	DebateID      string             `json:"debateId" bson:"debateId"`
+	Kind          string             `json:"kind" bson:"kind"`
+	SpectatorHash string             `json:"spectatorHash" bson:"spectatorHash"`
+	IPHash        string             `json:"-" bson:"ipHash,omitempty"` // Sanctions on anonymous spectators also cover anonymous viewers on this network
+	UserID        string             `json:"userId,omitempty" bson:"userId,omitempty"`
+	Username      string             `json:"username,omitempty" bson:"username,omitempty"`
+	Reason        string             `json:"reason,omitempty" bson:"reason,omitempty"`
+	IssuedBy      primitive.ObjectID `json:"issuedBy" bson:"issuedBy"`
+	IssuedByRole  string             `json:"issuedByRole" bson:"issuedByRole"`               // "host", or the admin's role
+	ExpiresAt     *time.Time         `json:"expiresAt,omitempty" bson:"expiresAt,omitempty"` // Unset for the rest of the debate
+	LiftedBy      primitive.ObjectID `json:"liftedBy,omitempty" bson:"liftedBy,omitempty"`
+	LiftedAt      *time.Time         `json:"liftedAt,omitempty" bson:"liftedAt,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+func (s SpectatorChatSanction) MarshalJSON() ([]byte, error) {
+	type Alias SpectatorChatSanction
+	a := Alias(s)
+	a.ID = primitive.NilObjectID
+	a.IssuedBy = primitive.NilObjectID
+	a.LiftedBy = primitive.NilObjectID
+	out := struct {
+		ID       string `json:"id"`
+		IssuedBy string `json:"issuedBy"`
+		LiftedBy string `json:"liftedBy,omitempty"`
+		Alias
+	}{
+		ID:       s.ID.Hex(),
+		IssuedBy: s.IssuedBy.Hex(),
+		Alias:    a,
+	}
+	if !s.LiftedBy.IsZero() {
+		out.LiftedBy = s.LiftedBy.Hex()
+	}
+	return json.Marshal(out)
+}
+
+// ActiveAt reports whether the sanction applies at the given time
+func (s SpectatorChatSanction) ActiveAt(at time.Time) bool {
+	return s.LiftedAt == nil && (s.ExpiresAt == nil || at.Before(*s.ExpiresAt))
+}
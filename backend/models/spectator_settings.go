@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SpectatorSettings controls what spectators of a debate need to do before they can take part
+type SpectatorSettings struct {
+	DebateID               string             `json:"debateId" bson:"debateId"` // Spectator hub debate ID (room ID or debate ID)
+	RequireLoginToVote     bool               `json:"requireLoginToVote" bson:"requireLoginToVote"`
+	RequireLoginToAsk      bool               `json:"requireLoginToAsk" bson:"requireLoginToAsk"`
+	MaxAnonymousPerIP      int                `json:"maxAnonymousPerIp" bson:"maxAnonymousPerIp"`           // Anonymous connections from one IP address
+	MaxAnonymousVotesPerIP int                `json:"maxAnonymousVotesPerIp" bson:"maxAnonymousVotesPerIp"` // Anonymous votes per poll from one IP address
+	ChatDisabled           bool               `json:"chatDisabled" bson:"chatDisabled"`
+	RequireLoginToChat     bool               `json:"requireLoginToChat" bson:"requireLoginToChat"`
+	ChatSlowModeSeconds    int                `json:"chatSlowModeSeconds" bson:"chatSlowModeSeconds"`     // Wait between one spectator's messages, 0 for off
+	ChatBlockedWords       []string           `json:"chatBlockedWords,omitempty" bson:"chatBlockedWords"` // Masked in chat messages; hosts only, see ForSpectators
+	UpdatedBy              primitive.ObjectID `json:"updatedBy,omitempty" bson:"updatedBy,omitempty"`
+	UpdatedAt              time.Time          `json:"updatedAt,omitempty" bson:"updatedAt,omitempty"`
+}
+
+// ForSpectators returns the settings without the blocked word list, which would show spectators
+// what gets past the filter
+func (s SpectatorSettings) ForSpectators() SpectatorSettings {
+	s.ChatBlockedWords = nil
+	return s
+}
+
+func (s SpectatorSettings) MarshalJSON() ([]byte, error) {
+	type Alias SpectatorSettings
+	a := Alias(s)
+	a.UpdatedBy = primitive.NilObjectID
+	updatedBy := ""
+	if !s.UpdatedBy.IsZero() {
+		updatedBy = s.UpdatedBy.Hex()
+	}
+	return json.Marshal(&struct {
+		UpdatedBy string `json:"updatedBy,omitempty"`
+		Alias
+	}{
+		UpdatedBy: updatedBy,
+		Alias:     a,
+	})
+}
@@ -15,7 +15,8 @@ type Team struct {
 	CaptainID    primitive.ObjectID `bson:"captainId" json:"captainId"`
 	CaptainEmail string             `bson:"captainEmail" json:"captainEmail"`
 	Members      []TeamMember       `bson:"members" json:"members"`
-	MaxSize      int                `bson:"maxSize" json:"maxSize"` // Maximum team size for matching
+	Coaches      []TeamMember       `bson:"coaches,omitempty" json:"coaches,omitempty"` // Coaches advise the team but do not debate
+	MaxSize      int                `bson:"maxSize" json:"maxSize"`                     // Maximum team size for matching
 	AverageElo   float64            `bson:"averageElo" json:"averageElo"`
 	CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt    time.Time          `bson:"updatedAt" json:"updatedAt"`
@@ -29,6 +30,37 @@ type TeamMember struct {
 	AvatarURL   string             `bson:"avatarUrl,omitempty" json:"avatarUrl,omitempty"`
 	Elo         float64            `bson:"elo" json:"elo"`
 	JoinedAt    time.Time          `bson:"joinedAt" json:"joinedAt"`
+	IsBot       bool               `bson:"isBot,omitempty" json:"isBot,omitempty"` // Bot personality speaking for a bot team
+	Role        string             `bson:"role,omitempty" json:"role,omitempty"`   // TeamRoleCoCaptain, or empty for a regular member
+}
+
+// Team roles
+const (
+	TeamRoleCaptain   = "captain"
+	TeamRoleCoCaptain = "co_captain"
+	TeamRoleMember    = "member"
+	TeamRoleCoach     = "coach"
+)
+
+// RoleOf returns the user's role in the team, or "" if they are not part of it
+func (t Team) RoleOf(userID primitive.ObjectID) string {
+	if userID == t.CaptainID {
+		return TeamRoleCaptain
+	}
+	for _, member := range t.Members {
+		if member.UserID == userID {
+			if member.Role == TeamRoleCoCaptain {
+				return TeamRoleCoCaptain
+			}
+			return TeamRoleMember
+		}
+	}
+	for _, coach := range t.Coaches {
+		if coach.UserID == userID {
+			return TeamRoleCoach
+		}
+	}
+	return ""
 }
 
 // MarshalJSON customizes JSON serialization for Team to convert ObjectIDs to hex strings
@@ -57,6 +89,12 @@ func (tm TeamMember) MarshalJSON() ([]byte, error) {
 	})
 }
 
+// Team debate modes
+const (
+	TeamDebateModeTeams  = "teams"   // Two human teams
+	TeamDebateModeVsBots = "vs_bots" // A human team (team 1) against a team of bot personalities (team 2)
+)
+
 // TeamDebate represents a debate between two teams
 type TeamDebate struct {
 	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
@@ -69,15 +107,98 @@ type TeamDebate struct {
 	Topic         string             `bson:"topic" json:"topic"`
 	Team1Stance   string             `bson:"team1Stance" json:"team1Stance"` // "for" or "against"
 	Team2Stance   string             `bson:"team2Stance" json:"team2Stance"` // "for" or "against"
-	Status        string             `bson:"status" json:"status"`           // "waiting", "active", "finished"
+	Status        string             `bson:"status" json:"status"`           // "waiting", "active", "judging", "finished"
+	CurrentPhase  string             `bson:"currentPhase,omitempty" json:"currentPhase,omitempty"`
 	CurrentTurn   string             `bson:"currentTurn" json:"currentTurn"` // "team1" or "team2"
 	CurrentUserID primitive.ObjectID `bson:"currentUserId,omitempty" json:"currentUserId,omitempty"`
 	TurnCount     int                `bson:"turnCount" json:"turnCount"`
 	MaxTurns      int                `bson:"maxTurns" json:"maxTurns"`
+	Version       int                `bson:"version" json:"version"` // Bumped on every turn state change
 	Team1Elo      float64            `bson:"team1Elo" json:"team1Elo"`
 	Team2Elo      float64            `bson:"team2Elo" json:"team2Elo"`
-	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+	Mode          string             `bson:"mode,omitempty" json:"mode,omitempty"`     // "teams" (default) or "vs_bots"
+	Format        string             `bson:"format,omitempty" json:"format,omitempty"` // Debate format, "standard" when empty
+	ChallengeID   primitive.ObjectID `bson:"challengeId,omitempty" json:"challengeId,omitempty"`
+	Speeches      []TeamDebateSpeech `bson:"speeches,omitempty" json:"speeches,omitempty"`
+	Substitutions []TeamSubstitution `bson:"substitutions,omitempty" json:"substitutions,omitempty"`
+	TimeoutsUsed  map[string]int     `bson:"timeoutsUsed,omitempty" json:"timeoutsUsed,omitempty"` // teamID (hex) -> timeouts called
+	SpeakingTime  map[string]int     `bson:"speakingTime,omitempty" json:"speakingTime,omitempty"` // userID (hex) -> seconds spoken
+	// Outcome, set once the debate is judged
+	Result         string              `bson:"result,omitempty" json:"result,omitempty"` // Judge output (JSON)
+	Winner         string              `bson:"winner,omitempty" json:"winner,omitempty"` // "team1", "team2" or "draw"
+	SpeakerResults []TeamSpeakerResult `bson:"speakerResults,omitempty" json:"speakerResults,omitempty"`
+	AudienceResult *AudienceResult     `bson:"audienceResult,omitempty" json:"audienceResult,omitempty"` // Set in audience decision mode
+	Team1EloAfter  float64             `bson:"team1EloAfter,omitempty" json:"team1EloAfter,omitempty"`
+	Team2EloAfter  float64             `bson:"team2EloAfter,omitempty" json:"team2EloAfter,omitempty"`
+	FinishedAt     *time.Time          `bson:"finishedAt,omitempty" json:"finishedAt,omitempty"`
+	CreatedAt      time.Time           `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time           `bson:"updatedAt" json:"updatedAt"`
+}
+
+// IsBotTeam reports whether the given side of the debate is played by bots
+func (td TeamDebate) IsBotTeam(teamID primitive.ObjectID) bool {
+	return td.Mode == TeamDebateModeVsBots && teamID == td.Team2ID
+}
+
+// State returns the debate's turn state
+func (td TeamDebate) State() TeamDebateState {
+	phase := td.CurrentPhase
+	if phase == "" {
+		phase = "setup"
+	}
+	return TeamDebateState{
+		DebateID:      td.ID,
+		Status:        td.Status,
+		Phase:         phase,
+		CurrentTurn:   td.CurrentTurn,
+		CurrentUserID: td.CurrentUserID,
+		TurnCount:     td.TurnCount,
+		Version:       td.Version,
+		UpdatedAt:     td.UpdatedAt,
+	}
+}
+
+// TeamDebateState is the turn state of a team debate shared by the REST API and the live room
+type TeamDebateState struct {
+	DebateID      primitive.ObjectID `json:"debateId"`
+	Status        string             `json:"status"`
+	Phase         string             `json:"phase"`
+	CurrentTurn   string             `json:"currentTurn"` // "team1" or "team2"
+	CurrentUserID primitive.ObjectID `json:"currentUserId,omitempty"`
+	TurnCount     int                `json:"turnCount"`
+	Version       int                `json:"version"`
+	UpdatedAt     time.Time          `json:"updatedAt"`
+}
+
+// MarshalJSON customizes JSON serialization for TeamDebateState to convert ObjectIDs to hex strings
+func (tds TeamDebateState) MarshalJSON() ([]byte, error) {
+	type Alias TeamDebateState
+	var currentUserHex *string
+	if !tds.CurrentUserID.IsZero() {
+		hex := tds.CurrentUserID.Hex()
+		currentUserHex = &hex
+	}
+	return json.Marshal(&struct {
+		DebateID      string  `json:"debateId"`
+		CurrentUserID *string `json:"currentUserId,omitempty"`
+		*Alias
+	}{
+		DebateID:      tds.DebateID.Hex(),
+		CurrentUserID: currentUserHex,
+		Alias:         (*Alias)(&tds),
+	})
+}
+
+// TeamDebateSpeech is a single speech given during a team debate, by a human or a bot
+type TeamDebateSpeech struct {
+	TeamID      primitive.ObjectID `bson:"teamId" json:"teamId"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	DisplayName string             `bson:"displayName" json:"displayName"`
+	IsBot       bool               `bson:"isBot" json:"isBot"`
+	Phase       string             `bson:"phase" json:"phase"`
+	Text        string             `bson:"text" json:"text"`
+	Citations   []Citation         `bson:"citations,omitempty" json:"citations,omitempty"`
+	Timestamp   time.Time          `bson:"timestamp" json:"timestamp"`
 }
 
 // TeamDebateMessage represents a message in a team debate
@@ -98,6 +219,7 @@ type TeamDebateMessage struct {
 type TeamChatMessage struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	TeamID      primitive.ObjectID `bson:"teamId" json:"teamId"`
+	DebateID    primitive.ObjectID `bson:"debateId,omitempty" json:"debateId,omitempty"` // Set for messages in a debate's prep channel
 	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
 	Email       string             `bson:"email" json:"email"`
 	DisplayName string             `bson:"displayName" json:"displayName"`
@@ -113,21 +235,125 @@ func (td TeamDebate) MarshalJSON() ([]byte, error) {
 		hex := td.CurrentUserID.Hex()
 		currentUserHex = &hex
 	}
+	var challengeHex *string
+	if !td.ChallengeID.IsZero() {
+		hex := td.ChallengeID.Hex()
+		challengeHex = &hex
+	}
 	return json.Marshal(&struct {
 		ID            string  `json:"id,omitempty"`
 		Team1ID       string  `json:"team1Id"`
 		Team2ID       string  `json:"team2Id"`
 		CurrentUserID *string `json:"currentUserId,omitempty"`
+		ChallengeID   *string `json:"challengeId,omitempty"`
 		*Alias
 	}{
 		ID:            td.ID.Hex(),
 		Team1ID:       td.Team1ID.Hex(),
 		Team2ID:       td.Team2ID.Hex(),
 		CurrentUserID: currentUserHex,
+		ChallengeID:   challengeHex,
 		Alias:         (*Alias)(&td),
 	})
 }
 
+// TeamSubstitution records a captain swapping a disconnected member for a connected one
+type TeamSubstitution struct {
+	TeamID    primitive.ObjectID `bson:"teamId" json:"teamId"`
+	OutUserID primitive.ObjectID `bson:"outUserId" json:"outUserId"`
+	InUserID  primitive.ObjectID `bson:"inUserId" json:"inUserId"`
+	Phase     string             `bson:"phase" json:"phase"`
+	Timestamp time.Time          `bson:"timestamp" json:"timestamp"`
+}
+
+// MarshalJSON customizes JSON serialization for TeamSubstitution to convert ObjectIDs to hex strings
+func (ts TeamSubstitution) MarshalJSON() ([]byte, error) {
+	type Alias TeamSubstitution
+	return json.Marshal(&struct {
+		TeamID    string `json:"teamId"`
+		OutUserID string `json:"outUserId"`
+		InUserID  string `json:"inUserId"`
+		*Alias
+	}{
+		TeamID:    ts.TeamID.Hex(),
+		OutUserID: ts.OutUserID.Hex(),
+		InUserID:  ts.InUserID.Hex(),
+		Alias:     (*Alias)(&ts),
+	})
+}
+
+// TeamSpeakerResult is the judged outcome for one speaker in a team debate
+type TeamSpeakerResult struct {
+	TeamID      primitive.ObjectID `bson:"teamId" json:"teamId"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	DisplayName string             `bson:"displayName" json:"displayName"`
+	IsBot       bool               `bson:"isBot" json:"isBot"`
+	Speeches    int                `bson:"speeches" json:"speeches"`
+	Words       int                `bson:"words" json:"words"`
+	Content     int                `bson:"content" json:"content"`   // 0-10: quality of arguments and evidence
+	Style       int                `bson:"style" json:"style"`       // 0-10: clarity and delivery
+	Strategy    int                `bson:"strategy" json:"strategy"` // 0-10: fulfilling their role and engaging the other team
+	Points      int                `bson:"points" json:"points"`     // Speaker points: content + style + strategy
+	Feedback    string             `bson:"feedback" json:"feedback"`
+	PreRating   float64            `bson:"preRating,omitempty" json:"preRating,omitempty"`
+	PostRating  float64            `bson:"postRating,omitempty" json:"postRating,omitempty"`
+}
+
+// MarshalJSON customizes JSON serialization for TeamSpeakerResult to convert ObjectIDs to hex strings
+func (tsr TeamSpeakerResult) MarshalJSON() ([]byte, error) {
+	type Alias TeamSpeakerResult
+	return json.Marshal(&struct {
+		TeamID string `json:"teamId"`
+		UserID string `json:"userId"`
+		*Alias
+	}{
+		TeamID: tsr.TeamID.Hex(),
+		UserID: tsr.UserID.Hex(),
+		Alias:  (*Alias)(&tsr),
+	})
+}
+
+// SpeakerCareerStats aggregates a user's speaker points across all their team debates
+type SpeakerCareerStats struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	UserID        primitive.ObjectID `bson:"userId" json:"userId"`
+	Debates       int                `bson:"debates" json:"debates"`
+	TotalContent  int                `bson:"totalContent" json:"totalContent"`
+	TotalStyle    int                `bson:"totalStyle" json:"totalStyle"`
+	TotalStrategy int                `bson:"totalStrategy" json:"totalStrategy"`
+	TotalPoints   int                `bson:"totalPoints" json:"totalPoints"`
+	BestPoints    int                `bson:"bestPoints" json:"bestPoints"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// MarshalJSON customizes JSON serialization for SpeakerCareerStats to convert ObjectIDs to hex strings
+func (scs SpeakerCareerStats) MarshalJSON() ([]byte, error) {
+	type Alias SpeakerCareerStats
+	return json.Marshal(&struct {
+		ID     string `json:"id,omitempty"`
+		UserID string `json:"userId"`
+		*Alias
+	}{
+		ID:     scs.ID.Hex(),
+		UserID: scs.UserID.Hex(),
+		Alias:  (*Alias)(&scs),
+	})
+}
+
+// MarshalJSON customizes JSON serialization for TeamDebateSpeech to convert ObjectIDs to hex strings
+func (tds TeamDebateSpeech) MarshalJSON() ([]byte, error) {
+	type Alias TeamDebateSpeech
+	return json.Marshal(&struct {
+		TeamID string `json:"teamId"`
+		UserID string `json:"userId"`
+		*Alias
+	}{
+		TeamID: tds.TeamID.Hex(),
+		UserID: tds.UserID.Hex(),
+		Alias:  (*Alias)(&tds),
+	})
+}
+
 // MarshalJSON customizes JSON serialization for TeamDebateMessage to convert ObjectIDs to hex strings
 func (tdm TeamDebateMessage) MarshalJSON() ([]byte, error) {
 	type Alias TeamDebateMessage
@@ -149,15 +375,267 @@ func (tdm TeamDebateMessage) MarshalJSON() ([]byte, error) {
 // MarshalJSON customizes JSON serialization for TeamChatMessage to convert ObjectIDs to hex strings
 func (tcm TeamChatMessage) MarshalJSON() ([]byte, error) {
 	type Alias TeamChatMessage
+	debateID := ""
+	if !tcm.DebateID.IsZero() {
+		debateID = tcm.DebateID.Hex()
+	}
 	return json.Marshal(&struct {
-		ID     string `json:"id,omitempty"`
-		TeamID string `json:"teamId"`
+		ID       string `json:"id,omitempty"`
+		TeamID   string `json:"teamId"`
+		DebateID string `json:"debateId,omitempty"`
+		UserID   string `json:"userId"`
+		*Alias
+	}{
+		ID:       tcm.ID.Hex(),
+		TeamID:   tcm.TeamID.Hex(),
+		DebateID: debateID,
+		UserID:   tcm.UserID.Hex(),
+		Alias:    (*Alias)(&tcm),
+	})
+}
+
+// TeamPrepRoom is a team's private preparation space for one debate. It is never shown to the
+// opposing team or spectators, and is kept after the debate for the team's review.
+type TeamPrepRoom struct {
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	DebateID       primitive.ObjectID   `bson:"debateId" json:"debateId"`
+	TeamID         primitive.ObjectID   `bson:"teamId" json:"teamId"`
+	Notes          string               `bson:"notes" json:"notes"`
+	NotesVersion   int                  `bson:"notesVersion" json:"notesVersion"` // Bumped on every notes edit
+	NotesUpdatedBy primitive.ObjectID   `bson:"notesUpdatedBy,omitempty" json:"notesUpdatedBy,omitempty"`
+	NotesUpdatedAt time.Time            `bson:"notesUpdatedAt,omitempty" json:"notesUpdatedAt,omitempty"`
+	Pinned         []TeamPinnedArgument `bson:"pinned" json:"pinned"`
+	CreatedAt      time.Time            `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time            `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TeamPinnedArgument is an argument a team member pinned in the prep room
+type TeamPinnedArgument struct {
+	ID          primitive.ObjectID `bson:"_id" json:"id"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	DisplayName string             `bson:"displayName" json:"displayName"`
+	Text        string             `bson:"text" json:"text"`
+	Phase       string             `bson:"phase,omitempty" json:"phase,omitempty"` // Phase the argument is meant for
+	PinnedAt    time.Time          `bson:"pinnedAt" json:"pinnedAt"`
+}
+
+// MarshalJSON customizes JSON serialization for TeamPrepRoom to convert ObjectIDs to hex strings
+func (tpr TeamPrepRoom) MarshalJSON() ([]byte, error) {
+	type Alias TeamPrepRoom
+	updatedBy := ""
+	if !tpr.NotesUpdatedBy.IsZero() {
+		updatedBy = tpr.NotesUpdatedBy.Hex()
+	}
+	pinned := tpr.Pinned
+	if pinned == nil {
+		pinned = []TeamPinnedArgument{}
+	}
+	return json.Marshal(&struct {
+		ID             string               `json:"id,omitempty"`
+		DebateID       string               `json:"debateId"`
+		TeamID         string               `json:"teamId"`
+		NotesUpdatedBy string               `json:"notesUpdatedBy,omitempty"`
+		Pinned         []TeamPinnedArgument `json:"pinned"`
+		*Alias
+	}{
+		ID:             tpr.ID.Hex(),
+		DebateID:       tpr.DebateID.Hex(),
+		TeamID:         tpr.TeamID.Hex(),
+		NotesUpdatedBy: updatedBy,
+		Pinned:         pinned,
+		Alias:          (*Alias)(&tpr),
+	})
+}
+
+// MarshalJSON customizes JSON serialization for TeamPinnedArgument to convert ObjectIDs to hex strings
+func (tpa TeamPinnedArgument) MarshalJSON() ([]byte, error) {
+	type Alias TeamPinnedArgument
+	return json.Marshal(&struct {
+		ID     string `json:"id"`
 		UserID string `json:"userId"`
 		*Alias
 	}{
-		ID:     tcm.ID.Hex(),
-		TeamID: tcm.TeamID.Hex(),
-		UserID: tcm.UserID.Hex(),
-		Alias:  (*Alias)(&tcm),
+		ID:     tpa.ID.Hex(),
+		UserID: tpa.UserID.Hex(),
+		Alias:  (*Alias)(&tpa),
+	})
+}
+
+// TeamInvitation is an invitation link to join a team, valid until it expires, is used up or is revoked
+type TeamInvitation struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	TeamID    primitive.ObjectID `bson:"teamId" json:"teamId"`
+	TeamName  string             `bson:"teamName" json:"teamName"`
+	Token     string             `bson:"token" json:"token"`
+	Role      string             `bson:"role" json:"role"` // TeamRoleMember or TeamRoleCoach
+	CreatedBy primitive.ObjectID `bson:"createdBy" json:"createdBy"`
+	MaxUses   int                `bson:"maxUses" json:"maxUses"`
+	Uses      int                `bson:"uses" json:"uses"`
+	Revoked   bool               `bson:"revoked" json:"revoked"`
+	ExpiresAt time.Time          `bson:"expiresAt" json:"expiresAt"`
+	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// MarshalJSON customizes JSON serialization for TeamInvitation to convert ObjectIDs to hex strings
+func (ti TeamInvitation) MarshalJSON() ([]byte, error) {
+	type Alias TeamInvitation
+	return json.Marshal(&struct {
+		ID        string `json:"id,omitempty"`
+		TeamID    string `json:"teamId"`
+		CreatedBy string `json:"createdBy"`
+		*Alias
+	}{
+		ID:        ti.ID.Hex(),
+		TeamID:    ti.TeamID.Hex(),
+		CreatedBy: ti.CreatedBy.Hex(),
+		Alias:     (*Alias)(&ti),
+	})
+}
+
+// TeamJoinRequest is a user's request to join a team, decided by the captain or a co-captain
+type TeamJoinRequest struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	TeamID      primitive.ObjectID `bson:"teamId" json:"teamId"`
+	UserID      primitive.ObjectID `bson:"userId" json:"userId"`
+	Email       string             `bson:"email" json:"email"`
+	DisplayName string             `bson:"displayName" json:"displayName"`
+	AvatarURL   string             `bson:"avatarUrl,omitempty" json:"avatarUrl,omitempty"`
+	Elo         float64            `bson:"elo" json:"elo"`
+	Message     string             `bson:"message,omitempty" json:"message,omitempty"`
+	Status      string             `bson:"status" json:"status"` // "pending", "approved", "rejected", "cancelled"
+	DecidedBy   primitive.ObjectID `bson:"decidedBy,omitempty" json:"decidedBy,omitempty"`
+	DecidedAt   *time.Time         `bson:"decidedAt,omitempty" json:"decidedAt,omitempty"`
+	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// MarshalJSON customizes JSON serialization for TeamJoinRequest to convert ObjectIDs to hex strings
+func (tjr TeamJoinRequest) MarshalJSON() ([]byte, error) {
+	type Alias TeamJoinRequest
+	decidedBy := ""
+	if !tjr.DecidedBy.IsZero() {
+		decidedBy = tjr.DecidedBy.Hex()
+	}
+	return json.Marshal(&struct {
+		ID        string `json:"id,omitempty"`
+		TeamID    string `json:"teamId"`
+		UserID    string `json:"userId"`
+		DecidedBy string `json:"decidedBy,omitempty"`
+		*Alias
+	}{
+		ID:        tjr.ID.Hex(),
+		TeamID:    tjr.TeamID.Hex(),
+		UserID:    tjr.UserID.Hex(),
+		DecidedBy: decidedBy,
+		Alias:     (*Alias)(&tjr),
+	})
+}
+
+// Team challenge statuses
+const (
+	TeamChallengePending   = "pending"   // Waiting for the other team to accept the proposed time
+	TeamChallengeAccepted  = "accepted"  // Both teams agreed; the debate starts at ScheduledAt
+	TeamChallengeDeclined  = "declined"  // The challenged team turned it down
+	TeamChallengeCancelled = "cancelled" // The challenging team withdrew it
+	TeamChallengeStarting  = "starting"  // The scheduler is creating the debate
+	TeamChallengeStarted   = "started"   // The debate was created
+	TeamChallengeExpired   = "expired"   // The slot passed without a debate
+)
+
+// TeamChallenge is a debate one team proposes to another for a specific time
+type TeamChallenge struct {
+	ID               primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	ChallengerTeamID primitive.ObjectID `bson:"challengerTeamId" json:"challengerTeamId"`
+	ChallengerName   string             `bson:"challengerName" json:"challengerName"`
+	OpponentTeamID   primitive.ObjectID `bson:"opponentTeamId" json:"opponentTeamId"`
+	OpponentName     string             `bson:"opponentName" json:"opponentName"`
+	CreatedBy        primitive.ObjectID `bson:"createdBy" json:"createdBy"`
+	Topic            string             `bson:"topic" json:"topic"`
+	Format           string             `bson:"format" json:"format"`
+	ScheduledAt      time.Time          `bson:"scheduledAt" json:"scheduledAt"`
+	ProposedBy       primitive.ObjectID `bson:"proposedBy" json:"proposedBy"` // Team that proposed the current time; the other team must accept it
+	Reschedules      int                `bson:"reschedules" json:"reschedules"`
+	Status           string             `bson:"status" json:"status"`
+	RemindersSent    []string           `bson:"remindersSent,omitempty" json:"remindersSent,omitempty"`
+	DebateID         primitive.ObjectID `bson:"debateId,omitempty" json:"debateId,omitempty"`
+	CreatedAt        time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt        time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// MarshalJSON customizes JSON serialization for TeamChallenge to convert ObjectIDs to hex strings
+func (tc TeamChallenge) MarshalJSON() ([]byte, error) {
+	type Alias TeamChallenge
+	debateID := ""
+	if !tc.DebateID.IsZero() {
+		debateID = tc.DebateID.Hex()
+	}
+	return json.Marshal(&struct {
+		ID               string `json:"id,omitempty"`
+		ChallengerTeamID string `json:"challengerTeamId"`
+		OpponentTeamID   string `json:"opponentTeamId"`
+		CreatedBy        string `json:"createdBy"`
+		ProposedBy       string `json:"proposedBy"`
+		DebateID         string `json:"debateId,omitempty"`
+		*Alias
+	}{
+		ID:               tc.ID.Hex(),
+		ChallengerTeamID: tc.ChallengerTeamID.Hex(),
+		OpponentTeamID:   tc.OpponentTeamID.Hex(),
+		CreatedBy:        tc.CreatedBy.Hex(),
+		ProposedBy:       tc.ProposedBy.Hex(),
+		DebateID:         debateID,
+		Alias:            (*Alias)(&tc),
 	})
 }
+
+// TeamStats summarises a team's finished debates
+type TeamStats struct {
+	TeamID                 string                `json:"teamId"`
+	TeamName               string                `json:"teamName"`
+	Record                 TeamRecord            `json:"record"`
+	ByStance               map[string]TeamRecord `json:"byStance"` // "for" or "against" -> record
+	ByTopic                []TeamTopicRecord     `json:"byTopic"`
+	RatingHistory          []TeamRatingPoint     `json:"ratingHistory"`
+	BestSpeakers           []TeamSpeakerSummary  `json:"bestSpeakers"`
+	HeadToHead             []TeamHeadToHead      `json:"headToHead"`
+	AverageSpeakingBalance float64               `json:"averageSpeakingBalance"` // 1 when every member spoke equally, 0 when someone never spoke
+}
+
+// TeamRecord is a win/loss/draw record
+type TeamRecord struct {
+	Played  int     `json:"played"`
+	Wins    int     `json:"wins"`
+	Losses  int     `json:"losses"`
+	Draws   int     `json:"draws"`
+	WinRate float64 `json:"winRate"`
+}
+
+// TeamTopicRecord is a team's record on one debate topic
+type TeamTopicRecord struct {
+	Topic string `json:"topic"`
+	TeamRecord
+}
+
+// TeamRatingPoint is a team's average Elo after one rated debate
+type TeamRatingPoint struct {
+	DebateID string    `json:"debateId"`
+	Before   float64   `json:"before"`
+	After    float64   `json:"after"`
+	At       time.Time `json:"at"`
+}
+
+// TeamSpeakerSummary is a member's speaker points across the team's debates
+type TeamSpeakerSummary struct {
+	UserID        string  `json:"userId"`
+	DisplayName   string  `json:"displayName"`
+	Debates       int     `json:"debates"`
+	TotalPoints   int     `json:"totalPoints"`
+	AveragePoints float64 `json:"averagePoints"`
+	BestPoints    int     `json:"bestPoints"`
+}
+
+// TeamHeadToHead is a team's record against one opposing team
+type TeamHeadToHead struct {
+	OpponentID   string `json:"opponentId"`
+	OpponentName string `json:"opponentName"`
+	TeamRecord
+}
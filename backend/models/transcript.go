@@ -16,9 +16,10 @@ type DebateTranscript struct {
 }
 
 type DebateResult struct {
-	RoomID    string    `bson:"roomId" json:"roomId"`
-	Result    string    `bson:"result" json:"result"`
-	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	RoomID         string          `bson:"roomId" json:"roomId"`
+	Result         string          `bson:"result" json:"result"`
+	AudienceResult *AudienceResult `bson:"audienceResult,omitempty" json:"audienceResult,omitempty"` // Set in audience decision mode
+	CreatedAt      time.Time       `bson:"createdAt" json:"createdAt"`
 }
 
 // SavedDebateTranscript represents a saved debate transcript that users can view later
@@ -26,14 +27,17 @@ type SavedDebateTranscript struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	UserID      primitive.ObjectID `bson:"userId" json:"userId,omitempty"`
 	Email       string             `bson:"email" json:"email"`
-	DebateType  string             `bson:"debateType" json:"debateType"` // "user_vs_bot" or "user_vs_user"
+	DebateType  string             `bson:"debateType" json:"debateType"` // "user_vs_bot", "user_vs_user", "team_vs_team" or "bot_vs_bot"
 	Topic       string             `bson:"topic" json:"topic"`
 	Opponent    string             `bson:"opponent" json:"opponent"` // Bot name or opponent email
 	Result      string             `bson:"result" json:"result"`     // "win", "loss", "draw", "pending"
 	Messages    []Message          `bson:"messages" json:"messages"`
 	Transcripts map[string]string  `bson:"transcripts,omitempty" json:"transcripts,omitempty"` // For user vs user debates
-	CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
-	UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+	DebateID    string             `bson:"debateId,omitempty" json:"debateId,omitempty"`       // Room or debate the transcript comes from, when known
+	// Audience verdict, stored next to the judge's result in audience decision mode
+	AudienceResult *AudienceResult `bson:"audienceResult,omitempty" json:"audienceResult,omitempty"`
+	CreatedAt      time.Time       `bson:"createdAt" json:"createdAt"`
+	UpdatedAt      time.Time       `bson:"updatedAt" json:"updatedAt"`
 }
 
 func (s SavedDebateTranscript) MarshalJSON() ([]byte, error) {
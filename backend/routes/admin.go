@@ -22,6 +22,7 @@ func SetupAdminRoutes(router *gin.Engine, configPath string) {
 		// Analytics
 		admin.GET("/analytics", controllers.GetAnalytics)
 		admin.GET("/analytics/history", controllers.GetAnalyticsHistory)
+		admin.GET("/spectator-metrics", controllers.GetSpectatorMetrics)
 		
 		// Debates management
 		admin.GET("/debates", controllers.GetDebates)
@@ -33,6 +34,29 @@ func SetupAdminRoutes(router *gin.Engine, configPath string) {
 		admin.DELETE("/comments/:id", middlewares.RBACMiddleware("comment", "delete"), controllers.DeleteComment)
 		admin.DELETE("/comments/bulk", middlewares.RBACMiddleware("comment", "delete"), controllers.BulkDeleteComments)
 		
+		// Evidence packs used by the debate bots
+		admin.GET("/evidence-packs", middlewares.RBACMiddleware("evidence", "read"), controllers.GetEvidencePacks)
+		admin.POST("/evidence-packs", middlewares.RBACMiddleware("evidence", "create"), controllers.CreateEvidencePack)
+		admin.DELETE("/evidence-packs/:id", middlewares.RBACMiddleware("evidence", "delete"), controllers.DeleteEvidencePack)
+
+		// Featured debates in the live directory
+		admin.GET("/featured-debates", middlewares.RBACMiddleware("featured_debate", "manage"), controllers.GetFeaturedDebates)
+		admin.POST("/featured-debates", middlewares.RBACMiddleware("featured_debate", "manage"), controllers.FeatureDebate)
+		admin.DELETE("/featured-debates/:id", middlewares.RBACMiddleware("featured_debate", "manage"), controllers.UnfeatureDebate)
+
+		// Spectator chat moderation (admin and moderator)
+		spectatorChat := admin.Group("/spectator-chat/:debateID", middlewares.RBACMiddleware("spectator_chat", "moderate"))
+		spectatorChat.GET("/messages", controllers.AdminGetSpectatorChat)
+		spectatorChat.DELETE("/messages/:messageId", controllers.AdminDeleteSpectatorChatMessage)
+		spectatorChat.GET("/sanctions", controllers.AdminGetSpectatorSanctions)
+		spectatorChat.POST("/sanctions", controllers.AdminSanctionSpectator)
+		spectatorChat.DELETE("/sanctions/:sanctionId", controllers.AdminLiftSpectatorSanction)
+
+		// Calls that favour one side of a debate (admin and moderator)
+		debateAudience := admin.Group("/debate-audience/:debateID", middlewares.RBACMiddleware("debate_audience", "moderate"))
+		debateAudience.PUT("/questions/:questionId/review", controllers.AdminReviewDebateQuestion)
+		debateAudience.POST("/audience-decision/close", controllers.AdminCloseAudienceDecision)
+
 		// Admin action logs
 		admin.GET("/logs", controllers.GetAdminActionLogs)
 	}
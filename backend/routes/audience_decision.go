@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAudienceDecisionRoutes sets up routes for debates decided by an audience vote
+func SetupAudienceDecisionRoutes(router *gin.RouterGroup) {
+	decision := router.Group("/debates/:debateID/audience-decision")
+	{
+		decision.GET("", controllers.GetAudienceDecision)
+		decision.POST("", controllers.EnableAudienceDecision)
+		decision.POST("/close", controllers.CloseAudienceDecision)
+	}
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupDebatePollRoutes sets up routes for running spectator polls and reading their results
+func SetupDebatePollRoutes(router *gin.RouterGroup) {
+	polls := router.Group("/debates/:debateID/polls")
+	{
+		polls.GET("", controllers.GetDebatePollResults)
+		polls.POST("", controllers.CreateDebatePoll)
+		polls.POST("/:pollId/close", controllers.CloseDebatePoll)
+	}
+}
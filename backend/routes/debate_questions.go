@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupDebateQuestionRoutes sets up routes for moderating spectator questions
+func SetupDebateQuestionRoutes(router *gin.RouterGroup) {
+	questions := router.Group("/debates/:debateID/questions")
+	{
+		questions.GET("", controllers.GetDebateQuestions)
+		questions.PUT("/:questionId/review", controllers.ReviewDebateQuestion)
+		questions.PUT("/:questionId/answered", controllers.UpdateDebateQuestionAnswered)
+	}
+}
@@ -14,5 +14,6 @@ func SetupDebateVsBotRoutes(router *gin.RouterGroup) {
 		vsbot.POST("/debate", controllers.SendDebateMessage)
 		vsbot.POST("/judge", controllers.JudgeDebate)
 		vsbot.POST("/concede", controllers.ConcedeDebate)
+		vsbot.GET("/memory", controllers.GetBotMemory)
 	}
 }
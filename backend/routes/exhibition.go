@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupExhibitionRoutes sets up routes for bot-vs-bot exhibition debates
+func SetupExhibitionRoutes(router *gin.RouterGroup) {
+	exhibitions := router.Group("/exhibitions")
+	{
+		exhibitions.POST("", controllers.StartExhibition)
+		exhibitions.GET("", controllers.GetExhibitions)
+		exhibitions.GET("/:id", controllers.GetExhibition)
+	}
+}
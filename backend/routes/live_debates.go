@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupLiveDebateRoutes sets up the live debate directory. It is public, so anonymous spectators can
+// find debates to watch.
+func SetupLiveDebateRoutes(router *gin.Engine) {
+	router.GET("/live-debates", controllers.GetLiveDebates)
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupReactionHeatmapRoutes sets up routes for reading a debate's spectator reactions
+func SetupReactionHeatmapRoutes(router *gin.RouterGroup) {
+	router.GET("/debates/:debateID/reactions/heatmap", controllers.GetReactionHeatmap)
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSpectatorChatRoutes sets up routes for reading and moderating a debate's spectator chat
+func SetupSpectatorChatRoutes(router *gin.RouterGroup) {
+	chat := router.Group("/debates/:debateID/chat")
+	{
+		chat.GET("", controllers.GetSpectatorChat)
+		chat.DELETE("/messages/:messageId", controllers.DeleteSpectatorChatMessage)
+		chat.GET("/sanctions", controllers.GetSpectatorSanctions)
+		chat.POST("/sanctions", controllers.SanctionSpectator)
+		chat.DELETE("/sanctions/:sanctionId", controllers.LiftSpectatorSanction)
+	}
+}
@@ -0,0 +1,16 @@
+package routes
+
+import (
+	"arguehub/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSpectatorSettingsRoutes sets up routes for a debate's spectator identity controls
+func SetupSpectatorSettingsRoutes(router *gin.RouterGroup) {
+	settings := router.Group("/debates/:debateID/spectator-settings")
+	{
+		settings.GET("", controllers.GetSpectatorSettings)
+		settings.PUT("", controllers.UpdateSpectatorSettings)
+	}
+}
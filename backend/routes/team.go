@@ -13,6 +13,7 @@ func SetupTeamRoutes(router *gin.RouterGroup) {
 		// Team management routes
 		teamRoutes.POST("/", controllers.CreateTeam)
 		teamRoutes.GET("/:id", controllers.GetTeam)
+		teamRoutes.GET("/:id/stats", controllers.GetTeamStats)
 		teamRoutes.POST("/:id/join", controllers.JoinTeam)
 		teamRoutes.POST("/:id/leave", controllers.LeaveTeam)
 		teamRoutes.DELETE("/:teamId", controllers.DeleteTeam)
@@ -23,6 +24,23 @@ func SetupTeamRoutes(router *gin.RouterGroup) {
 		teamRoutes.GET("/members/:memberId", controllers.GetTeamMemberProfile)
 		teamRoutes.GET("/user/teams", controllers.GetUserTeams)
 		teamRoutes.GET("/available", controllers.GetAvailableTeams)
+
+		// Invitations, join requests and roles
+		teamRoutes.GET("/invitations/:token", controllers.GetTeamInvitation)
+		teamRoutes.POST("/invitations/:token/accept", controllers.AcceptTeamInvitation)
+		teamRoutes.POST("/:id/invitations", controllers.CreateTeamInvitation)
+		teamRoutes.GET("/:id/invitations", controllers.ListTeamInvitations)
+		teamRoutes.DELETE("/:teamId/invitations/:invitationId", controllers.RevokeTeamInvitation)
+		teamRoutes.POST("/:id/join-requests", controllers.RequestToJoinTeam)
+		teamRoutes.GET("/:id/join-requests", controllers.ListTeamJoinRequests)
+		teamRoutes.POST("/:id/join-requests/:requestId/approve", controllers.ApproveTeamJoinRequest)
+		teamRoutes.POST("/:id/join-requests/:requestId/reject", controllers.RejectTeamJoinRequest)
+		teamRoutes.PUT("/:teamId/members/:memberId/role", controllers.UpdateMemberRole)
+		teamRoutes.DELETE("/:teamId/coaches/:coachId", controllers.RemoveCoach)
+
+		// Scheduled challenges
+		teamRoutes.POST("/:id/challenges", controllers.CreateTeamChallenge)
+		teamRoutes.GET("/:id/challenges", controllers.ListTeamChallenges)
 	}
 }
 
@@ -31,11 +49,27 @@ func SetupTeamDebateRoutes(router *gin.RouterGroup) {
 	teamDebateRoutes := router.Group("/team-debates")
 	{
 		teamDebateRoutes.POST("/", controllers.CreateTeamDebate)
+		teamDebateRoutes.POST("/vs-bots", controllers.CreateTeamBotDebate)
 		teamDebateRoutes.GET("/:id", controllers.GetTeamDebate)
+		teamDebateRoutes.GET("/:id/state", controllers.GetTeamDebateState)
+		teamDebateRoutes.PUT("/:id/phase", controllers.UpdateTeamDebatePhase)
+		teamDebateRoutes.POST("/:id/complete", controllers.CompleteTeamDebate)
+		teamDebateRoutes.GET("/:id/prep", controllers.GetTeamPrepRoom)
+		teamDebateRoutes.GET("/:id/review", controllers.GetTeamDebateReview)
 		teamDebateRoutes.GET("/team/:teamId/active", controllers.GetActiveTeamDebate)
 	}
 }
 
+// SetupTeamChallengeRoutes sets up routes for answering scheduled team challenges
+func SetupTeamChallengeRoutes(router *gin.RouterGroup) {
+	challengeRoutes := router.Group("/team-challenges")
+	{
+		challengeRoutes.POST("/:id/accept", controllers.AcceptTeamChallenge)
+		challengeRoutes.POST("/:id/decline", controllers.DeclineTeamChallenge)
+		challengeRoutes.POST("/:id/reschedule", controllers.RescheduleTeamChallenge)
+	}
+}
+
 // SetupTeamMatchmakingRoutes sets up team matchmaking routes
 func SetupTeamMatchmakingRoutes(router *gin.RouterGroup) {
 	matchmakingRoutes := router.Group("/matchmaking")
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"arguehub/models"
+)
+
+// Opponent styles understood by BotPersonality.InteractionModifiers
+const (
+	styleAggressive = "Aggressive opponent"
+	styleLogical    = "Logical opponent"
+	styleEmotional  = "Emotional opponent"
+	styleConfident  = "Confident opponent"
+	styleIrrational = "Irrational opponent"
+	styleNeutral    = "Neutral opponent"
+)
+
+var (
+	sentenceSplitRegex = regexp.MustCompile(`[^.!?]+[.!?]*`)
+
+	warrantMarkers = []string{
+		"because", "since", "therefore", "thus", "which means", "this means", "as a result",
+		"this shows", "that is why", "due to", "so that", "hence",
+	}
+	evidenceMarkers = []string{
+		"according to", "study", "studies", "research", "data", "survey", "report", "statistic",
+		"for example", "for instance", "percent", "%", "evidence shows",
+	}
+
+	// fallacyPatterns maps a fallacy to the phrases that commonly signal it
+	fallacyPatterns = []struct {
+		Type        string
+		Markers     []string
+		Explanation string
+	}{
+		{"Ad Hominem", []string{"you're stupid", "you are stupid", "idiot", "you're ignorant", "you are ignorant", "clueless", "you don't know anything", "ridiculous person"},
+			"Attacks the opponent instead of their argument."},
+		{"Bandwagon", []string{"everyone knows", "everybody knows", "most people agree", "everyone agrees", "nobody believes"},
+			"Treats popularity as proof."},
+		{"Slippery Slope", []string{"will lead to", "next thing you know", "before you know it", "slippery slope", "where does it end"},
+			"Assumes one step inevitably causes an extreme outcome without showing the chain."},
+		{"Appeal to Emotion", []string{"think of the children", "how would you feel", "heartbreaking", "imagine how", "it's just wrong"},
+			"Relies on feelings rather than reasons."},
+		{"False Dilemma", []string{"either we", "either you", "the only option", "only two options", "there is no other way"},
+			"Presents two options as the only possibilities."},
+		{"Hasty Generalization", []string{"all of them", "every single one", "they all", "always have been", "never works"},
+			"Draws a broad conclusion from limited cases."},
+	}
+)
+
+// AnalyzeArgument extracts the claims, warrants, evidence and fallacies in a debate message.
+// Gemini is used when available; otherwise a rule-based analysis is returned.
+func AnalyzeArgument(topic, text string) *models.ArgumentAnalysis {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+
+	if geminiClient != nil {
+		analysis, err := analyzeArgumentWithModel(topic, text)
+		if err == nil {
+			analysis.Style = classifyOpponentStyle(analysis)
+			return analysis
+		}
+		log.Printf("Falling back to heuristic argument analysis: %v", err)
+	}
+	return heuristicArgumentAnalysis(text)
+}
+
+func analyzeArgumentWithModel(topic, text string) (*models.ArgumentAnalysis, error) {
+	prompt := fmt.Sprintf(`You are an argumentation analyst. Break down the following debate message on the topic "%s".
+
+Identify:
+- claims: the positions the speaker asserts
+- warrants: the reasoning that connects their support to their claims
+- evidence: facts, statistics, examples or sources offered as support
+- fallacies: reasoning errors, each with a standard name (e.g. "Ad Hominem", "Straw Man", "Slippery Slope", "Appeal to Emotion", "False Dilemma", "Bandwagon", "Hasty Generalization"), the excerpt it appears in, and a one-sentence explanation
+
+Quote or closely paraphrase the speaker. Use empty arrays when nothing applies.
+
+Return ONLY JSON in this format:
+{
+  "claims": ["..."],
+  "warrants": ["..."],
+  "evidence": ["..."],
+  "fallacies": [{"type": "...", "excerpt": "...", "explanation": "..."}]
+}
+
+Message:
+%s`, topic, text)
+
+	response, err := generateDefaultModelText(context.Background(), prompt)
+	if err != nil {
+		return nil, err
+	}
+	var analysis models.ArgumentAnalysis
+	if err := json.Unmarshal([]byte(response), &analysis); err != nil {
+		return nil, err
+	}
+	return &analysis, nil
+}
+
+// heuristicArgumentAnalysis splits a message into sentences and classifies them with marker phrases
+func heuristicArgumentAnalysis(text string) *models.ArgumentAnalysis {
+	analysis := &models.ArgumentAnalysis{
+		Claims:    []string{},
+		Warrants:  []string{},
+		Evidence:  []string{},
+		Fallacies: []models.Fallacy{},
+	}
+
+	for _, raw := range sentenceSplitRegex.FindAllString(text, -1) {
+		sentence := strings.TrimSpace(raw)
+		if len(strings.Fields(sentence)) < 3 {
+			continue
+		}
+
+		for _, pattern := range fallacyPatterns {
+			if containsAny(sentence, pattern.Markers) {
+				analysis.Fallacies = append(analysis.Fallacies, models.Fallacy{
+					Type:        pattern.Type,
+					Excerpt:     sentence,
+					Explanation: pattern.Explanation,
+				})
+			}
+		}
+
+		if strings.HasSuffix(sentence, "?") {
+			continue
+		}
+
+		isEvidence := containsAny(sentence, evidenceMarkers) || containsDigit(sentence)
+		if isEvidence {
+			analysis.Evidence = append(analysis.Evidence, sentence)
+		}
+
+		if idx := firstMarkerIndex(sentence, warrantMarkers); idx >= 0 {
+			claim := strings.TrimSpace(strings.TrimRight(sentence[:idx], ", "))
+			if len(strings.Fields(claim)) >= 3 {
+				analysis.Claims = append(analysis.Claims, claim)
+			}
+			analysis.Warrants = append(analysis.Warrants, strings.TrimSpace(sentence[idx:]))
+		} else if !isEvidence {
+			analysis.Claims = append(analysis.Claims, sentence)
+		}
+	}
+
+	analysis.Style = classifyOpponentStyle(analysis)
+	return analysis
+}
+
+// classifyOpponentStyle maps the structure of an argument onto the opponent styles bots react to
+func classifyOpponentStyle(analysis *models.ArgumentAnalysis) string {
+	if analysis == nil {
+		return styleNeutral
+	}
+	hasFallacy := func(fallacyType string) bool {
+		for _, f := range analysis.Fallacies {
+			if strings.EqualFold(f.Type, fallacyType) {
+				return true
+			}
+		}
+		return false
+	}
+
+	switch {
+	case hasFallacy("Ad Hominem"):
+		return styleAggressive
+	case len(analysis.Fallacies) >= 2:
+		return styleIrrational
+	case hasFallacy("Appeal to Emotion"):
+		return styleEmotional
+	case len(analysis.Evidence) > 0 && len(analysis.Warrants) > 0:
+		return styleLogical
+	case len(analysis.Claims) >= 2 && len(analysis.Warrants) == 0 && len(analysis.Evidence) == 0:
+		return styleConfident
+	default:
+		return styleNeutral
+	}
+}
+
+// formatArgumentAnalysis describes the opponent's last argument so the bot can target its gaps
+func formatArgumentAnalysis(analysis *models.ArgumentAnalysis) string {
+	if analysis == nil {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Breakdown of the opponent's latest argument:")
+	writeList := func(label string, items []string) {
+		if len(items) == 0 {
+			sb.WriteString(fmt.Sprintf("\n- %s: none", label))
+			return
+		}
+		sb.WriteString(fmt.Sprintf("\n- %s: %s", label, strings.Join(items, " | ")))
+	}
+	writeList("Claims", analysis.Claims)
+	writeList("Warrants", analysis.Warrants)
+	writeList("Evidence", analysis.Evidence)
+	if len(analysis.Fallacies) > 0 {
+		fallacies := make([]string, 0, len(analysis.Fallacies))
+		for _, f := range analysis.Fallacies {
+			fallacies = append(fallacies, fmt.Sprintf("%s (\"%s\")", f.Type, f.Excerpt))
+		}
+		writeList("Fallacies", fallacies)
+	}
+	sb.WriteString("\nChallenge claims that lack warrants or evidence, and expose any fallacies in your own voice.")
+	return sb.String()
+}
+
+// FillMissingAnalyses analyses any user message that arrived without an analysis. The rule-based
+// analyzer is used here so judging a long debate does not add a model call per message.
+func FillMissingAnalyses(history []models.Message) {
+	for i := range history {
+		if history[i].Sender == "User" && history[i].Analysis == nil && strings.TrimSpace(history[i].Text) != "" {
+			history[i].Analysis = heuristicArgumentAnalysis(history[i].Text)
+		}
+	}
+}
+
+// BuildArgumentFeedback aggregates the analysis of every user message into post-debate feedback
+func BuildArgumentFeedback(history []models.Message) *models.ArgumentFeedback {
+	feedback := &models.ArgumentFeedback{
+		Fallacies:   map[string]int{},
+		Strengths:   []string{},
+		Suggestions: []string{},
+	}
+	for _, msg := range history {
+		if msg.Sender != "User" || msg.Analysis == nil {
+			continue
+		}
+		feedback.MessagesAnalyzed++
+		feedback.Claims += len(msg.Analysis.Claims)
+		feedback.Warrants += len(msg.Analysis.Warrants)
+		feedback.Evidence += len(msg.Analysis.Evidence)
+		for _, f := range msg.Analysis.Fallacies {
+			feedback.Fallacies[f.Type]++
+		}
+	}
+	if feedback.MessagesAnalyzed == 0 {
+		return feedback
+	}
+
+	if feedback.Claims > 0 && feedback.Warrants >= feedback.Claims/2 {
+		feedback.Strengths = append(feedback.Strengths, "You consistently explained why your claims follow.")
+	} else if feedback.Claims > 0 {
+		feedback.Suggestions = append(feedback.Suggestions, "Many claims were asserted without a warrant; add 'because...' reasoning that links support to each claim.")
+	}
+	if feedback.Evidence >= feedback.MessagesAnalyzed {
+		feedback.Strengths = append(feedback.Strengths, "You backed your arguments with evidence and examples.")
+	} else {
+		feedback.Suggestions = append(feedback.Suggestions, "Support more of your points with concrete evidence, statistics or examples.")
+	}
+	if len(feedback.Fallacies) == 0 {
+		feedback.Strengths = append(feedback.Strengths, "No logical fallacies were detected in your arguments.")
+	}
+	fallacyTypes := make([]string, 0, len(feedback.Fallacies))
+	for fallacyType := range feedback.Fallacies {
+		fallacyTypes = append(fallacyTypes, fallacyType)
+	}
+	sort.Strings(fallacyTypes)
+	for _, fallacyType := range fallacyTypes {
+		feedback.Suggestions = append(feedback.Suggestions, fmt.Sprintf("Avoid %s (used %d time(s)).", fallacyType, feedback.Fallacies[fallacyType]))
+	}
+	return feedback
+}
+
+func containsAny(text string, markers []string) bool {
+	return firstMarkerIndex(text, markers) >= 0
+}
+
+// firstMarkerIndex returns the position in text of the earliest marker found as a whole word or
+// phrase, ignoring case, or -1. Markers are lowercase ASCII, so only ASCII letters are folded and the
+// position can be used to slice the original text.
+func firstMarkerIndex(text string, markers []string) int {
+	text = asciiLower(text)
+	first := -1
+	for _, marker := range markers {
+		for offset := 0; offset < len(text); {
+			idx := strings.Index(text[offset:], marker)
+			if idx < 0 {
+				break
+			}
+			idx += offset
+			end := idx + len(marker)
+			if isWordBoundary(text[:idx], false) && isWordBoundary(text[end:], true) {
+				if first == -1 || idx < first {
+					first = idx
+				}
+				break
+			}
+			offset = idx + 1
+		}
+	}
+	return first
+}
+
+// isWordBoundary reports whether a marker next to text starts or ends a word: the neighbouring rune,
+// the first of text when after is set and the last otherwise, is missing or not a letter
+func isWordBoundary(text string, after bool) bool {
+	var r rune
+	if after {
+		r, _ = utf8.DecodeRuneInString(text)
+	} else {
+		r, _ = utf8.DecodeLastRuneInString(text)
+	}
+	return r == utf8.RuneError || !unicode.IsLetter(r)
+}
+
+// asciiLower lowercases the ASCII letters in text, keeping every byte where it was
+func asciiLower(text string) string {
+	b := []byte(text)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + 'a' - 'A'
+		}
+	}
+	return string(b)
+}
+
+func containsDigit(text string) bool {
+	return strings.ContainsAny(text, "0123456789")
+}
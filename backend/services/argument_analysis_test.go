@@ -0,0 +1,109 @@
+package services
+
+import (
+	"testing"
+
+	"arguehub/models"
+)
+
+func TestHeuristicArgumentAnalysis(t *testing.T) {
+	text := "Remote work should be the default because it saves hours of commuting. " +
+		"According to a 2023 survey, 68% of employees reported higher productivity at home. " +
+		"Everyone knows offices are a waste of money."
+
+	analysis := heuristicArgumentAnalysis(text)
+
+	if len(analysis.Claims) == 0 || analysis.Claims[0] != "Remote work should be the default" {
+		t.Errorf("expected claim before the warrant marker, got %v", analysis.Claims)
+	}
+	if len(analysis.Warrants) != 1 || analysis.Warrants[0] != "because it saves hours of commuting." {
+		t.Errorf("expected one warrant, got %v", analysis.Warrants)
+	}
+	if len(analysis.Evidence) != 1 {
+		t.Errorf("expected the survey sentence as evidence, got %v", analysis.Evidence)
+	}
+	if len(analysis.Fallacies) != 1 || analysis.Fallacies[0].Type != "Bandwagon" {
+		t.Errorf("expected a bandwagon fallacy, got %v", analysis.Fallacies)
+	}
+	if analysis.Style != styleLogical {
+		t.Errorf("expected %q, got %q", styleLogical, analysis.Style)
+	}
+}
+
+func TestHeuristicMarkersMatchWholeWords(t *testing.T) {
+	// "update" contains "data" and "sincerely" contains "since"; neither should count
+	analysis := heuristicArgumentAnalysis("I sincerely think the policy update is a mistake.")
+	if len(analysis.Evidence) != 0 || len(analysis.Warrants) != 0 {
+		t.Errorf("expected no evidence or warrants, got %v / %v", analysis.Evidence, analysis.Warrants)
+	}
+	if len(analysis.Claims) != 1 {
+		t.Errorf("expected a single claim, got %v", analysis.Claims)
+	}
+}
+
+func TestHeuristicAnalysisHandlesNonASCII(t *testing.T) {
+	// Lowercasing "Ⱥ" makes it longer and "İ" shorter, so marker positions must come from the
+	// original text
+	cases := []struct {
+		text, claim, warrant string
+	}{
+		{"ȺȺȺȺȺȺȺȺȺȺ ȺȺȺ ȺȺȺ we must act because", "ȺȺȺȺȺȺȺȺȺȺ ȺȺȺ ȺȺȺ we must act", "because"},
+		{"İİİ İİİ İİİ we must act BECAUSE time is short", "İİİ İİİ İİİ we must act", "BECAUSE time is short"},
+	}
+	for _, tc := range cases {
+		analysis := heuristicArgumentAnalysis(tc.text)
+		if len(analysis.Claims) != 1 || analysis.Claims[0] != tc.claim {
+			t.Errorf("%q: claims = %q, want [%q]", tc.text, analysis.Claims, tc.claim)
+		}
+		if len(analysis.Warrants) != 1 || analysis.Warrants[0] != tc.warrant {
+			t.Errorf("%q: warrants = %q, want [%q]", tc.text, analysis.Warrants, tc.warrant)
+		}
+	}
+
+	// A letter outside ASCII still joins a marker into a longer word
+	if analysis := heuristicArgumentAnalysis("we will act sinceé then nothing"); len(analysis.Warrants) != 0 {
+		t.Errorf("expected no warrant inside a longer word, got %q", analysis.Warrants)
+	}
+}
+
+func TestClassifyOpponentStyle(t *testing.T) {
+	cases := []struct {
+		name     string
+		analysis *models.ArgumentAnalysis
+		want     string
+	}{
+		{"nil", nil, styleNeutral},
+		{"ad hominem", &models.ArgumentAnalysis{Fallacies: []models.Fallacy{{Type: "Ad Hominem"}}}, styleAggressive},
+		{"several fallacies", &models.ArgumentAnalysis{Fallacies: []models.Fallacy{{Type: "Bandwagon"}, {Type: "Slippery Slope"}}}, styleIrrational},
+		{"emotional", &models.ArgumentAnalysis{Fallacies: []models.Fallacy{{Type: "Appeal to Emotion"}}}, styleEmotional},
+		{"unsupported assertions", &models.ArgumentAnalysis{Claims: []string{"a", "b"}}, styleConfident},
+	}
+	for _, tc := range cases {
+		if got := classifyOpponentStyle(tc.analysis); got != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestBuildArgumentFeedback(t *testing.T) {
+	history := []models.Message{
+		{Sender: "User", Text: "Taxes should rise because services need funding."},
+		{Sender: "Bot", Text: "Nonsense, everyone knows taxes hurt growth."},
+		{Sender: "User", Text: "You are stupid. Everyone knows I am right."},
+	}
+	FillMissingAnalyses(history)
+	if history[1].Analysis != nil {
+		t.Errorf("bot messages should not be analysed")
+	}
+
+	feedback := BuildArgumentFeedback(history)
+	if feedback.MessagesAnalyzed != 2 {
+		t.Errorf("expected 2 analysed messages, got %d", feedback.MessagesAnalyzed)
+	}
+	if feedback.Fallacies["Ad Hominem"] != 1 || feedback.Fallacies["Bandwagon"] != 1 {
+		t.Errorf("unexpected fallacy counts: %v", feedback.Fallacies)
+	}
+	if len(feedback.Suggestions) == 0 {
+		t.Errorf("expected suggestions for a debate with fallacies and no evidence")
+	}
+}
@@ -0,0 +1,292 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// audienceVotingWindow is how long the closing poll stays open after the debate finishes
+const audienceVotingWindow = 2 * time.Minute
+
+// audienceDecisionOptions are the choices in both opinion polls
+var audienceDecisionOptions = []string{"For", "Against", "Undecided"}
+
+var (
+	ErrAudienceDecisionExists = errors.New("audience decision mode is already on for this debate")
+	// ErrNoAudienceDecision is returned when a debate has no audience decision in the state a step needs
+	ErrNoAudienceDecision = errors.New("no audience decision in progress for this debate")
+)
+
+// EnableAudienceDecision puts a debate in audience decision mode and opens the opening poll
+func EnableAudienceDecision(debateID, topic string, hostID primitive.ObjectID) (*models.AudienceDecision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := db.GetCollection("audience_decisions")
+	count, err := collection.CountDocuments(ctx, bson.M{"debateId": debateID})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrAudienceDecisionExists
+	}
+
+	topic = strings.TrimSpace(topic)
+	pollID, err := openAudiencePoll(debateID, "Before the debate", topic)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	decision := &models.AudienceDecision{
+		DebateID:  debateID,
+		Topic:     topic,
+		Status:    models.AudienceDecisionPreOpen,
+		PrePollID: pollID,
+		OpenedBy:  hostID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	result, err := collection.InsertOne(ctx, decision)
+	if err != nil {
+		return nil, err
+	}
+	decision.ID = result.InsertedID.(primitive.ObjectID)
+	publishAudienceDecision(*decision)
+	return decision, nil
+}
+
+// GetAudienceDecision returns a debate's audience decision
+func GetAudienceDecision(debateID string) (*models.AudienceDecision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var decision models.AudienceDecision
+	err := db.GetCollection("audience_decisions").FindOne(ctx, bson.M{"debateId": debateID}).Decode(&decision)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNoAudienceDecision
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+// StartAudienceDebate closes and counts the opening poll as the debate starts
+func StartAudienceDebate(debateID string) error {
+	decision, err := advanceAudienceDecision(debateID, []string{models.AudienceDecisionPreOpen}, models.AudienceDecisionDebating)
+	if err != nil {
+		return err
+	}
+	closeAudiencePoll(debateID, decision.PrePollID)
+	preVotes := audiencePollCounts(debateID, decision.PrePollID)
+	if err := setAudienceDecision(debateID, bson.M{"preVotes": preVotes}); err != nil {
+		return err
+	}
+	decision.Status = models.AudienceDecisionDebating
+	publishAudienceDecision(*decision)
+	return nil
+}
+
+// OpenAudiencePostPoll opens the closing poll once the debate finishes, and closes it after the
+// voting window
+func OpenAudiencePostPoll(debateID string) error {
+	decision, err := advanceAudienceDecision(debateID,
+		[]string{models.AudienceDecisionPreOpen, models.AudienceDecisionDebating}, models.AudienceDecisionPostOpen)
+	if err != nil {
+		return err
+	}
+
+	set := bson.M{}
+	if decision.Status == models.AudienceDecisionPreOpen {
+		// The debate ended without the start being seen, so count the opening poll now
+		closeAudiencePoll(debateID, decision.PrePollID)
+		set["preVotes"] = audiencePollCounts(debateID, decision.PrePollID)
+	}
+	pollID, err := openAudiencePoll(debateID, "After the debate", decision.Topic)
+	if err != nil {
+		return err
+	}
+	set["postPollId"] = pollID
+	if err := setAudienceDecision(debateID, set); err != nil {
+		return err
+	}
+
+	decision.Status = models.AudienceDecisionPostOpen
+	decision.PostPollID = pollID
+	publishAudienceDecision(*decision)
+
+	time.AfterFunc(audienceVotingWindow, func() {
+		if _, err := CloseAudienceDecision(debateID); err != nil && !errors.Is(err, ErrNoAudienceDecision) {
+			log.Printf("failed to close the audience decision for debate %s: %v", debateID, err)
+		}
+	})
+	return nil
+}
+
+// CloseAudienceDecision counts the closing poll, works out the audience's verdict and stores it next
+// to the judge's result
+func CloseAudienceDecision(debateID string) (*models.AudienceResult, error) {
+	decision, err := advanceAudienceDecision(debateID, []string{models.AudienceDecisionPostOpen}, models.AudienceDecisionDecided)
+	if err != nil {
+		return nil, err
+	}
+
+	closeAudiencePoll(debateID, decision.PostPollID)
+	result := ComputeAudienceResult(decision.PreVotes, audiencePollCounts(debateID, decision.PostPollID), time.Now())
+	if err := setAudienceDecision(debateID, bson.M{"result": result}); err != nil {
+		return nil, err
+	}
+	attachAudienceResult(debateID, result)
+
+	decision.Status = models.AudienceDecisionDecided
+	decision.Result = &result
+	publishAudienceDecision(*decision)
+	return &result, nil
+}
+
+// ComputeAudienceResult compares each option's share of the opening and closing polls. The side
+// whose share grew the most wins.
+func ComputeAudienceResult(pre, post map[string]int64, decidedAt time.Time) models.AudienceResult {
+	result := models.AudienceResult{
+		Pre:       make(map[string]int64, len(audienceDecisionOptions)),
+		Post:      make(map[string]int64, len(audienceDecisionOptions)),
+		Swing:     make(map[string]float64, len(audienceDecisionOptions)),
+		DecidedAt: decidedAt,
+	}
+	// Only the decision's own options count, whatever else the poll counts hold
+	for _, option := range audienceDecisionOptions {
+		result.Pre[option] = pre[option]
+		result.Post[option] = post[option]
+		result.PreVoters += pre[option]
+		result.PostVoters += post[option]
+	}
+	if result.PreVoters == 0 || result.PostVoters == 0 {
+		return result
+	}
+
+	for _, option := range audienceDecisionOptions {
+		preShare := float64(result.Pre[option]) / float64(result.PreVoters) * 100
+		postShare := float64(result.Post[option]) / float64(result.PostVoters) * 100
+		result.Swing[option] = math.Round((postShare-preShare)*10) / 10
+	}
+
+	switch forSwing, againstSwing := result.Swing["For"], result.Swing["Against"]; {
+	case forSwing > againstSwing:
+		result.Winner = "for"
+	case againstSwing > forSwing:
+		result.Winner = "against"
+	default:
+		result.Winner = "draw"
+	}
+	return result
+}
+
+// AudienceResultFor returns a debate's audience verdict, if it has been decided
+func AudienceResultFor(debateID string) *models.AudienceResult {
+	decision, err := GetAudienceDecision(debateID)
+	if err != nil || decision.Result == nil {
+		return nil
+	}
+	return decision.Result
+}
+
+// advanceAudienceDecision moves a debate's audience decision from one of the given states to the
+// next and returns it as it was. Only one caller wins a transition.
+func advanceAudienceDecision(debateID string, from []string, to string) (*models.AudienceDecision, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var decision models.AudienceDecision
+	err := db.GetCollection("audience_decisions").FindOneAndUpdate(ctx,
+		bson.M{"debateId": debateID, "status": bson.M{"$in": from}},
+		bson.M{"$set": bson.M{"status": to, "updatedAt": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.Before),
+	).Decode(&decision)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNoAudienceDecision
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &decision, nil
+}
+
+func setAudienceDecision(debateID string, set bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set["updatedAt"] = time.Now()
+	_, err := db.GetCollection("audience_decisions").UpdateOne(ctx, bson.M{"debateId": debateID}, bson.M{"$set": set})
+	return err
+}
+
+// openAudiencePoll creates one of the opinion polls and announces it to spectators
+func openAudiencePoll(debateID, when, topic string) (string, error) {
+	question := when + ": where do you stand on the motion?"
+	if topic != "" {
+		question = fmt.Sprintf("%s: where do you stand on %q?", when, topic)
+	}
+
+	pollID, err := OpenDebatePoll(debateID, debate.PollMetadata{Question: question, Options: audienceDecisionOptions}, 0)
+	if err != nil {
+		return "", fmt.Errorf("polls are unavailable: %w", err)
+	}
+	return pollID, nil
+}
+
+// closeAudiencePoll closes one of the opinion polls so later votes don't count
+func closeAudiencePoll(debateID, pollID string) {
+	if _, err := CloseDebatePoll(debateID, pollID); err != nil && !errors.Is(err, debate.ErrPollNotFound) {
+		log.Printf("failed to close audience poll %s of debate %s: %v", pollID, debateID, err)
+	}
+}
+
+func audiencePollCounts(debateID, pollID string) map[string]int64 {
+	if pollID == "" {
+		return map[string]int64{}
+	}
+	pollState, _, _, err := debate.NewPollStore().GetPollState(debateID)
+	if err != nil || pollState[pollID] == nil {
+		return map[string]int64{}
+	}
+	return pollState[pollID]
+}
+
+// attachAudienceResult stores the audience's verdict next to the judge's: on the 1v1 result, the
+// team debate and the saved transcripts of the debate
+func attachAudienceResult(debateID string, result models.AudienceResult) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	set := bson.M{"$set": bson.M{"audienceResult": result}}
+	if _, err := db.GetCollection("debate_results").UpdateOne(ctx, bson.M{"roomId": debateID}, set); err != nil {
+		log.Printf("failed to store the audience result on the result of debate %s: %v", debateID, err)
+	}
+	if objectID, err := primitive.ObjectIDFromHex(debateID); err == nil {
+		if _, err := db.GetCollection("team_debates").UpdateOne(ctx, bson.M{"_id": objectID}, set); err != nil {
+			log.Printf("failed to store the audience result on team debate %s: %v", debateID, err)
+		}
+	}
+	if _, err := db.GetCollection("saved_debate_transcripts").UpdateMany(ctx, bson.M{"debateId": debateID}, set); err != nil {
+		log.Printf("failed to store the audience result on the transcripts of debate %s: %v", debateID, err)
+	}
+}
+
+func publishAudienceDecision(decision models.AudienceDecision) {
+	publishSpectatorEvent(decision.DebateID, "audience_decision", decision)
+}
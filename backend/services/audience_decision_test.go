@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestComputeAudienceResult(t *testing.T) {
+	now := time.Now()
+
+	// For goes from 30% to 40% while Against stays at 50%, so For wins
+	result := ComputeAudienceResult(
+		map[string]int64{"For": 30, "Against": 50, "Undecided": 20},
+		map[string]int64{"For": 40, "Against": 50, "Undecided": 10},
+		now,
+	)
+	if result.Winner != "for" {
+		t.Errorf("winner = %q, want %q", result.Winner, "for")
+	}
+	if result.Swing["For"] != 10 || result.Swing["Against"] != 0 || result.Swing["Undecided"] != -10 {
+		t.Errorf("swing = %v, want For 10, Against 0, Undecided -10", result.Swing)
+	}
+	if result.PreVoters != 100 || result.PostVoters != 100 {
+		t.Errorf("voters = %d/%d, want 100/100", result.PreVoters, result.PostVoters)
+	}
+
+	// Shares, not raw counts, decide: Against loses ground even though it gains votes
+	result = ComputeAudienceResult(
+		map[string]int64{"For": 1, "Against": 1},
+		map[string]int64{"For": 3, "Against": 2},
+		now,
+	)
+	if result.Winner != "for" || result.Swing["Against"] != -10 {
+		t.Errorf("got winner %q and swing %v, want for with Against -10", result.Winner, result.Swing)
+	}
+
+	// Votes for options the decision doesn't have don't dilute the real ones
+	result = ComputeAudienceResult(
+		map[string]int64{"For": 1, "Against": 1, "Maybe": 8},
+		map[string]int64{"For": 3, "Against": 2},
+		now,
+	)
+	if result.PreVoters != 2 || result.Swing["Against"] != -10 || len(result.Pre) != 3 {
+		t.Errorf("got %d opening voters and swing %v, want 2 with Against -10", result.PreVoters, result.Swing)
+	}
+
+	if result := ComputeAudienceResult(map[string]int64{"For": 2}, nil, now); result.Winner != "" {
+		t.Errorf("winner with no closing votes = %q, want empty", result.Winner)
+	}
+}
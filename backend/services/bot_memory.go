@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	botMemoryCollection    = "bot_opponent_profiles"
+	maxBotMemoryEntries    = 8
+	maxBotMemoryTopics     = 5
+	maxTranscriptsPerPass  = 5
+	maxMemoryEntryTextSize = 160
+)
+
+// opponentInsights is what a single past debate tells us about a user
+type opponentInsights struct {
+	Arguments  []string `json:"arguments"`
+	Weaknesses []string `json:"weaknesses"`
+}
+
+// GetBotOpponentProfile returns the stored bot memory for a user, or nil if the bots have not met them yet
+func GetBotOpponentProfile(email string) (*models.BotOpponentProfile, error) {
+	if db.MongoDatabase == nil || email == "" {
+		return nil, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var profile models.BotOpponentProfile
+	err := db.MongoDatabase.Collection(botMemoryCollection).FindOne(ctx, bson.M{"email": email}).Decode(&profile)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// InitBotMemoryIndexes creates the unique email index that keeps one bot memory profile per user
+func InitBotMemoryIndexes() error {
+	if db.MongoDatabase == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := db.MongoDatabase.Collection(botMemoryCollection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "email", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// RefreshBotOpponentProfile folds every user-vs-bot transcript saved since the last pass into the user's bot memory
+func RefreshBotOpponentProfile(userID primitive.ObjectID, email string) error {
+	if db.MongoDatabase == nil {
+		return fmt.Errorf("database not initialized")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	profile, err := GetBotOpponentProfile(email)
+	if err != nil {
+		return err
+	}
+	if profile == nil {
+		profile = &models.BotOpponentProfile{
+			UserID: userID,
+			Email:  email,
+		}
+	}
+
+	filter := bson.M{
+		"userId":     userID,
+		"debateType": "user_vs_bot",
+		"createdAt":  bson.M{"$gt": profile.LastAnalyzedAt},
+	}
+	opts := options.Find().SetSort(bson.M{"createdAt": 1}).SetLimit(maxTranscriptsPerPass)
+	cursor, err := db.MongoDatabase.Collection("saved_debate_transcripts").Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	var transcripts []models.SavedDebateTranscript
+	if err := cursor.All(ctx, &transcripts); err != nil {
+		return err
+	}
+	if len(transcripts) == 0 {
+		return nil
+	}
+
+	for _, transcript := range transcripts {
+		insights := extractOpponentInsights(ctx, transcript)
+		profile.Arguments = mergeBotMemoryEntries(profile.Arguments, insights.Arguments, transcript.CreatedAt)
+		profile.Weaknesses = mergeBotMemoryEntries(profile.Weaknesses, insights.Weaknesses, transcript.CreatedAt)
+		profile.RecentTopics = appendRecentTopic(profile.RecentTopics, transcript.Topic)
+		profile.DebatesAnalyzed++
+		switch transcript.Result {
+		case "win":
+			profile.Wins++
+		case "loss":
+			profile.Losses++
+		case "draw":
+			profile.Draws++
+		}
+		profile.LastAnalyzedAt = transcript.CreatedAt
+	}
+	profile.UpdatedAt = time.Now()
+
+	// Keyed on email, so two passes running at once update the same profile instead of adding a second one
+	_, err = db.MongoDatabase.Collection(botMemoryCollection).ReplaceOne(
+		ctx,
+		bson.M{"email": email},
+		profile,
+		options.Replace().SetUpsert(true),
+	)
+	return err
+}
+
+// extractOpponentInsights asks Gemini which arguments the user relied on and where they were weak,
+// falling back to simple heuristics when the model is unavailable.
+func extractOpponentInsights(ctx context.Context, transcript models.SavedDebateTranscript) opponentInsights {
+	var userLines []string
+	for _, msg := range transcript.Messages {
+		if msg.Sender == "User" && strings.TrimSpace(msg.Text) != "" {
+			userLines = append(userLines, msg.Text)
+		}
+	}
+	if len(userLines) == 0 {
+		return opponentInsights{}
+	}
+
+	if geminiClient != nil {
+		prompt := fmt.Sprintf(`You are reviewing a debate a user had against an AI opponent on the topic "%s". The user's result was: %s.
+Identify the arguments the user relies on and the weaknesses the debate exposed in their debating.
+Keep every item short (under 20 words) and phrased generally enough to recognise in future debates.
+
+Return ONLY JSON in this format:
+{
+  "arguments": ["argument the user made"],
+  "weaknesses": ["weakness that was exposed"]
+}
+
+Transcript:
+%s`, transcript.Topic, transcript.Result, FormatHistory(transcript.Messages))
+
+		text, err := generateDefaultModelText(ctx, prompt)
+		if err == nil {
+			var insights opponentInsights
+			if err = json.Unmarshal([]byte(text), &insights); err == nil {
+				return insights
+			}
+		}
+		log.Printf("Falling back to heuristic bot memory extraction: %v", err)
+	}
+
+	return heuristicOpponentInsights(userLines, transcript.Result)
+}
+
+// heuristicOpponentInsights keeps the opening sentence of each user turn as an argument and flags
+// weaknesses that can be spotted without a model.
+func heuristicOpponentInsights(userLines []string, result string) opponentInsights {
+	var insights opponentInsights
+	totalWords := 0
+	evidenceMentions := 0
+	for _, line := range userLines {
+		sentence := strings.TrimSpace(line)
+		if idx := strings.IndexAny(sentence, ".!?"); idx > 0 {
+			sentence = sentence[:idx]
+		}
+		insights.Arguments = append(insights.Arguments, sentence)
+
+		lower := strings.ToLower(line)
+		totalWords += len(strings.Fields(line))
+		for _, marker := range []string{"evidence", "study", "data", "according to", "research", "%"} {
+			if strings.Contains(lower, marker) {
+				evidenceMentions++
+				break
+			}
+		}
+	}
+
+	if totalWords/len(userLines) < 20 {
+		insights.Weaknesses = append(insights.Weaknesses, "Gives brief answers without developing the reasoning")
+	}
+	if evidenceMentions == 0 {
+		insights.Weaknesses = append(insights.Weaknesses, "Rarely backs claims with evidence or examples")
+	}
+	if result == "loss" && len(userLines) < 3 {
+		insights.Weaknesses = append(insights.Weaknesses, "Loses momentum and stops engaging in later phases")
+	}
+	return insights
+}
+
+// mergeBotMemoryEntries adds newly observed items to a memory list, counting repeats and keeping the most frequent ones
+func mergeBotMemoryEntries(existing []models.BotMemoryEntry, observed []string, seenAt time.Time) []models.BotMemoryEntry {
+	index := make(map[string]int, len(existing))
+	for i, entry := range existing {
+		index[normalizeMemoryText(entry.Text)] = i
+	}
+	for _, text := range observed {
+		text = truncateMemoryText(strings.TrimSpace(text))
+		key := normalizeMemoryText(text)
+		if key == "" {
+			continue
+		}
+		if i, ok := index[key]; ok {
+			existing[i].Count++
+			if seenAt.After(existing[i].LastSeen) {
+				existing[i].LastSeen = seenAt
+			}
+			continue
+		}
+		index[key] = len(existing)
+		existing = append(existing, models.BotMemoryEntry{Text: text, Count: 1, LastSeen: seenAt})
+	}
+
+	sort.SliceStable(existing, func(i, j int) bool {
+		if existing[i].Count != existing[j].Count {
+			return existing[i].Count > existing[j].Count
+		}
+		return existing[i].LastSeen.After(existing[j].LastSeen)
+	})
+	if len(existing) > maxBotMemoryEntries {
+		existing = existing[:maxBotMemoryEntries]
+	}
+	return existing
+}
+
+// truncateMemoryText caps an entry at maxMemoryEntryTextSize bytes without splitting a character
+func truncateMemoryText(text string) string {
+	if len(text) <= maxMemoryEntryTextSize {
+		return text
+	}
+	end := maxMemoryEntryTextSize
+	for end > 0 && !utf8.RuneStart(text[end]) {
+		end--
+	}
+	return text[:end]
+}
+
+func normalizeMemoryText(text string) string {
+	return strings.Join(strings.Fields(strings.ToLower(strings.Trim(text, " .!?\"'"))), " ")
+}
+
+func appendRecentTopic(topics []string, topic string) []string {
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return topics
+	}
+	filtered := make([]string, 0, len(topics)+1)
+	filtered = append(filtered, topic)
+	for _, t := range topics {
+		if !strings.EqualFold(t, topic) {
+			filtered = append(filtered, t)
+		}
+	}
+	if len(filtered) > maxBotMemoryTopics {
+		filtered = filtered[:maxBotMemoryTopics]
+	}
+	return filtered
+}
+
+// buildBotMemoryInstruction turns a user's profile into prompt guidance. The more often the
+// user has faced the bots (and the more they have won), the harder the bot leans on what it knows.
+func buildBotMemoryInstruction(profile *models.BotOpponentProfile) string {
+	if profile == nil || profile.DebatesAnalyzed == 0 {
+		return ""
+	}
+
+	experience := profile.DebatesAnalyzed + profile.Wins
+	var intensity string
+	switch {
+	case experience >= 8:
+		intensity = "This opponent is a seasoned regular. Pre-empt their usual arguments before they make them and press relentlessly on every known weakness."
+	case experience >= 4:
+		intensity = "This opponent is returning for a rematch. Anticipate their usual arguments and deliberately target their known weaknesses."
+	default:
+		intensity = "You have met this opponent before. Subtly steer toward their known weaknesses without naming them."
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Memory of this opponent (%d previous debates against bots: %d won, %d lost, %d drawn). %s",
+		profile.DebatesAnalyzed, profile.Wins, profile.Losses, profile.Draws, intensity))
+	if len(profile.Arguments) > 0 {
+		sb.WriteString("\nArguments they tend to use:")
+		for _, entry := range profile.Arguments {
+			sb.WriteString(fmt.Sprintf("\n  - %s", entry.Text))
+		}
+	}
+	if len(profile.Weaknesses) > 0 {
+		sb.WriteString("\nWeaknesses exposed in earlier debates:")
+		for _, entry := range profile.Weaknesses {
+			sb.WriteString(fmt.Sprintf("\n  - %s", entry.Text))
+		}
+	}
+	sb.WriteString("\nStay in character and never mention that you remember previous debates.")
+	return sb.String()
+}
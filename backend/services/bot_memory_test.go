@@ -0,0 +1,64 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestHeuristicOpponentInsights(t *testing.T) {
+	insights := heuristicOpponentInsights([]string{
+		"Schools should ban phones. They distract students all day.",
+		"Teachers lose time policing screens!",
+	}, "loss")
+
+	wantArguments := []string{"Schools should ban phones", "Teachers lose time policing screens"}
+	if len(insights.Arguments) != len(wantArguments) {
+		t.Fatalf("expected %d arguments, got %v", len(wantArguments), insights.Arguments)
+	}
+	for i, want := range wantArguments {
+		if insights.Arguments[i] != want {
+			t.Errorf("argument %d: expected %q, got %q", i, want, insights.Arguments[i])
+		}
+	}
+
+	wantWeaknesses := []string{
+		"Gives brief answers without developing the reasoning",
+		"Rarely backs claims with evidence or examples",
+		"Loses momentum and stops engaging in later phases",
+	}
+	if strings.Join(insights.Weaknesses, "|") != strings.Join(wantWeaknesses, "|") {
+		t.Errorf("expected weaknesses %v, got %v", wantWeaknesses, insights.Weaknesses)
+	}
+
+	long := "According to a recent study, " + strings.Repeat("students who keep their phones in class score lower on tests ", 2)
+	insights = heuristicOpponentInsights([]string{long, long, long}, "loss")
+	if len(insights.Weaknesses) != 0 {
+		t.Errorf("expected no weaknesses for developed, sourced answers, got %v", insights.Weaknesses)
+	}
+}
+
+func TestMergeBotMemoryEntriesTruncatesOnCharacterBoundary(t *testing.T) {
+	text := "a" + strings.Repeat("é", maxMemoryEntryTextSize)
+	entries := mergeBotMemoryEntries(nil, []string{text}, time.Now())
+	if len(entries) != 1 {
+		t.Fatalf("expected one entry, got %d", len(entries))
+	}
+
+	got := entries[0].Text
+	if len(got) > maxMemoryEntryTextSize {
+		t.Errorf("expected at most %d bytes, got %d", maxMemoryEntryTextSize, len(got))
+	}
+	if !utf8.ValidString(got) {
+		t.Errorf("truncated text is not valid UTF-8: %q", got)
+	}
+	if !strings.HasPrefix(text, got) || len(got) < maxMemoryEntryTextSize-1 {
+		t.Errorf("expected the longest whole-character prefix, got %q", got)
+	}
+
+	entries = mergeBotMemoryEntries(entries, []string{text}, time.Now())
+	if len(entries) != 1 || entries[0].Count != 2 {
+		t.Errorf("expected the repeat to be counted on the same entry, got %+v", entries)
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+// debatePhase is one speaking turn in a debate format
+type debatePhase struct {
+	Key         string // Phase key shared with human debates (e.g. "openingFor")
+	Label       string // Phase name the bot prompt understands
+	Side        string // "for" or "against"
+	Instruction string // What a bot speaker should do in the turn
+}
+
+// debateFormats are the phase sequences of the formats team debates and exhibitions are played in
+var debateFormats = map[string][]debatePhase{
+	"standard": {
+		{"openingFor", "Opening Statement", "for", "Introduce the topic, state your stance and outline your key points."},
+		{"openingAgainst", "Opening Statement", "against", "State your stance, outline your key points and respond to your opponent's opening."},
+		{"crossForQuestion", "Cross Examination", "for", "Ask your opponent one pointed question that exposes a weakness in their case."},
+		{"crossAgainstAnswer", "Cross Examination", "against", "Answer your opponent's question directly and precisely."},
+		{"crossAgainstQuestion", "Cross Examination", "against", "Ask your opponent one pointed question that exposes a weakness in their case."},
+		{"crossForAnswer", "Cross Examination", "for", "Answer your opponent's question directly and precisely."},
+		{"closingFor", "Closing Statement", "for", "Summarise the debate and give your strongest final argument."},
+		{"closingAgainst", "Closing Statement", "against", "Summarise the debate and give your strongest final argument."},
+	},
+	"lightning": {
+		{"openingFor", "Opening Statement", "for", "Introduce the topic, state your stance and outline your key points."},
+		{"openingAgainst", "Opening Statement", "against", "State your stance, outline your key points and respond to your opponent's opening."},
+		{"closingFor", "Closing Statement", "for", "Rebut your opponent and give your strongest final argument."},
+		{"closingAgainst", "Closing Statement", "against", "Rebut your opponent and give your strongest final argument."},
+	},
+}
+
+// formatPhases returns the phases of a format, falling back to the standard format for debates
+// created before formats existed
+func formatPhases(format string) []debatePhase {
+	if phases, ok := debateFormats[format]; ok {
+		return phases
+	}
+	return debateFormats["standard"]
+}
+
+// formatPhase looks a phase up in a format
+func formatPhase(format, phaseKey string) (debatePhase, bool) {
+	for _, phase := range formatPhases(format) {
+		if phase.Key == phaseKey {
+			return phase, true
+		}
+	}
+	return debatePhase{}, false
+}
+
+// PhaseSide returns the side ("for" or "against") that speaks in a phase of a format, or "" when
+// the phase is not one of the format's speaking phases
+func PhaseSide(format, phaseKey string) string {
+	if phase, ok := formatPhase(format, phaseKey); ok {
+		return phase.Side
+	}
+	return ""
+}
+
+// phaseKeySide returns the side a phase key speaks for in whichever format has it. Phase keys name
+// the same side in every format, so this serves callers that don't know the debate's format.
+func phaseKeySide(phaseKey string) string {
+	for _, phases := range debateFormats {
+		for _, phase := range phases {
+			if phase.Key == phaseKey {
+				return phase.Side
+			}
+		}
+	}
+	return ""
+}
+
+// NextPhase returns the phase that follows phaseKey in a format: the first speaking phase after
+// "setup", and "finished" after the last one
+func NextPhase(format, phaseKey string) string {
+	phases := formatPhases(format)
+	if phaseKey == "" || phaseKey == "setup" {
+		return phases[0].Key
+	}
+	for i, phase := range phases {
+		if phase.Key == phaseKey && i+1 < len(phases) {
+			return phases[i+1].Key
+		}
+	}
+	return "finished"
+}
@@ -0,0 +1,38 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNextPhaseWalksEachFormat(t *testing.T) {
+	want := map[string]string{
+		"standard":  "openingFor,openingAgainst,crossForQuestion,crossAgainstAnswer,crossAgainstQuestion,crossForAnswer,closingFor,closingAgainst,finished",
+		"lightning": "openingFor,openingAgainst,closingFor,closingAgainst,finished",
+		"":          "openingFor,openingAgainst,crossForQuestion,crossAgainstAnswer,crossAgainstQuestion,crossForAnswer,closingFor,closingAgainst,finished",
+	}
+	for format, sequence := range want {
+		var walked []string
+		for phase := NextPhase(format, "setup"); len(walked) < 20; phase = NextPhase(format, phase) {
+			walked = append(walked, phase)
+			if phase == "finished" {
+				break
+			}
+		}
+		if got := strings.Join(walked, ","); got != sequence {
+			t.Errorf("format %q walks %s, want %s", format, got, sequence)
+		}
+	}
+}
+
+func TestPhaseSideIsPerFormat(t *testing.T) {
+	if side := PhaseSide("standard", "crossAgainstAnswer"); side != "against" {
+		t.Errorf("standard crossAgainstAnswer side = %q, want against", side)
+	}
+	if side := PhaseSide("lightning", "crossAgainstAnswer"); side != "" {
+		t.Errorf("lightning has no cross examination, got side %q", side)
+	}
+	if side := PhaseSide("standard", "finished"); side != "" {
+		t.Errorf("finished is not a speaking phase, got side %q", side)
+	}
+}
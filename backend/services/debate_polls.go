@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	minPollDuration = 15 * time.Second
+	maxPollDuration = 2 * time.Hour
+)
+
+// ErrInvalidPollDuration is returned for a timed poll shorter or longer than allowed
+var ErrInvalidPollDuration = errors.New("polls must run for between 15 seconds and 2 hours")
+
+// OpenDebatePoll creates a poll for a debate's spectators and announces it. A poll with a duration
+// closes itself when the time is up.
+func OpenDebatePoll(debateID string, poll debate.PollMetadata, duration time.Duration) (string, error) {
+	if duration != 0 && (duration < minPollDuration || duration > maxPollDuration) {
+		return "", ErrInvalidPollDuration
+	}
+
+	var closesAt time.Time
+	if duration != 0 {
+		closesAt = time.Now().Add(duration)
+		poll.ClosesAt = closesAt.Unix()
+	}
+
+	pollID, err := debate.NewPollStore().CreatePoll(debateID, poll)
+	if err != nil {
+		return "", err
+	}
+	publishSpectatorEvent(debateID, "poll_created", debate.PollCreatedPayload{
+		PollID:    pollID,
+		Question:  poll.Question,
+		Options:   poll.Options,
+		ClosesAt:  poll.ClosesAt,
+		Timestamp: time.Now().Unix(),
+	})
+
+	if !closesAt.IsZero() {
+		schedulePollClose(debateID, pollID, closesAt)
+	}
+	return pollID, nil
+}
+
+// CloseDebatePoll stops a poll taking votes, archives its final results with the debate and
+// announces them. Closing a poll that is already closed returns its archived results.
+func CloseDebatePoll(debateID, pollID string) (*models.DebatePollResult, error) {
+	store := debate.NewPollStore()
+	closedAt := time.Now()
+	closed, err := store.ClosePoll(debateID, pollID, closedAt)
+	if err != nil {
+		if errors.Is(err, debate.ErrPollNotFound) {
+			// The poll may have expired from the store after it was archived
+			return GetDebatePollResult(debateID, pollID)
+		}
+		return nil, err
+	}
+	if !closed {
+		return GetDebatePollResult(debateID, pollID)
+	}
+
+	result := models.DebatePollResult{
+		DebateID:            debateID,
+		PollID:              pollID,
+		Counts:              map[string]int64{},
+		AuthenticatedCounts: map[string]int64{},
+		ClosedAt:            closedAt,
+	}
+	if pollState, voters, metadata, err := store.GetPollState(debateID); err == nil {
+		meta := metadata[pollID]
+		result.Question = meta.Question
+		result.Options = meta.Options
+		result.CreatedBy = meta.CreatedBy
+		if meta.CreatedAt != 0 {
+			result.CreatedAt = time.Unix(meta.CreatedAt, 0)
+		}
+		if counts := pollState[pollID]; counts != nil {
+			result.Counts = counts
+		}
+		result.Voters = voters[pollID]
+	}
+	if authCounts, err := store.GetAuthenticatedCounts(debateID, []string{pollID}); err == nil && authCounts[pollID] != nil {
+		result.AuthenticatedCounts = authCounts[pollID]
+	}
+
+	if err := archiveDebatePoll(&result); err != nil {
+		log.Printf("failed to archive poll %s of debate %s: %v", pollID, debateID, err)
+	}
+
+	publishSpectatorEvent(debateID, "poll_closed", debate.PollClosedPayload{
+		PollID:              pollID,
+		Counts:              result.Counts,
+		AuthenticatedCounts: result.AuthenticatedCounts,
+		Voters:              result.Voters,
+		ClosedAt:            closedAt.Unix(),
+	})
+	return &result, nil
+}
+
+// CloseExpiredPolls closes a debate's timed polls whose time ran out without their timer firing,
+// e.g. across a restart
+func CloseExpiredPolls(debateID string) {
+	_, _, metadata, err := debate.NewPollStore().GetPollState(debateID)
+	if err != nil {
+		return
+	}
+	now := time.Now().Unix()
+	for pollID, meta := range metadata {
+		if meta.ClosedAt == 0 && meta.ClosesAt != 0 && meta.ClosesAt <= now {
+			if _, err := CloseDebatePoll(debateID, pollID); err != nil {
+				log.Printf("failed to close expired poll %s of debate %s: %v", pollID, debateID, err)
+			}
+		}
+	}
+}
+
+// GetDebatePollMetadata returns a poll that is still in the store
+func GetDebatePollMetadata(debateID, pollID string) (debate.PollMetadata, error) {
+	_, _, metadata, err := debate.NewPollStore().GetPollState(debateID)
+	if err != nil {
+		return debate.PollMetadata{}, err
+	}
+	meta, exists := metadata[pollID]
+	if !exists {
+		return debate.PollMetadata{}, debate.ErrPollNotFound
+	}
+	return meta, nil
+}
+
+// ListDebatePollResults returns the archived results of a debate's closed polls, oldest first
+func ListDebatePollResults(debateID string) ([]models.DebatePollResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := db.GetCollection("debate_polls").Find(ctx,
+		bson.M{"debateId": debateID},
+		options.Find().SetSort(bson.D{{Key: "closedAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	results := []models.DebatePollResult{}
+	if err := cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetDebatePollResult returns the archived results of a closed poll
+func GetDebatePollResult(debateID, pollID string) (*models.DebatePollResult, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var result models.DebatePollResult
+	err := db.GetCollection("debate_polls").FindOne(ctx, bson.M{"debateId": debateID, "pollId": pollID}).Decode(&result)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, debate.ErrPollNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func schedulePollClose(debateID, pollID string, closesAt time.Time) {
+	time.AfterFunc(time.Until(closesAt), func() {
+		if _, err := CloseDebatePoll(debateID, pollID); err != nil && !errors.Is(err, debate.ErrPollNotFound) {
+			log.Printf("failed to close poll %s of debate %s: %v", pollID, debateID, err)
+		}
+	})
+}
+
+func archiveDebatePoll(result *models.DebatePollResult) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.GetCollection("debate_polls").UpdateOne(ctx,
+		bson.M{"debateId": result.DebateID, "pollId": result.PollID},
+		bson.M{"$set": result},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
@@ -0,0 +1,345 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// audienceQuestionsPerPush is how many questions are sent to the debaters
+	audienceQuestionsPerPush = 3
+	maxDebateQuestionLength  = 300
+)
+
+var (
+	ErrInvalidDebateQuestion  = errors.New("question must be between 1 and 300 characters")
+	ErrDebateQuestionNotFound = errors.New("question not found")
+	// ErrDebateQuestionVoted is returned when a spectator upvotes a question they asked or already upvoted
+	ErrDebateQuestionVoted = errors.New("question already upvoted")
+)
+
+// AudienceQuestionPhase returns the phase of a format whose start sends the top approved questions
+// to the debaters, so they can take them up in their closing statements: its first closing phase
+func AudienceQuestionPhase(format string) string {
+	for _, phase := range formatPhases(format) {
+		if phase.Label == "Closing Statement" {
+			return phase.Key
+		}
+	}
+	return ""
+}
+
+// IsDebateHost reports whether the user runs a debate and may moderate its spectators: the host of
+// an exhibition, a captain or co-captain in a team debate, or a debater in a 1v1 room. Calls that
+// favour one side need IsNeutralDebateHost instead.
+func IsDebateHost(debateID string, userID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if objectID, err := primitive.ObjectIDFromHex(debateID); err == nil {
+		var exhibition models.ExhibitionDebate
+		err := db.GetCollection(exhibitionCollection).FindOne(ctx, bson.M{"_id": objectID}).Decode(&exhibition)
+		if err == nil {
+			return exhibition.HostID == userID, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return false, err
+		}
+
+		var teamDebate models.TeamDebate
+		err = db.GetCollection("team_debates").FindOne(ctx, bson.M{"_id": objectID}).Decode(&teamDebate)
+		if err == nil {
+			for _, teamID := range []primitive.ObjectID{teamDebate.Team1ID, teamDebate.Team2ID} {
+				if ok, err := HasTeamPermission(teamID, userID, TeamPermLeadDebate); err == nil && ok {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return false, err
+		}
+	}
+
+	count, err := db.GetCollection("rooms").CountDocuments(ctx, bson.M{
+		"_id": debateID,
+		"$or": []bson.M{{"ownerId": userID.Hex()}, {"participants.id": userID.Hex()}},
+	})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// IsNeutralDebateHost reports whether the user runs a debate without arguing in it, so may make
+// calls that favour one side over the other: the host of an exhibition or the owner of a 1v1 room.
+// Team debates have no neutral host; admins and moderators act for them.
+func IsNeutralDebateHost(debateID string, userID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if objectID, err := primitive.ObjectIDFromHex(debateID); err == nil {
+		var exhibition models.ExhibitionDebate
+		err := db.GetCollection(exhibitionCollection).FindOne(ctx, bson.M{"_id": objectID}).Decode(&exhibition)
+		if err == nil {
+			return exhibition.HostID == userID, nil
+		}
+		if !errors.Is(err, mongo.ErrNoDocuments) {
+			return false, err
+		}
+	}
+
+	count, err := db.GetCollection("rooms").CountDocuments(ctx, bson.M{"_id": debateID, "ownerId": userID.Hex()})
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// SubmitDebateQuestion adds a spectator question to the debate's queue for review. A question
+// matching one already queued counts as an upvote for it instead; duplicate reports which happened.
+func SubmitDebateQuestion(debateID, text, askerHash string) (question *models.DebateQuestion, duplicate bool, err error) {
+	text = strings.TrimSpace(text)
+	normalized := normalizeQuestionText(text)
+	if normalized == "" || len(text) > maxDebateQuestionLength {
+		return nil, false, ErrInvalidDebateQuestion
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	collection := db.GetCollection("debate_questions")
+
+	var existing models.DebateQuestion
+	err = collection.FindOne(ctx, bson.M{
+		"debateId":       debateID,
+		"normalizedText": normalized,
+		"status":         bson.M{"$ne": models.DebateQuestionRejected},
+	}).Decode(&existing)
+	if err == nil {
+		upvoted, err := UpvoteDebateQuestion(debateID, existing.ID, askerHash)
+		if errors.Is(err, ErrDebateQuestionVoted) {
+			return &existing, true, nil
+		}
+		return upvoted, true, err
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, false, err
+	}
+
+	question = &models.DebateQuestion{
+		DebateID:       debateID,
+		Text:           text,
+		NormalizedText: normalized,
+		AskerHash:      askerHash,
+		Status:         models.DebateQuestionPending,
+		Voters:         []string{askerHash},
+		CreatedAt:      time.Now(),
+	}
+	result, err := collection.InsertOne(ctx, question)
+	if err != nil {
+		return nil, false, err
+	}
+	question.ID = result.InsertedID.(primitive.ObjectID)
+	return question, false, nil
+}
+
+// UpvoteDebateQuestion counts a spectator's upvote. Each spectator counts once per question, and
+// rejected questions cannot be upvoted.
+func UpvoteDebateQuestion(debateID string, questionID primitive.ObjectID, voterHash string) (*models.DebateQuestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var question models.DebateQuestion
+	err := db.GetCollection("debate_questions").FindOneAndUpdate(ctx,
+		bson.M{
+			"_id":      questionID,
+			"debateId": debateID,
+			"status":   bson.M{"$ne": models.DebateQuestionRejected},
+			"voters":   bson.M{"$ne": voterHash},
+		},
+		bson.M{"$addToSet": bson.M{"voters": voterHash}, "$inc": bson.M{"upvotes": 1}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&question)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if _, findErr := getDebateQuestion(ctx, debateID, questionID); findErr != nil {
+			return nil, findErr
+		}
+		return nil, ErrDebateQuestionVoted
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if question.Status == models.DebateQuestionApproved {
+		publishQuestionUpdate(question)
+	}
+	return &question, nil
+}
+
+// ListDebateQuestions returns a debate's questions, most upvoted first. Spectators only see
+// approved questions; hosts also see the ones waiting for review and the rejected ones.
+func ListDebateQuestions(debateID string, approvedOnly bool) ([]models.DebateQuestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"debateId": debateID}
+	if approvedOnly {
+		filter["status"] = models.DebateQuestionApproved
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "upvotes", Value: -1}, {Key: "createdAt", Value: 1}})
+	cursor, err := db.GetCollection("debate_questions").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	questions := []models.DebateQuestion{}
+	if err := cursor.All(ctx, &questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// ReviewDebateQuestion approves or rejects a question. Approved questions are shown to spectators.
+func ReviewDebateQuestion(debateID string, questionID, reviewerID primitive.ObjectID, approve bool) (*models.DebateQuestion, error) {
+	status := models.DebateQuestionRejected
+	if approve {
+		status = models.DebateQuestionApproved
+	}
+
+	now := time.Now()
+	question, err := updateDebateQuestion(debateID, questionID, bson.M{
+		"status":     status,
+		"reviewedBy": reviewerID,
+		"reviewedAt": now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if approve {
+		publishSpectatorEvent(debateID, "question", debate.QuestionPayload{
+			QID:           question.ID.Hex(),
+			Text:          question.Text,
+			SpectatorHash: question.AskerHash,
+			Upvotes:       question.Upvotes,
+			Timestamp:     now.Unix(),
+		})
+	} else {
+		publishQuestionUpdate(*question)
+	}
+	return question, nil
+}
+
+// SetDebateQuestionAnswered records whether the debaters have answered a question
+func SetDebateQuestionAnswered(debateID string, questionID primitive.ObjectID, answered bool) (*models.DebateQuestion, error) {
+	set := bson.M{"answered": answered}
+	if answered {
+		set["answeredAt"] = time.Now()
+	}
+	question, err := updateDebateQuestion(debateID, questionID, set)
+	if err != nil {
+		return nil, err
+	}
+	publishQuestionUpdate(*question)
+	return question, nil
+}
+
+// TakeAudienceQuestions marks the top approved questions not yet sent to the debaters as sent and
+// returns them, so each question reaches the debaters once
+func TakeAudienceQuestions(debateID string) ([]models.DebateQuestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := db.GetCollection("debate_questions")
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "upvotes", Value: -1}, {Key: "createdAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var questions []models.DebateQuestion
+	for len(questions) < audienceQuestionsPerPush {
+		var question models.DebateQuestion
+		err := collection.FindOneAndUpdate(ctx,
+			bson.M{
+				"debateId": debateID,
+				"status":   models.DebateQuestionApproved,
+				"answered": false,
+				"pushedAt": bson.M{"$exists": false},
+			},
+			bson.M{"$set": bson.M{"pushedAt": time.Now()}},
+			opts,
+		).Decode(&question)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			break
+		}
+		if err != nil {
+			return questions, err
+		}
+		publishQuestionUpdate(question)
+		questions = append(questions, question)
+	}
+	return questions, nil
+}
+
+func updateDebateQuestion(debateID string, questionID primitive.ObjectID, set bson.M) (*models.DebateQuestion, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var question models.DebateQuestion
+	err := db.GetCollection("debate_questions").FindOneAndUpdate(ctx,
+		bson.M{"_id": questionID, "debateId": debateID},
+		bson.M{"$set": set},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&question)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDebateQuestionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+func getDebateQuestion(ctx context.Context, debateID string, questionID primitive.ObjectID) (*models.DebateQuestion, error) {
+	var question models.DebateQuestion
+	err := db.GetCollection("debate_questions").FindOne(ctx, bson.M{"_id": questionID, "debateId": debateID}).Decode(&question)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrDebateQuestionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+func publishQuestionUpdate(question models.DebateQuestion) {
+	publishSpectatorEvent(question.DebateID, "question_updated", debate.QuestionUpdatePayload{
+		QID:       question.ID.Hex(),
+		Status:    question.Status,
+		Upvotes:   question.Upvotes,
+		Pushed:    question.PushedAt != nil,
+		Answered:  question.Answered,
+		Timestamp: time.Now().Unix(),
+	})
+}
+
+// normalizeQuestionText reduces a question to lower-case words so rewordings in case, spacing or
+// punctuation are treated as duplicates
+func normalizeQuestionText(text string) string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " ")
+}
@@ -0,0 +1,22 @@
+package services
+
+import "testing"
+
+func TestNormalizeQuestionText(t *testing.T) {
+	a := normalizeQuestionText("  What about   the COST?? ")
+	b := normalizeQuestionText("what about the cost")
+	if a != b || a != "what about the cost" {
+		t.Errorf("normalized %q and %q, want both %q", a, b, "what about the cost")
+	}
+	if got := normalizeQuestionText("?!"); got != "" {
+		t.Errorf("normalizeQuestionText(%q) = %q, want empty", "?!", got)
+	}
+}
+
+func TestAudienceQuestionPhaseIsTheFirstClosingPhase(t *testing.T) {
+	for _, format := range []string{"standard", "lightning", ""} {
+		if got := AudienceQuestionPhase(format); got != "closingFor" {
+			t.Errorf("AudienceQuestionPhase(%q) = %q, want closingFor", format, got)
+		}
+	}
+}
@@ -47,6 +47,9 @@ func FormatHistory(history []models.Message) string {
 			phase = "Unspecified Phase"
 		}
 		sb.WriteString(fmt.Sprintf("%s (%s): %s\n", msg.Sender, phase, msg.Text))
+		for _, citation := range msg.Citations {
+			sb.WriteString(fmt.Sprintf("    [%s] cites %s (%s): %s\n", citation.Label, citation.Source, citation.Kind, citation.Excerpt))
+		}
 	}
 	return sb.String()
 }
@@ -66,51 +69,12 @@ func findLastUserMessage(history []models.Message) models.Message {
 	return models.Message{} // Return empty message if history is empty
 }
 
-// inferOpponentStyle infers the opponent's debating style based on their latest message
-func inferOpponentStyle(message string) string {
-	message = strings.ToLower(message)
-	aggressiveWords := []string{"ridiculous", "absurd", "nonsense", "prove it", "wrong"}
-	logicalWords := []string{"evidence", "data", "logic", "reason", "study"}
-	emotionalWords := []string{"feel", "heart", "believe", "hope", "fear"}
-	confidentWords := []string{"obvious", "clearly", "definitely", "certain"}
-	irrationalWords := []string{"random", "guess", "whatever", "who cares"}
-
-	count := func(words []string) int {
-		n := 0
-		for _, word := range words {
-			if strings.Contains(message, word) {
-				n++
-			}
-		}
-		return n
-	}
-
-	aggressiveScore := count(aggressiveWords)
-	logicalScore := count(logicalWords)
-	emotionalScore := count(emotionalWords)
-	confidentScore := count(confidentWords)
-	irrationalScore := count(irrationalWords)
-
-	switch {
-	case aggressiveScore >= 2:
-		return "Aggressive opponent"
-	case logicalScore >= 2:
-		return "Logical opponent"
-	case emotionalScore >= 2:
-		return "Emotional opponent"
-	case confidentScore >= 2:
-		return "Confident opponent"
-	case irrationalScore >= 2:
-		return "Irrational opponent"
-	default:
-		return "Neutral opponent"
-	}
-}
-
 // constructPrompt builds a prompt that adjusts based on bot personality, debate topic, history,
 // extra context, and uses the provided stance directly. It includes phase-specific instructions
-// and leverages InteractionModifiers and PhilosophicalTenets for tailored responses.
-func constructPrompt(bot BotPersonality, topic string, history []models.Message, stance, extraContext string, maxWords int) string {
+// and leverages InteractionModifiers and PhilosophicalTenets for tailored responses. When the bots
+// have met this user before, their remembered arguments and weaknesses are folded in as well, and
+// any retrieved evidence passages are offered for citation.
+func constructPrompt(bot BotPersonality, topic string, history []models.Message, stance, extraContext string, maxWords int, memory *models.BotOpponentProfile, sources []models.Citation) string {
 	// Level-based instructions
 	levelInstructions := ""
 	switch strings.ToLower(bot.Level) {
@@ -150,18 +114,25 @@ Your responses must reflect this persona consistently, as if you are the charact
 		strings.Join(bot.PhilosophicalTenets, ", "), strings.Join(bot.UniverseTies, ", "), bot.ExampleDialogue,
 	)
 
-	// Interaction modifier based on opponent's style
-	opponentStyle := "Neutral opponent"
+	// Interaction modifier based on the structure of the opponent's latest argument
+	var analysis *models.ArgumentAnalysis
 	if len(history) > 0 {
 		lastUserMsg := findLastUserMessage(history)
-		if lastUserMsg.Text != "" {
-			opponentStyle = inferOpponentStyle(lastUserMsg.Text)
+		if lastUserMsg.Sender == "User" && lastUserMsg.Text != "" {
+			analysis = lastUserMsg.Analysis
+			if analysis == nil {
+				analysis = heuristicArgumentAnalysis(lastUserMsg.Text)
+			}
 		}
 	}
+	opponentStyle := classifyOpponentStyle(analysis)
 	modifierInstruction := ""
 	if modifier, ok := bot.InteractionModifiers[opponentStyle]; ok {
 		modifierInstruction = fmt.Sprintf("Adjust your response based on the opponent’s style (%s): %s", opponentStyle, modifier)
 	}
+	if analysisInstruction := formatArgumentAnalysis(analysis); analysisInstruction != "" {
+		modifierInstruction = strings.TrimSpace(modifierInstruction + "\n" + analysisInstruction)
+	}
 
 	// Word limit instruction
 	limitInstruction := ""
@@ -169,6 +140,12 @@ Your responses must reflect this persona consistently, as if you are the charact
 		limitInstruction = fmt.Sprintf("Limit your response to %d words.", maxWords)
 	}
 
+	// Memory of previous debates with this user
+	memoryInstruction := buildBotMemoryInstruction(memory)
+
+	// Evidence retrieved from the admin-curated packs
+	evidenceInstruction := formatEvidenceForPrompt(sources)
+
 	// Base instruction for all responses
 	baseInstruction := "Provide only your own argument without simulating an opponent’s dialogue. " +
 		"If the user’s input is unclear, off-topic, or empty, respond with a personality-appropriate clarification request, e.g., for Yoda: 'Clouded, your point is, young one. Clarify, you must.'"
@@ -186,6 +163,7 @@ Your stance is: %s.
 %s
 %s
 %s
+%s
 Provide an opening statement that embodies your persona and stance.
 [Your opening argument]
 %s %s`,
@@ -200,6 +178,8 @@ Provide an opening statement that embodies your persona and stance.
 				}
 				return ""
 			}(),
+			memoryInstruction,
+			evidenceInstruction,
 			phaseInstruction,
 			limitInstruction, baseInstruction,
 		)
@@ -240,6 +220,8 @@ Your debating style must strictly adhere to the following guidelines:
 Your stance is: %s.
 %s
 %s
+%s
+%s
 Based on the debate transcript below, continue the discussion in the %s phase by responding directly to the user’s message.
 User’s message: "%s"
 %s
@@ -257,6 +239,8 @@ Please provide your full argument.`,
 			}
 			return ""
 		}(),
+		memoryInstruction,
+		evidenceInstruction,
 		phaseInstruction,
 		currentPhase,
 		userText,
@@ -266,28 +250,31 @@ Please provide your full argument.`,
 }
 
 // GenerateBotResponse generates a response from the debate bot using the Gemini client library.
-// It uses the bot’s personality to handle errors and responses vividly.
-func GenerateBotResponse(botName, botLevel, topic string, history []models.Message, stance, extraContext string, maxWords int) string {
+// It uses the bot’s personality to handle errors and responses vividly. memory may be nil when
+// the bots have no history with the user. The returned citations are the evidence passages the
+// bot actually referenced in its response.
+func GenerateBotResponse(botName, botLevel, topic string, history []models.Message, stance, extraContext string, maxWords int, memory *models.BotOpponentProfile) (string, []models.Citation) {
 	if geminiClient == nil {
-		return personalityErrorResponse(botName, "My systems are offline, it seems.")
+		return personalityErrorResponse(botName, "My systems are offline, it seems."), nil
 	}
 
 	bot := GetBotPersonality(botName)
+	sources := RetrieveEvidence(topic, stance, findLastUserMessage(history).Text, botEvidencePassages)
 	// Construct prompt with enhanced personality integration
-	prompt := constructPrompt(bot, topic, history, stance, extraContext, maxWords)
+	prompt := constructPrompt(bot, topic, history, stance, extraContext, maxWords, memory, sources)
 
 	ctx := context.Background()
 	response, err := generateDefaultModelText(ctx, prompt)
 	if err != nil {
-		return personalityErrorResponse(botName, "A glitch in my logic, there is.")
+		return personalityErrorResponse(botName, "A glitch in my logic, there is."), nil
 	}
 	if response == "" {
-		return personalityErrorResponse(botName, "Lost in translation, my thoughts are.")
+		return personalityErrorResponse(botName, "Lost in translation, my thoughts are."), nil
 	}
 	if strings.Contains(strings.ToLower(response), "clarify") {
-		return personalityClarificationRequest(botName)
+		return personalityClarificationRequest(botName), nil
 	}
-	return response
+	return response, extractCitations(response, sources)
 }
 
 // personalityErrorResponse returns a personality-specific error message
@@ -415,6 +402,10 @@ Judgment Criteria:
    - Effective reiteration of stance
    - Persuasiveness of final argument, embodying bot’s philosophical tenets (%s)
 
+Evidence: Messages may cite evidence with markers such as [E1]; the cited source and excerpt are listed beneath the message.
+Credit claims that are genuinely supported by relevant, credible cited evidence, penalise citations that do not support the claim they are attached to,
+and give less weight to factual claims made by either side without any support.
+
 Required Output Format:
 {
   "opening_statement": {
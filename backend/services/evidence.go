@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/evidence"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	evidencePackCollection = "evidence_packs"
+	// botEvidencePassages is how many passages are offered to the bot for each turn
+	botEvidencePassages = 4
+)
+
+var (
+	evidenceIndex       = evidence.NewIndex()
+	citationMarkerRegex = regexp.MustCompile(`\[(E\d+)\]`)
+	validEvidenceKinds  = map[string]bool{"article": true, "statistic": true, "quote": true}
+)
+
+// InitEvidenceIndex loads every stored evidence pack into the local retrieval index
+func InitEvidenceIndex() error {
+	if db.MongoDatabase == nil {
+		return errors.New("database not initialized")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cursor, err := db.MongoDatabase.Collection(evidencePackCollection).Find(ctx, bson.M{})
+	if err != nil {
+		return err
+	}
+	var packs []models.EvidencePack
+	if err := cursor.All(ctx, &packs); err != nil {
+		return err
+	}
+	for _, pack := range packs {
+		indexEvidencePack(pack)
+	}
+	log.Printf("Evidence index loaded: %d packs, %d passages", len(packs), evidenceIndex.Size())
+	return nil
+}
+
+// CreateEvidencePack validates, stores and indexes a new evidence pack
+func CreateEvidencePack(pack *models.EvidencePack) error {
+	if err := normalizeEvidencePack(pack); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	pack.ID = primitive.NewObjectID()
+	pack.CreatedAt = now
+	pack.UpdatedAt = now
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if _, err := db.MongoDatabase.Collection(evidencePackCollection).InsertOne(ctx, pack); err != nil {
+		return err
+	}
+	indexEvidencePack(*pack)
+	return nil
+}
+
+// normalizeEvidencePack validates a new pack and fills in defaults. Passages without an ID get the
+// first free "p<n>" ID, so they never collide with IDs the caller chose.
+func normalizeEvidencePack(pack *models.EvidencePack) error {
+	pack.Topic = strings.TrimSpace(pack.Topic)
+	if pack.Topic == "" {
+		return errors.New("invalid evidence pack: topic is required")
+	}
+	if len(pack.Passages) == 0 {
+		return errors.New("invalid evidence pack: at least one passage is required")
+	}
+
+	taken := make(map[string]bool, len(pack.Passages))
+	for i := range pack.Passages {
+		passage := &pack.Passages[i]
+		passage.Text = strings.TrimSpace(passage.Text)
+		if passage.Text == "" {
+			return fmt.Errorf("invalid evidence pack: passage %d has no text", i+1)
+		}
+		if strings.TrimSpace(passage.Source) == "" {
+			return fmt.Errorf("invalid evidence pack: passage %d has no source", i+1)
+		}
+		passage.Kind = strings.ToLower(strings.TrimSpace(passage.Kind))
+		if passage.Kind == "" {
+			passage.Kind = "article"
+		}
+		if !validEvidenceKinds[passage.Kind] {
+			return fmt.Errorf("invalid evidence pack: passage %d has invalid kind %q", i+1, passage.Kind)
+		}
+		passage.Stance = evidence.NormalizeStance(passage.Stance)
+		passage.ID = strings.TrimSpace(passage.ID)
+		if passage.ID == "" {
+			continue
+		}
+		if taken[passage.ID] {
+			return fmt.Errorf("invalid evidence pack: passage %d reuses ID %q", i+1, passage.ID)
+		}
+		taken[passage.ID] = true
+	}
+
+	next := 1
+	for i := range pack.Passages {
+		passage := &pack.Passages[i]
+		if passage.ID != "" {
+			continue
+		}
+		for taken[fmt.Sprintf("p%d", next)] {
+			next++
+		}
+		passage.ID = fmt.Sprintf("p%d", next)
+		taken[passage.ID] = true
+	}
+
+	if pack.Title == "" {
+		pack.Title = pack.Topic
+	}
+	return nil
+}
+
+// ListEvidencePacks returns stored evidence packs, optionally filtered by a topic substring
+func ListEvidencePacks(topic string) ([]models.EvidencePack, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if topic = strings.TrimSpace(topic); topic != "" {
+		filter["topic"] = bson.M{"$regex": regexp.QuoteMeta(topic), "$options": "i"}
+	}
+	cursor, err := db.MongoDatabase.Collection(evidencePackCollection).Find(ctx, filter, options.Find().SetSort(bson.M{"createdAt": -1}))
+	if err != nil {
+		return nil, err
+	}
+	packs := []models.EvidencePack{}
+	if err := cursor.All(ctx, &packs); err != nil {
+		return nil, err
+	}
+	return packs, nil
+}
+
+// DeleteEvidencePack removes a pack from storage and from the retrieval index
+func DeleteEvidencePack(packID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	result, err := db.MongoDatabase.Collection(evidencePackCollection).DeleteOne(ctx, bson.M{"_id": packID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return errors.New("evidence pack not found")
+	}
+	evidenceIndex.RemovePack(packID.Hex())
+	return nil
+}
+
+func indexEvidencePack(pack models.EvidencePack) {
+	docs := make([]evidence.Document, 0, len(pack.Passages))
+	for _, passage := range pack.Passages {
+		docs = append(docs, evidence.Document{
+			PassageID: passage.ID,
+			Topic:     pack.Topic,
+			Kind:      passage.Kind,
+			Text:      passage.Text,
+			Source:    passage.Source,
+			URL:       passage.URL,
+			Stance:    passage.Stance,
+		})
+	}
+	evidenceIndex.AddPack(pack.ID.Hex(), docs)
+}
+
+// RetrieveEvidence finds the passages most relevant to the topic and the point being answered,
+// restricted to the given stance, and labels them E1, E2, ... for citation.
+func RetrieveEvidence(topic, stance, query string, limit int) []models.Citation {
+	results := evidenceIndex.Search(topic+" "+query, stance, limit)
+	citations := make([]models.Citation, 0, len(results))
+	for i, r := range results {
+		citations = append(citations, models.Citation{
+			Label:     fmt.Sprintf("E%d", i+1),
+			PackID:    r.PackID,
+			PassageID: r.PassageID,
+			Kind:      r.Kind,
+			Source:    r.Source,
+			URL:       r.URL,
+			Excerpt:   r.Text,
+		})
+	}
+	return citations
+}
+
+// formatEvidenceForPrompt lists the retrieved passages the bot is allowed to cite
+func formatEvidenceForPrompt(candidates []models.Citation) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	sb.WriteString("Evidence you may cite (cite by writing its marker, e.g. [E1], right after the claim it supports; never invent sources or markers):")
+	for _, c := range candidates {
+		sb.WriteString(fmt.Sprintf("\n[%s] (%s, %s) %s", c.Label, c.Kind, c.Source, c.Excerpt))
+	}
+	return sb.String()
+}
+
+// extractCitations returns the candidates whose markers actually appear in the bot's response
+func extractCitations(response string, candidates []models.Citation) []models.Citation {
+	if len(candidates) == 0 {
+		return nil
+	}
+	byLabel := make(map[string]models.Citation, len(candidates))
+	for _, c := range candidates {
+		byLabel[c.Label] = c
+	}
+	var cited []models.Citation
+	seen := make(map[string]bool)
+	for _, match := range citationMarkerRegex.FindAllStringSubmatch(response, -1) {
+		label := match[1]
+		if c, ok := byLabel[label]; ok && !seen[label] {
+			seen[label] = true
+			cited = append(cited, c)
+		}
+	}
+	return cited
+}
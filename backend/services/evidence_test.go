@@ -0,0 +1,47 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"arguehub/models"
+)
+
+func TestNormalizeEvidencePackSkipsTakenPassageIDs(t *testing.T) {
+	pack := models.EvidencePack{
+		Topic: " Remote work ",
+		Passages: []models.EvidencePassage{
+			{Text: "Remote workers reported higher productivity.", Source: "Survey"},
+			{ID: "p1", Text: "Commuting accounts for urban emissions.", Source: "Report"},
+			{Text: "Isolation rises among remote employees.", Source: "Study", Kind: "Statistic"},
+		},
+	}
+	if err := normalizeEvidencePack(&pack); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var ids []string
+	for _, passage := range pack.Passages {
+		ids = append(ids, passage.ID)
+	}
+	if got := strings.Join(ids, ","); got != "p2,p1,p3" {
+		t.Errorf("expected generated IDs to skip the caller's p1, got %s", got)
+	}
+	if pack.Title != "Remote work" || pack.Passages[0].Kind != "article" || pack.Passages[2].Kind != "statistic" {
+		t.Errorf("expected defaults to be filled in, got %+v", pack)
+	}
+}
+
+func TestNormalizeEvidencePackRejectsDuplicatePassageIDs(t *testing.T) {
+	pack := models.EvidencePack{
+		Topic: "Remote work",
+		Passages: []models.EvidencePassage{
+			{ID: "stat", Text: "Remote workers reported higher productivity.", Source: "Survey"},
+			{ID: "stat", Text: "Commuting accounts for urban emissions.", Source: "Report"},
+		},
+	}
+	err := normalizeEvidencePack(&pack)
+	if err == nil || !strings.HasPrefix(err.Error(), "invalid evidence pack") {
+		t.Errorf("expected a validation error for a reused passage ID, got %v", err)
+	}
+}
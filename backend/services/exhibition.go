@@ -0,0 +1,417 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"arguehub/db"
+	"arguehub/internal/debate"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	exhibitionCollection = "exhibition_debates"
+	exhibitionPollID     = "winner"
+	exhibitionMaxWords   = 120
+)
+
+// exhibitionTurnDelay is the pause between turns so spectators can read, react and vote
+const exhibitionTurnDelay = 8 * time.Second
+
+// SpectatorEventPublisher delivers an event to everyone watching a debate
+type SpectatorEventPublisher func(debateID string, event *debate.Event)
+
+var (
+	spectatorEventPublisher SpectatorEventPublisher
+	runningExhibitions      = make(map[string]bool)
+	runningExhibitionsMu    sync.Mutex
+)
+
+// SetSpectatorEventPublisher sets the function used to stream events to spectators
+func SetSpectatorEventPublisher(publisher SpectatorEventPublisher) {
+	spectatorEventPublisher = publisher
+}
+
+func publishSpectatorEvent(debateID, eventType string, payload interface{}) {
+	event, err := debate.NewEvent(eventType, payload)
+	if err != nil {
+		return
+	}
+	if spectatorEventPublisher != nil {
+		spectatorEventPublisher(debateID, event)
+		return
+	}
+	debate.PublishEvent(debateID, event)
+}
+
+// ExhibitionFormats returns the names of the supported exhibition formats
+func ExhibitionFormats() []string {
+	return []string{"standard", "lightning"}
+}
+
+// StartExhibition creates a bot-vs-bot exhibition and runs it in the background
+func StartExhibition(hostID primitive.ObjectID, hostEmail, topic, forBot, againstBot, format string) (*models.ExhibitionDebate, error) {
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	if format == "" {
+		format = "standard"
+	}
+	if _, ok := debateFormats[format]; !ok {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	for _, name := range []string{forBot, againstBot} {
+		if GetBotPersonality(name).Name != name {
+			return nil, fmt.Errorf("unknown bot %q", name)
+		}
+	}
+	if forBot == againstBot {
+		return nil, errors.New("an exhibition needs two different bots")
+	}
+	if geminiClient == nil {
+		return nil, errors.New("bot debates are unavailable: AI service not configured")
+	}
+
+	runningExhibitionsMu.Lock()
+	if runningExhibitions[hostID.Hex()] {
+		runningExhibitionsMu.Unlock()
+		return nil, errors.New("you already have an exhibition running")
+	}
+	runningExhibitions[hostID.Hex()] = true
+	runningExhibitionsMu.Unlock()
+
+	exhibition := &models.ExhibitionDebate{
+		ID:          primitive.NewObjectID(),
+		Topic:       topic,
+		Format:      format,
+		ForBot:      forBot,
+		AgainstBot:  againstBot,
+		Status:      "running",
+		Messages:    []models.Message{},
+		Transcripts: map[string]string{},
+		HostID:      hostID,
+		HostEmail:   hostEmail,
+		CreatedAt:   time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := db.MongoDatabase.Collection(exhibitionCollection).InsertOne(ctx, exhibition); err != nil {
+		releaseExhibitionHost(hostID)
+		return nil, err
+	}
+
+	go runExhibition(*exhibition)
+	return exhibition, nil
+}
+
+func releaseExhibitionHost(hostID primitive.ObjectID) {
+	runningExhibitionsMu.Lock()
+	delete(runningExhibitions, hostID.Hex())
+	runningExhibitionsMu.Unlock()
+}
+
+// GetExhibition loads a single exhibition
+func GetExhibition(id primitive.ObjectID) (*models.ExhibitionDebate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var exhibition models.ExhibitionDebate
+	if err := db.MongoDatabase.Collection(exhibitionCollection).FindOne(ctx, bson.M{"_id": id}).Decode(&exhibition); err != nil {
+		return nil, err
+	}
+	return &exhibition, nil
+}
+
+// ListExhibitions returns recent exhibitions, optionally filtered by status
+func ListExhibitions(status string, limit int64) ([]models.ExhibitionDebate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.M{"createdAt": -1}).SetLimit(limit).SetProjection(bson.M{"messages": 0, "transcripts": 0})
+	cursor, err := db.MongoDatabase.Collection(exhibitionCollection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	exhibitions := []models.ExhibitionDebate{}
+	if err := cursor.All(ctx, &exhibitions); err != nil {
+		return nil, err
+	}
+	return exhibitions, nil
+}
+
+// runExhibition plays every phase of the format, then judges and archives the debate
+func runExhibition(exhibition models.ExhibitionDebate) {
+	defer releaseExhibitionHost(exhibition.HostID)
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Panic in exhibition %s: %v", exhibition.ID.Hex(), r)
+			updateExhibition(exhibition.ID, bson.M{"status": "failed"})
+		}
+	}()
+
+	debateID := exhibition.ID.Hex()
+	phases := debateFormats[exhibition.Format]
+
+	publishSpectatorEvent(debateID, "exhibition_started", map[string]interface{}{
+		"exhibitionId": debateID,
+		"topic":        exhibition.Topic,
+		"format":       exhibition.Format,
+		"forBot":       exhibition.ForBot,
+		"againstBot":   exhibition.AgainstBot,
+		"phases":       len(phases),
+	})
+
+	// Let the audience predict the winner while the bots debate
+	poll := debate.PollMetadata{
+		PollID:   exhibitionPollID,
+		Question: fmt.Sprintf("Who wins: %s or %s?", exhibition.ForBot, exhibition.AgainstBot),
+		Options:  []string{exhibition.ForBot, exhibition.AgainstBot},
+	}
+	if pollID, err := OpenDebatePoll(debateID, poll, 0); err == nil {
+		exhibition.PollID = pollID
+		updateExhibition(exhibition.ID, bson.M{"pollId": pollID})
+	}
+
+	for _, phase := range phases {
+		botName := exhibition.ForBot
+		if phase.Side == "against" {
+			botName = exhibition.AgainstBot
+		}
+		bot := GetBotPersonality(botName)
+
+		updateExhibition(exhibition.ID, bson.M{"currentPhase": phase.Key})
+		publishSpectatorEvent(debateID, "exhibition_phase", map[string]interface{}{
+			"phase":   phase.Key,
+			"label":   phase.Label,
+			"speaker": botName,
+			"side":    phase.Side,
+		})
+
+		turnContext := fmt.Sprintf("You are debating another AI personality in a public exhibition. This turn is the %s (%s). %s",
+			phase.Label, phase.Key, phase.Instruction)
+		history := exhibitionHistoryFor(exhibition.Messages, botName)
+		text, citations := GenerateBotResponse(botName, bot.Level, exhibition.Topic, history, phase.Side, turnContext, exhibitionMaxWords, nil)
+
+		message := models.Message{
+			Sender:    botName,
+			Text:      text,
+			Phase:     phase.Label,
+			Citations: citations,
+		}
+		exhibition.Messages = append(exhibition.Messages, message)
+		exhibition.Transcripts[phase.Key] = text
+
+		appendExhibitionTurn(exhibition.ID, phase.Key, message)
+
+		publishSpectatorEvent(debateID, "exhibition_turn", map[string]interface{}{
+			"phase":     phase.Key,
+			"label":     phase.Label,
+			"speaker":   botName,
+			"side":      phase.Side,
+			"text":      text,
+			"citations": citations,
+		})
+
+		time.Sleep(exhibitionTurnDelay)
+	}
+
+	finishExhibition(&exhibition)
+}
+
+// exhibitionHistoryFor relabels the transcript from one bot's point of view, so the bot prompt
+// treats its opponent's turns as the "User" it is responding to.
+func exhibitionHistoryFor(messages []models.Message, botName string) []models.Message {
+	history := make([]models.Message, 0, len(messages))
+	for _, msg := range messages {
+		sender := "User"
+		if msg.Sender == botName {
+			sender = "Bot"
+		}
+		history = append(history, models.Message{
+			Sender:    sender,
+			Text:      msg.Text,
+			Phase:     msg.Phase,
+			Citations: msg.Citations,
+		})
+	}
+	return history
+}
+
+// finishExhibition judges the debate, records the audience vote and archives it as a public post
+func finishExhibition(exhibition *models.ExhibitionDebate) {
+	debateID := exhibition.ID.Hex()
+
+	result := JudgeDebateHumanVsHuman(exhibition.Transcripts)
+	if !isLikelyJSONResult(result) {
+		result = buildFallbackJudgeResult(exhibition.Transcripts)
+	}
+	exhibition.Result = result
+	exhibition.Winner = exhibitionWinner(result, exhibition.ForBot, exhibition.AgainstBot)
+
+	if exhibition.PollID != "" {
+		if pollResult, err := CloseDebatePoll(debateID, exhibition.PollID); err == nil {
+			exhibition.AudienceVote = pollResult.Counts
+		}
+	}
+
+	now := time.Now()
+	exhibition.Status = "finished"
+	exhibition.FinishedAt = &now
+
+	if transcriptID, postID, err := archiveExhibition(exhibition); err != nil {
+		log.Printf("Failed to archive exhibition %s: %v", debateID, err)
+	} else {
+		exhibition.TranscriptID = transcriptID
+		exhibition.PostID = postID
+	}
+
+	update := bson.M{
+		"status":       exhibition.Status,
+		"currentPhase": "finished",
+		"result":       exhibition.Result,
+		"winner":       exhibition.Winner,
+		"audienceVote": exhibition.AudienceVote,
+		"finishedAt":   now,
+	}
+	if !exhibition.TranscriptID.IsZero() {
+		update["transcriptId"] = exhibition.TranscriptID
+		update["postId"] = exhibition.PostID
+	}
+	updateExhibition(exhibition.ID, update)
+
+	var judgeResult interface{}
+	if err := json.Unmarshal([]byte(result), &judgeResult); err != nil {
+		judgeResult = result
+	}
+	publishSpectatorEvent(debateID, "exhibition_finished", map[string]interface{}{
+		"winner":       exhibition.Winner,
+		"result":       judgeResult,
+		"audienceVote": exhibition.AudienceVote,
+		"postId":       exhibition.PostID.Hex(),
+	})
+}
+
+// exhibitionWinner maps the judge's For/Against verdict onto the bot names
+func exhibitionWinner(result, forBot, againstBot string) string {
+	var judged struct {
+		Verdict struct {
+			Winner string `json:"winner"`
+		} `json:"verdict"`
+	}
+	if err := json.Unmarshal([]byte(result), &judged); err != nil {
+		return "draw"
+	}
+	switch strings.ToLower(strings.TrimSpace(judged.Verdict.Winner)) {
+	case "for":
+		return forBot
+	case "against":
+		return againstBot
+	default:
+		return "draw"
+	}
+}
+
+// archiveExhibition stores the transcript and publishes it to the community feed on behalf of the host.
+// The transcript has no owning user so it does not appear in anyone's personal debate history.
+func archiveExhibition(exhibition *models.ExhibitionDebate) (primitive.ObjectID, primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	matchup := fmt.Sprintf("%s vs %s", exhibition.ForBot, exhibition.AgainstBot)
+	now := time.Now()
+	transcript := models.SavedDebateTranscript{
+		ID:          primitive.NewObjectID(),
+		DebateType:  "bot_vs_bot",
+		Topic:       exhibition.Topic,
+		Opponent:    matchup,
+		Result:      exhibition.Winner,
+		Messages:    exhibition.Messages,
+		Transcripts: exhibition.Transcripts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := db.MongoDatabase.Collection("saved_debate_transcripts").InsertOne(ctx, transcript); err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, err
+	}
+
+	var host models.User
+	if err := db.MongoDatabase.Collection("users").FindOne(ctx, bson.M{"_id": exhibition.HostID}).Decode(&host); err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, err
+	}
+
+	post := models.DebatePost{
+		ID:           primitive.NewObjectID(),
+		TranscriptID: transcript.ID,
+		UserID:       host.ID,
+		Email:        host.Email,
+		DisplayName:  host.DisplayName,
+		AvatarURL:    host.AvatarURL,
+		Topic:        exhibition.Topic,
+		DebateType:   "bot_vs_bot",
+		Opponent:     matchup,
+		Result:       exhibition.Winner,
+		IsPublic:     true,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if _, err := db.MongoDatabase.Collection("debate_posts").InsertOne(ctx, post); err != nil {
+		return transcript.ID, primitive.NilObjectID, err
+	}
+	return transcript.ID, post.ID, nil
+}
+
+func updateExhibition(id primitive.ObjectID, fields bson.M) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := db.MongoDatabase.Collection(exhibitionCollection).UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": fields}); err != nil {
+		log.Printf("Failed to update exhibition %s: %v", id.Hex(), err)
+	}
+}
+
+// appendExhibitionTurn stores one bot's speech as it is given
+func appendExhibitionTurn(id primitive.ObjectID, phaseKey string, message models.Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := db.MongoDatabase.Collection(exhibitionCollection).UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$push": bson.M{"messages": message},
+		"$set":  bson.M{"transcripts." + phaseKey: message.Text},
+	})
+	if err != nil {
+		log.Printf("Failed to save %s turn of exhibition %s: %v", phaseKey, id.Hex(), err)
+	}
+}
+
+// FailInterruptedExhibitions marks exhibitions left running by a previous process as failed. The
+// goroutine that played them is gone, so they would otherwise show as running forever.
+func FailInterruptedExhibitions() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.MongoDatabase.Collection(exhibitionCollection).UpdateMany(ctx,
+		bson.M{"status": "running"},
+		bson.M{"$set": bson.M{"status": "failed"}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.ModifiedCount > 0 {
+		log.Printf("Marked %d interrupted exhibitions as failed", result.ModifiedCount)
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"testing"
+
+	"arguehub/models"
+)
+
+func TestExhibitionWinnerMapsVerdictToBots(t *testing.T) {
+	cases := []struct {
+		result string
+		want   string
+	}{
+		{`{"verdict":{"winner":"For"}}`, "Rookie Rick"},
+		{`{"verdict":{"winner":" against "}}`, "Casual Casey"},
+		{`{"verdict":{"winner":"Draw"}}`, "draw"},
+		{`not json`, "draw"},
+	}
+	for _, c := range cases {
+		if got := exhibitionWinner(c.result, "Rookie Rick", "Casual Casey"); got != c.want {
+			t.Errorf("exhibitionWinner(%q) = %q, want %q", c.result, got, c.want)
+		}
+	}
+}
+
+func TestExhibitionHistoryForRelabelsTurns(t *testing.T) {
+	messages := []models.Message{
+		{Sender: "Rookie Rick", Text: "Cars are loud.", Phase: "openingFor"},
+		{Sender: "Casual Casey", Text: "Cars carry goods.", Phase: "openingAgainst"},
+	}
+
+	history := exhibitionHistoryFor(messages, "Casual Casey")
+
+	if len(history) != 2 || history[0].Sender != "User" || history[1].Sender != "Bot" {
+		t.Fatalf("expected the opponent as User and the bot as Bot, got %+v", history)
+	}
+	if history[0].Text != "Cars are loud." || history[1].Phase != "openingAgainst" {
+		t.Errorf("expected text and phase to be kept, got %+v", history)
+	}
+	if messages[0].Sender != "Rookie Rick" {
+		t.Error("expected the stored transcript to be left alone")
+	}
+}
+
+func TestExhibitionFormatsHavePhases(t *testing.T) {
+	for _, format := range ExhibitionFormats() {
+		if len(debateFormats[format]) == 0 {
+			t.Errorf("exhibition format %q has no phases", format)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Live debate types
+const (
+	LiveDebateOneOnOne   = "1v1"
+	LiveDebateTeam       = "team"
+	LiveDebateExhibition = "exhibition"
+)
+
+// Live directory sort orders
+const (
+	LiveDebatesBySpectators = "spectators"
+	LiveDebatesByRating     = "rating"
+	LiveDebatesByNewest     = "newest"
+)
+
+const (
+	defaultLiveDebateLimit = 50
+	maxLiveDebateLimit     = 100
+	// exhibitionStaleAfter hides exhibitions still marked running long after they should have
+	// finished, such as ones cut off by a restart
+	exhibitionStaleAfter = 3 * time.Hour
+	maxFeaturedDuration  = 7 * 24 * time.Hour
+	maxFeaturedNote      = 200
+)
+
+var (
+	ErrInvalidLiveDebateFilter = errors.New("type must be 1v1, team or exhibition, and sort spectators, rating or newest")
+	ErrInvalidFeaturedDebate   = fmt.Errorf("a featured debate needs a debate ID, a note of up to %d characters and a duration of up to %v",
+		maxFeaturedNote, maxFeaturedDuration)
+	ErrFeaturedDebateNotFound = errors.New("featured debate not found")
+)
+
+// LiveDebate is an ongoing debate in the live directory
+type LiveDebate struct {
+	DebateID     string                  `json:"debateId"` // Spectator hub debate ID, for /ws/debate/:debateID
+	Type         string                  `json:"type"`
+	Topic        string                  `json:"topic,omitempty"`
+	Phase        string                  `json:"phase,omitempty"` // Empty until the first phase starts
+	Participants []LiveDebateParticipant `json:"participants"`
+	Rating       float64                 `json:"rating"`     // Average rating of the human debaters, 0 without any
+	Spectators   int                     `json:"spectators"` // Spectators on the instance serving the request
+	StartedAt    time.Time               `json:"startedAt"`
+	Featured     *models.FeaturedDebate  `json:"featured,omitempty"`
+}
+
+// LiveDebateParticipant is a debater in a live debate
+type LiveDebateParticipant struct {
+	UserID   string  `json:"userId,omitempty"`
+	Name     string  `json:"name"`
+	Side     string  `json:"side,omitempty"` // "for" or "against"
+	TeamID   string  `json:"teamId,omitempty"`
+	TeamName string  `json:"teamName,omitempty"`
+	Rating   float64 `json:"rating,omitempty"`
+	IsBot    bool    `json:"isBot,omitempty"`
+}
+
+// LiveDebateFilter narrows down the live directory. Zero values don't filter.
+type LiveDebateFilter struct {
+	Type         string
+	Query        string // Matches the topic, debater names and team names
+	Phase        string
+	MinRating    float64
+	MaxRating    float64
+	FeaturedOnly bool
+	Sort         string
+	Limit        int
+}
+
+// Validate checks the filter's type and sort order and fills in the default sort and limit
+func (f *LiveDebateFilter) Validate() error {
+	switch f.Type {
+	case "", LiveDebateOneOnOne, LiveDebateTeam, LiveDebateExhibition:
+	default:
+		return ErrInvalidLiveDebateFilter
+	}
+	switch f.Sort {
+	case "":
+		f.Sort = LiveDebatesBySpectators
+	case LiveDebatesBySpectators, LiveDebatesByRating, LiveDebatesByNewest:
+	default:
+		return ErrInvalidLiveDebateFilter
+	}
+	if f.Limit <= 0 {
+		f.Limit = defaultLiveDebateLimit
+	}
+	if f.Limit > maxLiveDebateLimit {
+		f.Limit = maxLiveDebateLimit
+	}
+	return nil
+}
+
+// AverageRating returns the average rating of a debate's human debaters, to one decimal place
+func AverageRating(participants []LiveDebateParticipant) float64 {
+	var total float64
+	var rated int
+	for _, participant := range participants {
+		if participant.IsBot || participant.Rating <= 0 {
+			continue
+		}
+		total += participant.Rating
+		rated++
+	}
+	if rated == 0 {
+		return 0
+	}
+	return math.Round(total/float64(rated)*10) / 10
+}
+
+// FilterLiveDebates applies a validated filter to the live directory. Featured debates come first,
+// highest priority first, and the rest follow in the filter's sort order.
+func FilterLiveDebates(debates []LiveDebate, filter LiveDebateFilter) []LiveDebate {
+	query := strings.ToLower(strings.TrimSpace(filter.Query))
+	filtered := make([]LiveDebate, 0, len(debates))
+	for _, debate := range debates {
+		if filter.Type != "" && debate.Type != filter.Type {
+			continue
+		}
+		if filter.Phase != "" && !strings.EqualFold(debate.Phase, filter.Phase) {
+			continue
+		}
+		if filter.MinRating > 0 && debate.Rating < filter.MinRating {
+			continue
+		}
+		if filter.MaxRating > 0 && debate.Rating > filter.MaxRating {
+			continue
+		}
+		if filter.FeaturedOnly && debate.Featured == nil {
+			continue
+		}
+		if query != "" && !liveDebateMatches(debate, query) {
+			continue
+		}
+		filtered = append(filtered, debate)
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		a, b := filtered[i], filtered[j]
+		if (a.Featured != nil) != (b.Featured != nil) {
+			return a.Featured != nil
+		}
+		if a.Featured != nil && a.Featured.Priority != b.Featured.Priority {
+			return a.Featured.Priority > b.Featured.Priority
+		}
+		switch filter.Sort {
+		case LiveDebatesByRating:
+			if a.Rating != b.Rating {
+				return a.Rating > b.Rating
+			}
+		case LiveDebatesByNewest:
+			if !a.StartedAt.Equal(b.StartedAt) {
+				return a.StartedAt.After(b.StartedAt)
+			}
+		}
+		if a.Spectators != b.Spectators {
+			return a.Spectators > b.Spectators
+		}
+		return a.DebateID < b.DebateID
+	})
+
+	if filter.Limit > 0 && len(filtered) > filter.Limit {
+		filtered = filtered[:filter.Limit]
+	}
+	return filtered
+}
+
+func liveDebateMatches(debate LiveDebate, query string) bool {
+	if strings.Contains(strings.ToLower(debate.Topic), query) {
+		return true
+	}
+	for _, participant := range debate.Participants {
+		if strings.Contains(strings.ToLower(participant.Name), query) ||
+			strings.Contains(strings.ToLower(participant.TeamName), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// AttachFeaturedDebates marks the live debates admins featured
+func AttachFeaturedDebates(debates []LiveDebate, featured []models.FeaturedDebate) {
+	byDebate := make(map[string]*models.FeaturedDebate, len(featured))
+	for i := range featured {
+		byDebate[featured[i].DebateID] = &featured[i]
+	}
+	for i := range debates {
+		debates[i].Featured = byDebate[debates[i].DebateID]
+	}
+}
+
+// ListRunningExhibitions returns the bot exhibitions in progress for the live directory
+func ListRunningExhibitions() ([]LiveDebate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := db.GetCollection(exhibitionCollection).Find(ctx, bson.M{
+		"status":    "running",
+		"createdAt": bson.M{"$gt": time.Now().Add(-exhibitionStaleAfter)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var exhibitions []models.ExhibitionDebate
+	if err := cursor.All(ctx, &exhibitions); err != nil {
+		return nil, err
+	}
+
+	debates := make([]LiveDebate, 0, len(exhibitions))
+	for _, exhibition := range exhibitions {
+		debates = append(debates, LiveDebate{
+			DebateID: exhibition.ID.Hex(),
+			Type:     LiveDebateExhibition,
+			Topic:    exhibition.Topic,
+			Phase:    exhibition.CurrentPhase,
+			Participants: []LiveDebateParticipant{
+				{Name: exhibition.ForBot, Side: "for", IsBot: true},
+				{Name: exhibition.AgainstBot, Side: "against", IsBot: true},
+			},
+			StartedAt: exhibition.CreatedAt,
+		})
+	}
+	return debates, nil
+}
+
+// LoadTeamDebates returns the stored team debates with the given IDs
+func LoadTeamDebates(ids []primitive.ObjectID) (map[primitive.ObjectID]models.TeamDebate, error) {
+	debates := make(map[primitive.ObjectID]models.TeamDebate, len(ids))
+	if len(ids) == 0 {
+		return debates, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := db.GetCollection("team_debates").Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var found []models.TeamDebate
+	if err := cursor.All(ctx, &found); err != nil {
+		return nil, err
+	}
+	for _, debate := range found {
+		debates[debate.ID] = debate
+	}
+	return debates, nil
+}
+
+// PublicRoomIDs returns which of the given 1v1 rooms are stored as public and may be listed in the
+// live directory. Rooms that are private, invite-only or not stored at all stay out of it.
+func PublicRoomIDs(roomIDs []string) (map[string]bool, error) {
+	public := make(map[string]bool)
+	if len(roomIDs) == 0 {
+		return public, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := db.GetCollection("rooms").Find(ctx,
+		bson.M{"_id": bson.M{"$in": roomIDs}, "type": "public"},
+		options.Find().SetProjection(bson.M{"_id": 1}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var rooms []struct {
+		ID string `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &rooms); err != nil {
+		return nil, err
+	}
+	for _, room := range rooms {
+		public[room.ID] = true
+	}
+	return public, nil
+}
+
+// ListFeaturedDebates returns the featured debates, highest priority first. Expired ones are left
+// out unless includeExpired is set.
+func ListFeaturedDebates(includeExpired bool) ([]models.FeaturedDebate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if !includeExpired {
+		filter["$or"] = bson.A{
+			bson.M{"expiresAt": bson.M{"$exists": false}},
+			bson.M{"expiresAt": bson.M{"$gt": time.Now()}},
+		}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "priority", Value: -1}, {Key: "featuredAt", Value: -1}})
+	cursor, err := db.GetCollection("featured_debates").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	featured := []models.FeaturedDebate{}
+	if err := cursor.All(ctx, &featured); err != nil {
+		return nil, err
+	}
+	return featured, nil
+}
+
+// FeatureDebate features a debate in the live directory, or updates it if it is already featured.
+// A zero duration keeps it featured until it is removed.
+func FeatureDebate(debateID, note string, priority int, duration time.Duration, adminID primitive.ObjectID) (*models.FeaturedDebate, error) {
+	debateID = strings.TrimSpace(debateID)
+	note = strings.TrimSpace(note)
+	if debateID == "" || len(note) > maxFeaturedNote || duration < 0 || duration > maxFeaturedDuration {
+		return nil, ErrInvalidFeaturedDebate
+	}
+
+	now := time.Now()
+	set := bson.M{
+		"note":       note,
+		"priority":   priority,
+		"featuredBy": adminID,
+		"featuredAt": now,
+	}
+	update := bson.M{"$set": set}
+	if duration > 0 {
+		set["expiresAt"] = now.Add(duration)
+	} else {
+		update["$unset"] = bson.M{"expiresAt": ""}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var featured models.FeaturedDebate
+	err := db.GetCollection("featured_debates").FindOneAndUpdate(ctx,
+		bson.M{"debateId": debateID},
+		update,
+		options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After),
+	).Decode(&featured)
+	if err != nil {
+		return nil, err
+	}
+	return &featured, nil
+}
+
+// UnfeatureDebate removes a debate from the featured list
+func UnfeatureDebate(id primitive.ObjectID) (*models.FeaturedDebate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var featured models.FeaturedDebate
+	err := db.GetCollection("featured_debates").FindOneAndDelete(ctx, bson.M{"_id": id}).Decode(&featured)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrFeaturedDebateNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &featured, nil
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"arguehub/models"
+)
+
+func TestFilterLiveDebates(t *testing.T) {
+	now := time.Now()
+	debates := []LiveDebate{
+		{DebateID: "room1", Type: LiveDebateOneOnOne, Topic: "Ban homework", Phase: "openingFor", Rating: 1450, Spectators: 3, StartedAt: now.Add(-10 * time.Minute),
+			Participants: []LiveDebateParticipant{{Name: "alice"}, {Name: "bob"}}},
+		{DebateID: "team1", Type: LiveDebateTeam, Topic: "Universal basic income", Phase: "closingFor", Rating: 1700, Spectators: 12, StartedAt: now.Add(-30 * time.Minute),
+			Participants: []LiveDebateParticipant{{Name: "carol", TeamName: "Owls"}}},
+		{DebateID: "exh1", Type: LiveDebateExhibition, Topic: "Space colonies", Spectators: 5, StartedAt: now,
+			Participants: []LiveDebateParticipant{{Name: "Socrates", IsBot: true}}},
+	}
+	AttachFeaturedDebates(debates, []models.FeaturedDebate{{DebateID: "exh1", Priority: 1}})
+
+	ids := func(debates []LiveDebate) []string {
+		out := make([]string, 0, len(debates))
+		for _, debate := range debates {
+			out = append(out, debate.DebateID)
+		}
+		return out
+	}
+	cases := []struct {
+		name   string
+		filter LiveDebateFilter
+		want   []string
+	}{
+		{"featured first, then most watched", LiveDebateFilter{}, []string{"exh1", "team1", "room1"}},
+		{"newest", LiveDebateFilter{Sort: LiveDebatesByNewest}, []string{"exh1", "room1", "team1"}},
+		{"type", LiveDebateFilter{Type: LiveDebateTeam}, []string{"team1"}},
+		{"team name", LiveDebateFilter{Query: "owls"}, []string{"team1"}},
+		{"topic", LiveDebateFilter{Query: "HOMEWORK"}, []string{"room1"}},
+		{"rating range", LiveDebateFilter{MinRating: 1400, MaxRating: 1500}, []string{"room1"}},
+		{"phase", LiveDebateFilter{Phase: "closingfor"}, []string{"team1"}},
+		{"featured only", LiveDebateFilter{FeaturedOnly: true}, []string{"exh1"}},
+		{"limit", LiveDebateFilter{Limit: 1}, []string{"exh1"}},
+	}
+	for _, tc := range cases {
+		if err := tc.filter.Validate(); err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		got := ids(FilterLiveDebates(debates, tc.filter))
+		if len(got) != len(tc.want) {
+			t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: got %v, want %v", tc.name, got, tc.want)
+				break
+			}
+		}
+	}
+
+	if err := (&LiveDebateFilter{Sort: "loudest"}).Validate(); err != ErrInvalidLiveDebateFilter {
+		t.Errorf("unknown sort: err = %v, want ErrInvalidLiveDebateFilter", err)
+	}
+}
+
+func TestAverageRatingSkipsBotsAndUnrated(t *testing.T) {
+	participants := []LiveDebateParticipant{{Rating: 1400}, {Rating: 1501}, {Rating: 2000, IsBot: true}, {}}
+	if got := AverageRating(participants); got != 1450.5 {
+		t.Errorf("AverageRating = %v, want 1450.5", got)
+	}
+}
@@ -0,0 +1,299 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// timelineReactions are the reactions the spectator reaction bar offers. Others aren't counted.
+var timelineReactions = map[string]bool{
+	"👍": true, "❤️": true, "😂": true, "🔥": true, "🎉": true, "💯": true, "👏": true, "🤔": true,
+}
+
+// ErrNoReactionTimeline is returned for a debate nobody reacted to and no phases were recorded for
+var ErrNoReactionTimeline = errors.New("no reaction timeline for this debate")
+
+// ReactionHeatmap lines a debate's reactions up with its phases and speeches. Offsets are seconds
+// since the debate started.
+type ReactionHeatmap struct {
+	DebateID  string           `json:"debateId"`
+	StartedAt int64            `json:"startedAt"`
+	EndedAt   int64            `json:"endedAt"`
+	Finished  bool             `json:"finished"`
+	Total     int64            `json:"total"`
+	Buckets   []HeatmapBucket  `json:"buckets"` // Seconds with at least one reaction, in order
+	Phases    []HeatmapPhase   `json:"phases"`
+	Segments  []HeatmapSegment `json:"segments"`
+}
+
+// HeatmapBucket is one second of reactions
+type HeatmapBucket struct {
+	Offset int64            `json:"offset"`
+	At     int64            `json:"at"`
+	Phase  string           `json:"phase,omitempty"`
+	Counts map[string]int64 `json:"counts"`
+	Total  int64            `json:"total"`
+}
+
+// HeatmapPhase is a phase of the debate and the reactions during it
+type HeatmapPhase struct {
+	Phase      string `json:"phase"`
+	Side       string `json:"side,omitempty"`
+	TeamID     string `json:"teamId,omitempty"`
+	Start      int64  `json:"start"`
+	End        int64  `json:"end"`
+	Total      int64  `json:"total"`
+	PeakOffset int64  `json:"peakOffset"` // Busiest second of the phase, -1 without reactions
+}
+
+// HeatmapSegment is a speech and the reactions while it was given
+type HeatmapSegment struct {
+	UserID   string           `json:"userId,omitempty"`
+	Username string           `json:"username,omitempty"`
+	TeamID   string           `json:"teamId,omitempty"`
+	Side     string           `json:"side,omitempty"`
+	Phase    string           `json:"phase,omitempty"`
+	Text     string           `json:"text"`
+	IsBot    bool             `json:"isBot,omitempty"`
+	Start    int64            `json:"start"`
+	End      int64            `json:"end"`
+	Counts   map[string]int64 `json:"counts"`
+	Total    int64            `json:"total"`
+	Rate     float64          `json:"rate"` // Reactions per second
+}
+
+// IsTimelineReaction reports whether a reaction is counted on the timeline
+func IsTimelineReaction(reaction string) bool {
+	return timelineReactions[reaction]
+}
+
+// RecordReactionBuckets adds per-second reaction counts to a debate's timeline. Each reaction is
+// counted by the instance its spectator sent it to, not from the stream, so the totals add up
+// across instances without counting anything twice.
+func RecordReactionBuckets(debateID string, buckets map[int64]map[string]int64) error {
+	if len(buckets) == 0 {
+		return nil
+	}
+	inc := bson.M{}
+	for second, counts := range buckets {
+		for reaction, count := range counts {
+			inc[fmt.Sprintf("buckets.%d.%s", second, reaction)] = count
+		}
+	}
+	return updateReactionTimeline(debateID, bson.M{"$inc": inc})
+}
+
+// RecordTimelinePhase marks the start of a phase on a debate's timeline
+func RecordTimelinePhase(debateID string, phase models.TimelinePhase) error {
+	return updateReactionTimeline(debateID, bson.M{"$push": bson.M{"phases": phase}})
+}
+
+// RecordTimelineSegment adds a finished speech to a debate's timeline
+func RecordTimelineSegment(debateID string, segment models.TimelineSegment) error {
+	return updateReactionTimeline(debateID, bson.M{"$push": bson.M{"segments": segment}})
+}
+
+// FinishReactionTimeline marks the end of a debate on its timeline
+func FinishReactionTimeline(debateID string, finishedAt time.Time) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.GetCollection("reaction_timelines").UpdateOne(ctx,
+		bson.M{"debateId": debateID, "finishedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"finishedAt": finishedAt}},
+	)
+	return err
+}
+
+// GetReactionHeatmap returns a debate's crowd heatmap
+func GetReactionHeatmap(debateID string) (*ReactionHeatmap, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var timeline models.ReactionTimeline
+	err := db.GetCollection("reaction_timelines").FindOne(ctx, bson.M{"debateId": debateID}).Decode(&timeline)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrNoReactionTimeline
+	}
+	if err != nil {
+		return nil, err
+	}
+	heatmap := BuildReactionHeatmap(timeline)
+	return &heatmap, nil
+}
+
+// BuildReactionHeatmap lines a timeline's reaction buckets up with its phases and speeches. A
+// speech runs from the end of the previous speech in its phase, or the start of the phase, to
+// when its transcript arrived.
+func BuildReactionHeatmap(timeline models.ReactionTimeline) ReactionHeatmap {
+	heatmap := ReactionHeatmap{
+		DebateID: timeline.DebateID,
+		Finished: timeline.FinishedAt != nil,
+		Buckets:  []HeatmapBucket{},
+		Phases:   []HeatmapPhase{},
+		Segments: []HeatmapSegment{},
+	}
+
+	seconds := make([]int64, 0, len(timeline.Buckets))
+	for key := range timeline.Buckets {
+		if second, err := strconv.ParseInt(key, 10, 64); err == nil {
+			seconds = append(seconds, second)
+		}
+	}
+	sort.Slice(seconds, func(i, j int) bool { return seconds[i] < seconds[j] })
+
+	phases := append([]models.TimelinePhase(nil), timeline.Phases...)
+	sort.SliceStable(phases, func(i, j int) bool { return phases[i].StartedAt.Before(phases[j].StartedAt) })
+	segments := append([]models.TimelineSegment(nil), timeline.Segments...)
+	sort.SliceStable(segments, func(i, j int) bool { return segments[i].EndedAt.Before(segments[j].EndedAt) })
+
+	// The debate starts with its first phase, or its first reaction if no phases were recorded
+	start := timeline.CreatedAt.Unix()
+	switch {
+	case len(phases) > 0:
+		start = phases[0].StartedAt.Unix()
+	case len(seconds) > 0:
+		start = seconds[0]
+	}
+	end := start
+	if timeline.FinishedAt != nil {
+		end = timeline.FinishedAt.Unix()
+	} else {
+		if len(seconds) > 0 && seconds[len(seconds)-1] > end {
+			end = seconds[len(seconds)-1]
+		}
+		if len(segments) > 0 && segments[len(segments)-1].EndedAt.Unix() > end {
+			end = segments[len(segments)-1].EndedAt.Unix()
+		}
+		if len(phases) > 0 && phases[len(phases)-1].StartedAt.Unix() > end {
+			end = phases[len(phases)-1].StartedAt.Unix()
+		}
+	}
+	heatmap.StartedAt = start
+	heatmap.EndedAt = end
+
+	for i, phase := range phases {
+		phaseEnd := end
+		if i+1 < len(phases) {
+			phaseEnd = phases[i+1].StartedAt.Unix()
+		}
+		if phaseEnd < phase.StartedAt.Unix() {
+			phaseEnd = phase.StartedAt.Unix()
+		}
+		heatmap.Phases = append(heatmap.Phases, HeatmapPhase{
+			Phase:      phase.Phase,
+			Side:       phase.Side,
+			TeamID:     phase.TeamID,
+			Start:      phase.StartedAt.Unix() - start,
+			End:        phaseEnd - start,
+			PeakOffset: -1,
+		})
+	}
+
+	// phaseAt returns the index of the phase in progress at an offset, or -1 before the first
+	phaseAt := func(offset int64) int {
+		return sort.Search(len(heatmap.Phases), func(i int) bool { return heatmap.Phases[i].Start > offset }) - 1
+	}
+
+	peaks := make([]int64, len(heatmap.Phases))
+	for _, second := range seconds {
+		bucket := HeatmapBucket{
+			Offset: second - start,
+			At:     second,
+			Counts: timeline.Buckets[strconv.FormatInt(second, 10)],
+		}
+		for _, count := range bucket.Counts {
+			bucket.Total += count
+		}
+		if p := phaseAt(bucket.Offset); p >= 0 {
+			bucket.Phase = heatmap.Phases[p].Phase
+			heatmap.Phases[p].Total += bucket.Total
+			if bucket.Total > peaks[p] {
+				peaks[p] = bucket.Total
+				heatmap.Phases[p].PeakOffset = bucket.Offset
+			}
+		}
+		heatmap.Total += bucket.Total
+		heatmap.Buckets = append(heatmap.Buckets, bucket)
+	}
+
+	var previousEnd int64 = -1
+	previousPhase := -1
+	for _, segment := range segments {
+		segmentEnd := segment.EndedAt.Unix() - start
+		p := phaseAt(segmentEnd)
+		if segment.Phase != "" {
+			// The transcript can arrive just after the next phase began
+			for i := p; i >= 0; i-- {
+				if heatmap.Phases[i].Phase == segment.Phase {
+					p = i
+					break
+				}
+			}
+		}
+
+		var segmentStart int64
+		if p >= 0 {
+			segmentStart = heatmap.Phases[p].Start
+		}
+		if p == previousPhase && previousEnd+1 > segmentStart {
+			segmentStart = previousEnd + 1
+		}
+		if segmentStart > segmentEnd {
+			segmentStart = segmentEnd
+		}
+
+		entry := HeatmapSegment{
+			UserID:   segment.UserID,
+			Username: segment.Username,
+			TeamID:   segment.TeamID,
+			Side:     segment.Side,
+			Phase:    segment.Phase,
+			Text:     segment.Text,
+			IsBot:    segment.IsBot,
+			Start:    segmentStart,
+			End:      segmentEnd,
+			Counts:   map[string]int64{},
+		}
+		for _, bucket := range heatmap.Buckets {
+			if bucket.Offset < segmentStart || bucket.Offset > segmentEnd {
+				continue
+			}
+			for reaction, count := range bucket.Counts {
+				entry.Counts[reaction] += count
+			}
+			entry.Total += bucket.Total
+		}
+		entry.Rate = float64(entry.Total) / float64(segmentEnd-segmentStart+1)
+		heatmap.Segments = append(heatmap.Segments, entry)
+
+		previousEnd = segmentEnd
+		previousPhase = p
+	}
+
+	return heatmap
+}
+
+func updateReactionTimeline(debateID string, update bson.M) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update["$setOnInsert"] = bson.M{"debateId": debateID, "createdAt": time.Now()}
+	_, err := db.GetCollection("reaction_timelines").UpdateOne(ctx,
+		bson.M{"debateId": debateID},
+		update,
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
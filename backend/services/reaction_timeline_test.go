@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"arguehub/models"
+)
+
+func TestBuildReactionHeatmap(t *testing.T) {
+	start := time.Unix(1000, 0)
+	finished := start.Add(60 * time.Second)
+	timeline := models.ReactionTimeline{
+		DebateID: "room",
+		Buckets: map[string]map[string]int64{
+			"1005": {"👏": 2},
+			"1012": {"🔥": 3, "👏": 1},
+			"1031": {"🤔": 1},
+			"1045": {"😂": 5},
+		},
+		Phases: []models.TimelinePhase{
+			{Phase: "Rebuttal", StartedAt: start.Add(30 * time.Second)},
+			{Phase: "Opening", StartedAt: start},
+		},
+		Segments: []models.TimelineSegment{
+			{Username: "b", Phase: "Opening", Text: "second", EndedAt: start.Add(20 * time.Second)},
+			{Username: "a", Phase: "Opening", Text: "first", EndedAt: start.Add(10 * time.Second)},
+			// Arrived just after the rebuttal began, but belongs to the opening
+			{Username: "c", Phase: "Opening", Text: "late", EndedAt: start.Add(32 * time.Second)},
+		},
+		FinishedAt: &finished,
+	}
+
+	heatmap := BuildReactionHeatmap(timeline)
+	if heatmap.StartedAt != 1000 || heatmap.EndedAt != 1060 || !heatmap.Finished {
+		t.Fatalf("span = %d-%d finished %v, want 1000-1060 finished", heatmap.StartedAt, heatmap.EndedAt, heatmap.Finished)
+	}
+	if heatmap.Total != 12 || len(heatmap.Buckets) != 4 {
+		t.Fatalf("total %d over %d buckets, want 12 over 4", heatmap.Total, len(heatmap.Buckets))
+	}
+	if bucket := heatmap.Buckets[1]; bucket.Offset != 12 || bucket.Phase != "Opening" || bucket.Total != 4 {
+		t.Errorf("second bucket = %+v, want offset 12 in Opening with 4 reactions", bucket)
+	}
+
+	if len(heatmap.Phases) != 2 {
+		t.Fatalf("got %d phases, want 2", len(heatmap.Phases))
+	}
+	opening, rebuttal := heatmap.Phases[0], heatmap.Phases[1]
+	if opening.Start != 0 || opening.End != 30 || opening.Total != 6 || opening.PeakOffset != 12 {
+		t.Errorf("opening = %+v, want 0-30 with 6 reactions peaking at 12", opening)
+	}
+	if rebuttal.Start != 30 || rebuttal.End != 60 || rebuttal.Total != 6 || rebuttal.PeakOffset != 45 {
+		t.Errorf("rebuttal = %+v, want 30-60 with 6 reactions peaking at 45", rebuttal)
+	}
+
+	want := []struct {
+		text       string
+		start, end int64
+		total      int64
+	}{
+		{"first", 0, 10, 2},
+		{"second", 11, 20, 4},
+		{"late", 21, 32, 1},
+	}
+	if len(heatmap.Segments) != len(want) {
+		t.Fatalf("got %d segments, want %d", len(heatmap.Segments), len(want))
+	}
+	for i, w := range want {
+		segment := heatmap.Segments[i]
+		if segment.Text != w.text || segment.Start != w.start || segment.End != w.end || segment.Total != w.total {
+			t.Errorf("segment %d = %q %d-%d with %d reactions, want %q %d-%d with %d",
+				i, segment.Text, segment.Start, segment.End, segment.Total, w.text, w.start, w.end, w.total)
+		}
+	}
+	if rate := heatmap.Segments[1].Rate; rate != 0.4 {
+		t.Errorf("second segment rate = %v, want 0.4", rate)
+	}
+}
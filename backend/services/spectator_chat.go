@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxSpectatorChatLength  = 500
+	spectatorChatHistory    = 50
+	minChatSanctionDuration = 10 * time.Second
+	maxChatSanctionDuration = 24 * time.Hour
+)
+
+var (
+	ErrInvalidSpectatorChat          = fmt.Errorf("chat messages must be between 1 and %d characters", maxSpectatorChatLength)
+	ErrSpectatorChatMessageNotFound  = errors.New("chat message not found")
+	ErrSpectatorChatSanctionNotFound = errors.New("no active mute or ban with this ID")
+	ErrInvalidChatSanction           = fmt.Errorf("a mute or ban needs a kind, a spectator or message, and a duration of 0 (rest of the debate) or %v to %v",
+		minChatSanctionDuration, maxChatSanctionDuration)
+)
+
+// SpectatorChatSender is who sent a spectator chat message
+type SpectatorChatSender struct {
+	SpectatorHash string
+	IPHash        string
+	UserID        string // Set for logged-in spectators
+	Username      string
+}
+
+// ChatModerator is the host or admin deleting a message or issuing a mute or ban
+type ChatModerator struct {
+	ID   primitive.ObjectID
+	Role string // "host", or the admin's role
+}
+
+// SpectatorChatSanctionRequest is a host's or admin's mute or ban. The spectator is picked by their
+// hash or by one of their messages.
+type SpectatorChatSanctionRequest struct {
+	Kind            string `json:"kind" binding:"required"`
+	SpectatorHash   string `json:"spectatorHash"`
+	MessageID       string `json:"messageId"`
+	Reason          string `json:"reason"`
+	DurationSeconds int    `json:"durationSeconds"` // 0 for the rest of the debate
+}
+
+// PostSpectatorChatMessage saves a spectator's chat message with the debate's blocked words masked,
+// and sends it to everyone watching
+func PostSpectatorChatMessage(debateID string, sender SpectatorChatSender, content string, blockedWords []string) (*models.SpectatorChatMessage, error) {
+	content = strings.TrimSpace(content)
+	if content == "" || utf8.RuneCountInString(content) > maxSpectatorChatLength {
+		return nil, ErrInvalidSpectatorChat
+	}
+	filtered, masked := FilterChatContent(content, blockedWords)
+
+	message := &models.SpectatorChatMessage{
+		DebateID:      debateID,
+		SpectatorHash: sender.SpectatorHash,
+		IPHash:        sender.IPHash,
+		UserID:        sender.UserID,
+		Username:      sender.Username,
+		Content:       filtered,
+		Filtered:      masked,
+		CreatedAt:     time.Now(),
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.GetCollection("spectator_chat_messages").InsertOne(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	message.ID = result.InsertedID.(primitive.ObjectID)
+
+	publishSpectatorEvent(debateID, "spectator_chat", message)
+	return message, nil
+}
+
+// ListSpectatorChatMessages returns a debate's latest chat messages, oldest first. Deleted messages
+// are only included for moderators.
+func ListSpectatorChatMessages(debateID string, includeDeleted bool) ([]models.SpectatorChatMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"debateId": debateID}
+	if !includeDeleted {
+		filter["deleted"] = bson.M{"$ne": true}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(spectatorChatHistory)
+	cursor, err := db.GetCollection("spectator_chat_messages").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []models.SpectatorChatMessage{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// DeleteSpectatorChatMessage removes a chat message and tells spectators to hide it
+func DeleteSpectatorChatMessage(debateID string, messageID primitive.ObjectID, moderator ChatModerator) (*models.SpectatorChatMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var message models.SpectatorChatMessage
+	err := db.GetCollection("spectator_chat_messages").FindOneAndUpdate(ctx,
+		bson.M{"_id": messageID, "debateId": debateID},
+		bson.M{"$set": bson.M{"deleted": true, "deletedBy": moderator.ID, "deletedAt": now}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrSpectatorChatMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	publishSpectatorEvent(debateID, "spectator_chat_deleted", map[string]interface{}{
+		"messageId": message.ID.Hex(),
+	})
+	return &message, nil
+}
+
+// SanctionSpectator mutes or bans a spectator from a debate. Banned spectators are disconnected by
+// the spectator hub when it sees the sanction.
+func SanctionSpectator(debateID string, req SpectatorChatSanctionRequest, moderator ChatModerator) (*models.SpectatorChatSanction, error) {
+	duration := time.Duration(req.DurationSeconds) * time.Second
+	if req.Kind != models.SpectatorChatMute && req.Kind != models.SpectatorChatBan {
+		return nil, ErrInvalidChatSanction
+	}
+	if duration != 0 && (duration < minChatSanctionDuration || duration > maxChatSanctionDuration) {
+		return nil, ErrInvalidChatSanction
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Take the spectator's details from the message, or their latest message
+	filter := bson.M{"debateId": debateID, "spectatorHash": req.SpectatorHash}
+	if req.MessageID != "" {
+		messageID, err := primitive.ObjectIDFromHex(req.MessageID)
+		if err != nil {
+			return nil, ErrSpectatorChatMessageNotFound
+		}
+		filter = bson.M{"_id": messageID, "debateId": debateID}
+	} else if req.SpectatorHash == "" {
+		return nil, ErrInvalidChatSanction
+	}
+	var message models.SpectatorChatMessage
+	err := db.GetCollection("spectator_chat_messages").FindOne(ctx, filter,
+		options.FindOne().SetSort(bson.D{{Key: "createdAt", Value: -1}}),
+	).Decode(&message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if req.MessageID != "" {
+			return nil, ErrSpectatorChatMessageNotFound
+		}
+		message.SpectatorHash = req.SpectatorHash
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sanction := &models.SpectatorChatSanction{
+		DebateID:      debateID,
+		Kind:          req.Kind,
+		SpectatorHash: message.SpectatorHash,
+		UserID:        message.UserID,
+		Username:      message.Username,
+		Reason:        strings.TrimSpace(req.Reason),
+		IssuedBy:      moderator.ID,
+		IssuedByRole:  moderator.Role,
+		CreatedAt:     now,
+	}
+	if message.UserID == "" {
+		sanction.IPHash = message.IPHash
+	}
+	if duration > 0 {
+		expiresAt := now.Add(duration)
+		sanction.ExpiresAt = &expiresAt
+	}
+
+	result, err := db.GetCollection("spectator_chat_sanctions").InsertOne(ctx, sanction)
+	if err != nil {
+		return nil, err
+	}
+	sanction.ID = result.InsertedID.(primitive.ObjectID)
+
+	publishSpectatorEvent(debateID, "spectator_chat_sanction", sanction)
+	return sanction, nil
+}
+
+// LiftSpectatorSanction ends a mute or ban early
+func LiftSpectatorSanction(debateID string, sanctionID primitive.ObjectID, moderator ChatModerator) (*models.SpectatorChatSanction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var sanction models.SpectatorChatSanction
+	err := db.GetCollection("spectator_chat_sanctions").FindOneAndUpdate(ctx,
+		bson.M{"_id": sanctionID, "debateId": debateID, "liftedAt": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"liftedBy": moderator.ID, "liftedAt": time.Now()}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&sanction)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrSpectatorChatSanctionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	publishSpectatorEvent(debateID, "spectator_chat_sanction_lifted", sanction)
+	return &sanction, nil
+}
+
+// ListSpectatorSanctions returns a debate's mutes and bans, newest first
+func ListSpectatorSanctions(debateID string, activeOnly bool) ([]models.SpectatorChatSanction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"debateId": debateID}
+	if activeOnly {
+		filter = activeSanctionFilter(time.Now())
+		filter["debateId"] = debateID
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}})
+	cursor, err := db.GetCollection("spectator_chat_sanctions").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	sanctions := []models.SpectatorChatSanction{}
+	if err := cursor.All(ctx, &sanctions); err != nil {
+		return nil, err
+	}
+	return sanctions, nil
+}
+
+// ActiveSpectatorSanction returns the mute or ban keeping a spectator out of a debate's chat, bans
+// first, or nil. Sanctions on anonymous spectators also match their IP address, so a new spectatorId
+// doesn't get past them; that only applies to anonymous spectators, who can log in instead.
+func ActiveSpectatorSanction(debateID, spectatorHash, ipHash string, anonymous bool) (*models.SpectatorChatSanction, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	who := bson.A{bson.M{"spectatorHash": spectatorHash}}
+	if anonymous && ipHash != "" {
+		who = append(who, bson.M{"ipHash": ipHash})
+	}
+	filter := bson.M{
+		"debateId": debateID,
+		"$and":     bson.A{activeSanctionFilter(time.Now()), bson.M{"$or": who}},
+	}
+
+	var sanction models.SpectatorChatSanction
+	err := db.GetCollection("spectator_chat_sanctions").FindOne(ctx, filter,
+		options.FindOne().SetSort(bson.D{{Key: "kind", Value: 1}}), // "ban" before "mute"
+	).Decode(&sanction)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &sanction, nil
+}
+
+// FilterChatContent masks the blocked words in a chat message, matching whole words regardless of
+// case. It reports whether anything was masked.
+func FilterChatContent(content string, blockedWords []string) (string, bool) {
+	if len(blockedWords) == 0 {
+		return content, false
+	}
+	blocked := make(map[string]bool, len(blockedWords))
+	for _, word := range blockedWords {
+		blocked[strings.ToLower(word)] = true
+	}
+
+	runes := []rune(content)
+	masked := false
+	for start := 0; start < len(runes); {
+		if !isChatWordRune(runes[start]) {
+			start++
+			continue
+		}
+		end := start
+		for end < len(runes) && isChatWordRune(runes[end]) {
+			end++
+		}
+		if blocked[strings.ToLower(string(runes[start:end]))] {
+			for i := start; i < end; i++ {
+				runes[i] = '*'
+			}
+			masked = true
+		}
+		start = end
+	}
+	if !masked {
+		return content, false
+	}
+	return string(runes), true
+}
+
+func isChatWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsNumber(r) || r == '\''
+}
+
+// activeSanctionFilter matches mutes and bans that haven't been lifted or run out
+func activeSanctionFilter(now time.Time) bson.M {
+	return bson.M{
+		"liftedAt": bson.M{"$exists": false},
+		"$or": bson.A{
+			bson.M{"expiresAt": bson.M{"$exists": false}},
+			bson.M{"expiresAt": bson.M{"$gt": now}},
+		},
+	}
+}
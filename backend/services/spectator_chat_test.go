@@ -0,0 +1,36 @@
+package services
+
+import "testing"
+
+func TestFilterChatContent(t *testing.T) {
+	blocked := []string{"darn", "heck"}
+
+	cases := []struct {
+		content, want string
+		masked        bool
+	}{
+		{"What the HECK was that?", "What the **** was that?", true},
+		{"darn, darn it", "****, **** it", true},
+		{"Darnell made a great point", "Darnell made a great point", false}, // whole words only
+		{"no filter needed", "no filter needed", false},
+	}
+	for _, tc := range cases {
+		got, masked := FilterChatContent(tc.content, blocked)
+		if got != tc.want || masked != tc.masked {
+			t.Errorf("FilterChatContent(%q) = %q, %v; want %q, %v", tc.content, got, masked, tc.want, tc.masked)
+		}
+	}
+}
+
+func TestNormalizeBlockedWords(t *testing.T) {
+	words, err := normalizeBlockedWords([]string{" Darn ", "darn", "", "HECK"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(words) != 2 || words[0] != "darn" || words[1] != "heck" {
+		t.Errorf("words = %q, want [darn heck]", words)
+	}
+	if _, err := normalizeBlockedWords([]string{"two words"}); err != ErrInvalidChatBlockedWords {
+		t.Errorf("err = %v, want ErrInvalidChatBlockedWords", err)
+	}
+}
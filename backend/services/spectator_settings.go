@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultMaxAnonymousPerIP      = 5
+	defaultMaxAnonymousVotesPerIP = 3
+	maxAnonymousLimit             = 50
+	maxChatSlowModeSeconds        = 300
+	maxChatBlockedWords           = 100
+	maxChatBlockedWordLength      = 40
+)
+
+var (
+	// ErrInvalidSpectatorSettings is returned for an anonymous viewer limit out of range
+	ErrInvalidSpectatorSettings = fmt.Errorf("anonymous viewer limits must be between 1 and %d", maxAnonymousLimit)
+	ErrInvalidChatSlowMode      = fmt.Errorf("chat slow mode must be between 0 and %d seconds", maxChatSlowModeSeconds)
+	ErrInvalidChatBlockedWords  = fmt.Errorf("up to %d blocked words of up to %d characters, without spaces", maxChatBlockedWords, maxChatBlockedWordLength)
+)
+
+// SpectatorSettingsUpdate holds the spectator settings a host is changing
+type SpectatorSettingsUpdate struct {
+	RequireLoginToVote     *bool     `json:"requireLoginToVote"`
+	RequireLoginToAsk      *bool     `json:"requireLoginToAsk"`
+	MaxAnonymousPerIP      *int      `json:"maxAnonymousPerIp"`
+	MaxAnonymousVotesPerIP *int      `json:"maxAnonymousVotesPerIp"`
+	ChatDisabled           *bool     `json:"chatDisabled"`
+	RequireLoginToChat     *bool     `json:"requireLoginToChat"`
+	ChatSlowModeSeconds    *int      `json:"chatSlowModeSeconds"`
+	ChatBlockedWords       *[]string `json:"chatBlockedWords"`
+}
+
+// DefaultSpectatorSettings returns the settings of a debate whose hosts haven't changed them
+func DefaultSpectatorSettings(debateID string) models.SpectatorSettings {
+	return models.SpectatorSettings{
+		DebateID:               debateID,
+		MaxAnonymousPerIP:      defaultMaxAnonymousPerIP,
+		MaxAnonymousVotesPerIP: defaultMaxAnonymousVotesPerIP,
+	}
+}
+
+// GetSpectatorSettings returns a debate's spectator settings, falling back to the defaults
+func GetSpectatorSettings(debateID string) (models.SpectatorSettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var settings models.SpectatorSettings
+	err := db.GetCollection("spectator_settings").FindOne(ctx, bson.M{"debateId": debateID}).Decode(&settings)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return DefaultSpectatorSettings(debateID), nil
+	}
+	if err != nil {
+		return DefaultSpectatorSettings(debateID), err
+	}
+	return settings, nil
+}
+
+// UpdateSpectatorSettings changes a debate's spectator settings and tells connected spectators
+func UpdateSpectatorSettings(debateID string, update SpectatorSettingsUpdate, userID primitive.ObjectID) (models.SpectatorSettings, error) {
+	settings, err := GetSpectatorSettings(debateID)
+	if err != nil {
+		return settings, err
+	}
+
+	if update.RequireLoginToVote != nil {
+		settings.RequireLoginToVote = *update.RequireLoginToVote
+	}
+	if update.RequireLoginToAsk != nil {
+		settings.RequireLoginToAsk = *update.RequireLoginToAsk
+	}
+	if update.MaxAnonymousPerIP != nil {
+		if *update.MaxAnonymousPerIP < 1 || *update.MaxAnonymousPerIP > maxAnonymousLimit {
+			return settings, ErrInvalidSpectatorSettings
+		}
+		settings.MaxAnonymousPerIP = *update.MaxAnonymousPerIP
+	}
+	if update.MaxAnonymousVotesPerIP != nil {
+		if *update.MaxAnonymousVotesPerIP < 1 || *update.MaxAnonymousVotesPerIP > maxAnonymousLimit {
+			return settings, ErrInvalidSpectatorSettings
+		}
+		settings.MaxAnonymousVotesPerIP = *update.MaxAnonymousVotesPerIP
+	}
+	if update.ChatDisabled != nil {
+		settings.ChatDisabled = *update.ChatDisabled
+	}
+	if update.RequireLoginToChat != nil {
+		settings.RequireLoginToChat = *update.RequireLoginToChat
+	}
+	if update.ChatSlowModeSeconds != nil {
+		if *update.ChatSlowModeSeconds < 0 || *update.ChatSlowModeSeconds > maxChatSlowModeSeconds {
+			return settings, ErrInvalidChatSlowMode
+		}
+		settings.ChatSlowModeSeconds = *update.ChatSlowModeSeconds
+	}
+	if update.ChatBlockedWords != nil {
+		words, err := normalizeBlockedWords(*update.ChatBlockedWords)
+		if err != nil {
+			return settings, err
+		}
+		settings.ChatBlockedWords = words
+	}
+	settings.UpdatedBy = userID
+	settings.UpdatedAt = time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = db.GetCollection("spectator_settings").UpdateOne(ctx,
+		bson.M{"debateId": debateID},
+		bson.M{"$set": settings},
+		options.Update().SetUpsert(true),
+	)
+	if err != nil {
+		return settings, err
+	}
+
+	publishSpectatorEvent(debateID, "spectator_settings", settings.ForSpectators())
+	return settings, nil
+}
+
+// normalizeBlockedWords lowercases and de-duplicates a chat word filter
+func normalizeBlockedWords(words []string) ([]string, error) {
+	normalized := make([]string, 0, len(words))
+	seen := make(map[string]bool, len(words))
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word == "" || seen[word] {
+			continue
+		}
+		if strings.ContainsFunc(word, unicode.IsSpace) || utf8.RuneCountInString(word) > maxChatBlockedWordLength {
+			return nil, ErrInvalidChatBlockedWords
+		}
+		seen[word] = true
+		normalized = append(normalized, word)
+	}
+	if len(normalized) > maxChatBlockedWords {
+		return nil, ErrInvalidChatBlockedWords
+	}
+	return normalized, nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// teamBotMaxWords keeps bot speeches in team debates short enough to read aloud in one turn
+const teamBotMaxWords = 120
+
+// CreateTeamBotDebate creates a debate between a human team and a team of bot personalities.
+// The bot team is not stored in the teams collection; its members only exist on the debate.
+func CreateTeamBotDebate(team models.Team, topic string, botNames []string, stance string) (*models.TeamDebate, error) {
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	if len(team.Members) == 0 {
+		return nil, errors.New("team has no members")
+	}
+	if len(botNames) == 0 {
+		return nil, errors.New("at least one bot is required")
+	}
+	if len(botNames) > len(team.Members) {
+		return nil, fmt.Errorf("the bot team cannot have more than %d members", len(team.Members))
+	}
+	if geminiClient == nil {
+		return nil, errors.New("bot debates are unavailable: AI service not configured")
+	}
+
+	now := time.Now()
+	seen := make(map[string]bool, len(botNames))
+	botMembers := make([]models.TeamMember, 0, len(botNames))
+	totalRating := 0.0
+	for _, name := range botNames {
+		bot := GetBotPersonality(name)
+		if bot.Name != name {
+			return nil, fmt.Errorf("unknown bot %q", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("bot %q was picked twice", name)
+		}
+		seen[name] = true
+		botMembers = append(botMembers, models.TeamMember{
+			UserID:      primitive.NewObjectID(),
+			DisplayName: bot.Name,
+			Elo:         float64(bot.Rating),
+			JoinedAt:    now,
+			IsBot:       true,
+		})
+		totalRating += float64(bot.Rating)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := db.GetCollection("team_debates")
+	count, err := collection.CountDocuments(ctx, bson.M{
+		"$or":    []bson.M{{"team1Id": team.ID}, {"team2Id": team.ID}},
+		"status": "active",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("your team already has an active debate")
+	}
+
+	team1Stance, team2Stance := "for", "against"
+	switch strings.ToLower(strings.TrimSpace(stance)) {
+	case "for":
+	case "against":
+		team1Stance, team2Stance = "against", "for"
+	default:
+		if now.Unix()%2 != 0 {
+			team1Stance, team2Stance = "against", "for"
+		}
+	}
+
+	debate := &models.TeamDebate{
+		Team1ID:      team.ID,
+		Team2ID:      primitive.NewObjectID(),
+		Team1Name:    team.Name,
+		Team2Name:    strings.Join(botNames, " & "),
+		Team1Members: team.Members,
+		Team2Members: botMembers,
+		Topic:        topic,
+		Team1Stance:  team1Stance,
+		Team2Stance:  team2Stance,
+		Status:       "active",
+		CurrentTurn:  "team1",
+		TurnCount:    0,
+		MaxTurns:     12,
+		Team1Elo:     team.AverageElo,
+		Team2Elo:     totalRating / float64(len(botMembers)),
+		Mode:         models.TeamDebateModeVsBots,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	result, err := collection.InsertOne(ctx, debate)
+	if err != nil {
+		return nil, err
+	}
+	debate.ID = result.InsertedID.(primitive.ObjectID)
+	return debate, nil
+}
+
+// GenerateTeamBotSpeech produces a bot team member's speech for a phase. In history, "Bot" marks
+// speeches by the bot team and "User" marks speeches by the human team.
+func GenerateTeamBotSpeech(botName, topic, format, stance, phaseKey string, history []models.Message) (string, []models.Citation) {
+	bot := GetBotPersonality(botName)
+	turnContext := "You speak for a team of AI debaters facing a team of human debaters. Earlier speeches marked as yours were given by your teammates; build on them instead of repeating them."
+	if phase, ok := formatPhase(format, phaseKey); ok {
+		turnContext += fmt.Sprintf(" This turn is the %s (%s). %s", phase.Label, phase.Key, phase.Instruction)
+	}
+	return GenerateBotResponse(botName, bot.Level, topic, history, stance, turnContext, teamBotMaxWords, nil)
+}
@@ -0,0 +1,475 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	minChallengeLead       = 10 * time.Minute    // Earliest a challenge can be scheduled from now
+	maxChallengeLead       = 60 * 24 * time.Hour // Latest a challenge can be scheduled from now
+	challengeSlotLength    = time.Hour           // A team cannot have two challenges closer than this
+	challengeStartGrace    = 30 * time.Minute    // How long a slot waits for a team still busy in another debate
+	maxChallengeReschedule = 5
+	challengeSchedulerTick = 30 * time.Second
+)
+
+// teamChallengeReminders are sent to both teams ahead of an accepted challenge, latest last
+var teamChallengeReminders = []struct {
+	Key    string
+	Before time.Duration
+}{
+	{"24h", 24 * time.Hour},
+	{"1h", time.Hour},
+	{"10m", 10 * time.Minute},
+}
+
+var ErrChallengeNotFound = errors.New("challenge not found or no longer open")
+
+// CreateTeamChallenge has one team challenge another to a debate at a given time
+func CreateTeamChallenge(challenger models.Team, createdBy, opponentID primitive.ObjectID, topic, format string, scheduledAt time.Time) (*models.TeamChallenge, error) {
+	topic = strings.TrimSpace(topic)
+	if topic == "" {
+		return nil, errors.New("topic is required")
+	}
+	if format == "" {
+		format = "standard"
+	}
+	if _, ok := debateFormats[format]; !ok {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if challenger.ID == opponentID {
+		return nil, errors.New("a team cannot challenge itself")
+	}
+	if err := validateChallengeTime(scheduledAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var opponent models.Team
+	if err := db.GetCollection("teams").FindOne(ctx, bson.M{"_id": opponentID}).Decode(&opponent); err != nil {
+		return nil, errors.New("opponent team not found")
+	}
+	if err := checkTeamPairing(format, challenger.MaxSize, opponent.MaxSize); err != nil {
+		return nil, err
+	}
+	if err := checkChallengeConflict([]primitive.ObjectID{challenger.ID, opponentID}, scheduledAt, primitive.NilObjectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	challenge := &models.TeamChallenge{
+		ChallengerTeamID: challenger.ID,
+		ChallengerName:   challenger.Name,
+		OpponentTeamID:   opponent.ID,
+		OpponentName:     opponent.Name,
+		CreatedBy:        createdBy,
+		Topic:            topic,
+		Format:           format,
+		ScheduledAt:      scheduledAt,
+		ProposedBy:       challenger.ID,
+		Status:           models.TeamChallengePending,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	result, err := db.GetCollection("team_challenges").InsertOne(ctx, challenge)
+	if err != nil {
+		return nil, err
+	}
+	challenge.ID = result.InsertedID.(primitive.ObjectID)
+
+	go notifyTeamLeads(opponent, "Team Challenge",
+		fmt.Sprintf("%s challenged %s to debate '%s' on %s", challenger.Name, opponent.Name, topic, scheduledAt.UTC().Format(time.RFC1123)))
+	return challenge, nil
+}
+
+// GetTeamChallenge returns a challenge by ID
+func GetTeamChallenge(challengeID primitive.ObjectID) (*models.TeamChallenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var challenge models.TeamChallenge
+	if err := db.GetCollection("team_challenges").FindOne(ctx, bson.M{"_id": challengeID}).Decode(&challenge); err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// ListTeamChallenges returns the challenges a team sent or received, soonest first. Finished
+// challenges are only included when includeClosed is set.
+func ListTeamChallenges(teamID primitive.ObjectID, includeClosed bool) ([]models.TeamChallenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"$or": []bson.M{{"challengerTeamId": teamID}, {"opponentTeamId": teamID}}}
+	if !includeClosed {
+		filter["status"] = bson.M{"$in": bson.A{models.TeamChallengePending, models.TeamChallengeAccepted, models.TeamChallengeStarting}}
+	}
+	cursor, err := db.GetCollection("team_challenges").Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "scheduledAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	challenges := []models.TeamChallenge{}
+	if err := cursor.All(ctx, &challenges); err != nil {
+		return nil, err
+	}
+	return challenges, nil
+}
+
+// AcceptTeamChallenge accepts the time the other team proposed
+func AcceptTeamChallenge(challengeID, teamID primitive.ObjectID) (*models.TeamChallenge, error) {
+	challenge, err := updateTeamChallenge(bson.M{
+		"_id":         challengeID,
+		"status":      models.TeamChallengePending,
+		"proposedBy":  bson.M{"$ne": teamID},
+		"scheduledAt": bson.M{"$gt": time.Now()},
+		"$or":         []bson.M{{"challengerTeamId": teamID}, {"opponentTeamId": teamID}},
+	}, bson.M{"$set": bson.M{"status": models.TeamChallengeAccepted, "updatedAt": time.Now()}})
+	if err != nil {
+		return nil, err
+	}
+
+	go notifyChallengeTeams(*challenge, "Team Challenge Accepted",
+		fmt.Sprintf("%s vs %s on '%s' is confirmed for %s", challenge.ChallengerName, challenge.OpponentName, challenge.Topic, challenge.ScheduledAt.UTC().Format(time.RFC1123)))
+	return challenge, nil
+}
+
+// DeclineTeamChallenge turns a challenge down. When the challenging team does it, the challenge is cancelled instead.
+func DeclineTeamChallenge(challengeID, teamID primitive.ObjectID) (*models.TeamChallenge, error) {
+	challenge, err := GetTeamChallenge(challengeID)
+	if err != nil {
+		return nil, ErrChallengeNotFound
+	}
+	status := models.TeamChallengeDeclined
+	if challenge.ChallengerTeamID == teamID {
+		status = models.TeamChallengeCancelled
+	}
+
+	challenge, err = updateTeamChallenge(bson.M{
+		"_id":    challengeID,
+		"status": bson.M{"$in": bson.A{models.TeamChallengePending, models.TeamChallengeAccepted}},
+		"$or":    []bson.M{{"challengerTeamId": teamID}, {"opponentTeamId": teamID}},
+	}, bson.M{"$set": bson.M{"status": status, "updatedAt": time.Now()}})
+	if err != nil {
+		return nil, err
+	}
+
+	title := "Team Challenge Declined"
+	if status == models.TeamChallengeCancelled {
+		title = "Team Challenge Cancelled"
+	}
+	go notifyChallengeTeams(*challenge, title,
+		fmt.Sprintf("%s vs %s on '%s' will not take place", challenge.ChallengerName, challenge.OpponentName, challenge.Topic))
+	return challenge, nil
+}
+
+// RescheduleTeamChallenge proposes a new time; the other team then has to accept it
+func RescheduleTeamChallenge(challengeID, teamID primitive.ObjectID, scheduledAt time.Time) (*models.TeamChallenge, error) {
+	if err := validateChallengeTime(scheduledAt, time.Now()); err != nil {
+		return nil, err
+	}
+	challenge, err := GetTeamChallenge(challengeID)
+	if err != nil {
+		return nil, ErrChallengeNotFound
+	}
+	if challenge.Reschedules >= maxChallengeReschedule {
+		return nil, errors.New("this challenge has been rescheduled too many times")
+	}
+	if err := checkChallengeConflict([]primitive.ObjectID{challenge.ChallengerTeamID, challenge.OpponentTeamID}, scheduledAt, challengeID); err != nil {
+		return nil, err
+	}
+
+	challenge, err = updateTeamChallenge(bson.M{
+		"_id":         challengeID,
+		"status":      bson.M{"$in": bson.A{models.TeamChallengePending, models.TeamChallengeAccepted}},
+		"reschedules": challenge.Reschedules,
+		"$or":         []bson.M{{"challengerTeamId": teamID}, {"opponentTeamId": teamID}},
+	}, bson.M{
+		"$set": bson.M{
+			"scheduledAt":   scheduledAt,
+			"proposedBy":    teamID,
+			"status":        models.TeamChallengePending,
+			"remindersSent": []string{},
+			"updatedAt":     time.Now(),
+		},
+		"$inc": bson.M{"reschedules": 1},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	proposer, other := challenge.ChallengerName, challenge.OpponentName
+	if teamID == challenge.OpponentTeamID {
+		proposer, other = other, proposer
+	}
+	go notifyChallengeTeams(*challenge, "Team Challenge Rescheduled",
+		fmt.Sprintf("%s proposed moving the debate with %s on '%s' to %s", proposer, other, challenge.Topic, scheduledAt.UTC().Format(time.RFC1123)))
+	return challenge, nil
+}
+
+// StartTeamChallengeScheduler sends reminders for accepted challenges, starts their debates when
+// the slot opens and expires challenges that were never accepted. It blocks, so run it in a goroutine.
+func StartTeamChallengeScheduler() {
+	// Challenges left half-started by a previous run are retried
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	db.GetCollection("team_challenges").UpdateMany(ctx,
+		bson.M{"status": models.TeamChallengeStarting},
+		bson.M{"$set": bson.M{"status": models.TeamChallengeAccepted}},
+	)
+	cancel()
+
+	ticker := time.NewTicker(challengeSchedulerTick)
+	defer ticker.Stop()
+	for range ticker.C {
+		processTeamChallenges(time.Now())
+	}
+}
+
+// processTeamChallenges runs one pass of the challenge scheduler
+func processTeamChallenges(now time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	collection := db.GetCollection("team_challenges")
+
+	// Expire challenges whose slot passed before the other team accepted
+	cursor, err := collection.Find(ctx, bson.M{"status": models.TeamChallengePending, "scheduledAt": bson.M{"$lte": now}})
+	if err != nil {
+		log.Printf("[challenges] Failed to load pending challenges: %v", err)
+		return
+	}
+	var pending []models.TeamChallenge
+	if err := cursor.All(ctx, &pending); err != nil {
+		log.Printf("[challenges] Failed to decode pending challenges: %v", err)
+	}
+	for _, challenge := range pending {
+		if expired, err := updateTeamChallenge(bson.M{"_id": challenge.ID, "status": models.TeamChallengePending},
+			bson.M{"$set": bson.M{"status": models.TeamChallengeExpired, "updatedAt": now}}); err == nil {
+			go notifyChallengeTeams(*expired, "Team Challenge Expired",
+				fmt.Sprintf("%s vs %s on '%s' expired before it was accepted", expired.ChallengerName, expired.OpponentName, expired.Topic))
+		}
+	}
+
+	// Remind and start accepted challenges
+	cursor, err = collection.Find(ctx, bson.M{
+		"status":      models.TeamChallengeAccepted,
+		"scheduledAt": bson.M{"$lte": now.Add(teamChallengeReminders[0].Before)},
+	})
+	if err != nil {
+		log.Printf("[challenges] Failed to load accepted challenges: %v", err)
+		return
+	}
+	var accepted []models.TeamChallenge
+	if err := cursor.All(ctx, &accepted); err != nil {
+		log.Printf("[challenges] Failed to decode accepted challenges: %v", err)
+		return
+	}
+	for _, challenge := range accepted {
+		if !challenge.ScheduledAt.After(now) {
+			startTeamChallenge(challenge, now)
+			continue
+		}
+		if key, sent := dueTeamChallengeReminder(challenge.ScheduledAt, now, challenge.RemindersSent); key != "" {
+			_, err := updateTeamChallenge(bson.M{"_id": challenge.ID, "status": models.TeamChallengeAccepted, "remindersSent": bson.M{"$ne": key}},
+				bson.M{"$addToSet": bson.M{"remindersSent": bson.M{"$each": sent}}})
+			if err == nil {
+				go notifyChallengeTeams(challenge, "Team Debate Reminder",
+					fmt.Sprintf("%s vs %s on '%s' starts in %s", challenge.ChallengerName, challenge.OpponentName, challenge.Topic, challenge.ScheduledAt.Sub(now).Round(time.Minute)))
+			}
+		}
+	}
+}
+
+// dueTeamChallengeReminder returns the latest reminder that is due and not sent yet, along with
+// every reminder it supersedes so that a late acceptance does not trigger a burst of reminders
+func dueTeamChallengeReminder(scheduledAt, now time.Time, sent []string) (string, []string) {
+	alreadySent := make(map[string]bool, len(sent))
+	for _, key := range sent {
+		alreadySent[key] = true
+	}
+
+	due := ""
+	var superseded []string
+	for _, reminder := range teamChallengeReminders {
+		if now.Before(scheduledAt.Add(-reminder.Before)) {
+			break
+		}
+		superseded = append(superseded, reminder.Key)
+		due = reminder.Key
+	}
+	if due == "" || alreadySent[due] {
+		return "", nil
+	}
+	return due, superseded
+}
+
+// startTeamChallenge creates the debate for an accepted challenge whose slot has opened
+func startTeamChallenge(challenge models.TeamChallenge, now time.Time) {
+	claimed, err := updateTeamChallenge(bson.M{"_id": challenge.ID, "status": models.TeamChallengeAccepted},
+		bson.M{"$set": bson.M{"status": models.TeamChallengeStarting, "updatedAt": now}})
+	if err != nil {
+		return
+	}
+
+	teams, err := loadChallengeTeams(*claimed)
+	if err == nil {
+		for _, team := range teams {
+			busy, busyErr := HasActiveTeamDebate(team.ID)
+			if busyErr != nil {
+				err = busyErr
+				break
+			}
+			if busy {
+				err = fmt.Errorf("team %s is still in another debate", team.Name)
+				break
+			}
+		}
+	}
+
+	var debate *models.TeamDebate
+	if err == nil {
+		debate, err = CreateTeamDebate(teams[0], teams[1], claimed.Topic, claimed.Format)
+	}
+	if err != nil {
+		status := models.TeamChallengeAccepted
+		if now.After(claimed.ScheduledAt.Add(challengeStartGrace)) {
+			status = models.TeamChallengeExpired
+		}
+		log.Printf("[challenges] Could not start challenge %s: %v", claimed.ID.Hex(), err)
+		updateTeamChallenge(bson.M{"_id": claimed.ID}, bson.M{"$set": bson.M{"status": status, "updatedAt": now}})
+		if status == models.TeamChallengeExpired {
+			go notifyChallengeTeams(*claimed, "Team Challenge Expired",
+				fmt.Sprintf("%s vs %s on '%s' could not start: %v", claimed.ChallengerName, claimed.OpponentName, claimed.Topic, err))
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db.GetCollection("team_debates").UpdateOne(ctx, bson.M{"_id": debate.ID}, bson.M{"$set": bson.M{"challengeId": claimed.ID}})
+	updateTeamChallenge(bson.M{"_id": claimed.ID}, bson.M{"$set": bson.M{
+		"status":    models.TeamChallengeStarted,
+		"debateId":  debate.ID,
+		"updatedAt": now,
+	}})
+
+	RemoveFromMatchmaking(teams[0].ID)
+	RemoveFromMatchmaking(teams[1].ID)
+
+	go func() {
+		for _, member := range append(teams[0].Members, teams[1].Members...) {
+			CreateNotification(
+				member.UserID,
+				models.NotificationTypeTournament,
+				"Team Debate Started",
+				"Your scheduled team debate on '"+claimed.Topic+"' has started!",
+				"/team-debate/"+debate.ID.Hex(),
+			)
+		}
+	}()
+}
+
+// validateChallengeTime checks a proposed challenge time is within the bookable window
+func validateChallengeTime(scheduledAt, now time.Time) error {
+	if scheduledAt.Before(now.Add(minChallengeLead)) {
+		return fmt.Errorf("challenges must be scheduled at least %d minutes ahead", int(minChallengeLead.Minutes()))
+	}
+	if scheduledAt.After(now.Add(maxChallengeLead)) {
+		return fmt.Errorf("challenges cannot be scheduled more than %d days ahead", int(maxChallengeLead.Hours()/24))
+	}
+	return nil
+}
+
+// checkChallengeConflict rejects a time that overlaps another open challenge of either team
+func checkChallengeConflict(teamIDs []primitive.ObjectID, scheduledAt time.Time, excludeID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":    bson.M{"$ne": excludeID},
+		"status": bson.M{"$in": bson.A{models.TeamChallengePending, models.TeamChallengeAccepted}},
+		"$or": []bson.M{
+			{"challengerTeamId": bson.M{"$in": teamIDs}},
+			{"opponentTeamId": bson.M{"$in": teamIDs}},
+		},
+		"scheduledAt": bson.M{
+			"$gt": scheduledAt.Add(-challengeSlotLength),
+			"$lt": scheduledAt.Add(challengeSlotLength),
+		},
+	}
+	count, err := db.GetCollection("team_challenges").CountDocuments(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return errors.New("one of the teams already has a challenge within an hour of that time")
+	}
+	return nil
+}
+
+// updateTeamChallenge applies an update to the challenge matching filter and returns it afterwards
+func updateTeamChallenge(filter, update bson.M) (*models.TeamChallenge, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var challenge models.TeamChallenge
+	err := db.GetCollection("team_challenges").FindOneAndUpdate(ctx, filter, update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After)).Decode(&challenge)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrChallengeNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &challenge, nil
+}
+
+// loadChallengeTeams loads the challenging and the challenged team, in that order
+func loadChallengeTeams(challenge models.TeamChallenge) ([]models.Team, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	teams := make([]models.Team, 2)
+	for i, teamID := range []primitive.ObjectID{challenge.ChallengerTeamID, challenge.OpponentTeamID} {
+		if err := db.GetCollection("teams").FindOne(ctx, bson.M{"_id": teamID}).Decode(&teams[i]); err != nil {
+			return nil, fmt.Errorf("team %s not found: %w", teamID.Hex(), err)
+		}
+	}
+	return teams, nil
+}
+
+// notifyChallengeTeams notifies every member and coach of both teams about a challenge
+func notifyChallengeTeams(challenge models.TeamChallenge, title, message string) {
+	teams, err := loadChallengeTeams(challenge)
+	if err != nil {
+		log.Printf("[challenges] Failed to notify teams of challenge %s: %v", challenge.ID.Hex(), err)
+		return
+	}
+	for _, team := range teams {
+		NotifyTeam(team, primitive.NilObjectID, title, message)
+	}
+}
+
+// notifyTeamLeads notifies the members of a team who can answer challenges
+func notifyTeamLeads(team models.Team, title, message string) {
+	for _, member := range team.Members {
+		if TeamCan(team, member.UserID, TeamPermLeadDebate) {
+			CreateNotification(member.UserID, models.NotificationTypeTournament, title, message, "/team/"+team.ID.Hex())
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDueTeamChallengeReminder(t *testing.T) {
+	start := time.Date(2026, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		now       time.Time
+		sent      []string
+		wantKey   string
+		wantMarks []string
+	}{
+		{"too early", start.Add(-48 * time.Hour), nil, "", nil},
+		{"day before", start.Add(-23 * time.Hour), nil, "24h", []string{"24h"}},
+		{"day reminder already sent", start.Add(-23 * time.Hour), []string{"24h"}, "", nil},
+		{"hour before", start.Add(-50 * time.Minute), []string{"24h"}, "1h", []string{"24h", "1h"}},
+		{"accepted late skips earlier reminders", start.Add(-5 * time.Minute), nil, "10m", []string{"24h", "1h", "10m"}},
+	}
+	for _, tc := range cases {
+		key, marks := dueTeamChallengeReminder(start, tc.now, tc.sent)
+		if key != tc.wantKey || !reflect.DeepEqual(marks, tc.wantMarks) {
+			t.Errorf("%s: expected %q %v, got %q %v", tc.name, tc.wantKey, tc.wantMarks, key, marks)
+		}
+	}
+}
+
+func TestValidateChallengeTime(t *testing.T) {
+	now := time.Now()
+	if err := validateChallengeTime(now.Add(5*time.Minute), now); err == nil {
+		t.Error("expected a slot five minutes away to be rejected")
+	}
+	if err := validateChallengeTime(now.Add(90*24*time.Hour), now); err == nil {
+		t.Error("expected a slot three months away to be rejected")
+	}
+	if err := validateChallengeTime(now.Add(48*time.Hour), now); err != nil {
+		t.Errorf("expected a slot two days away to be accepted, got %v", err)
+	}
+}
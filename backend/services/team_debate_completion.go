@@ -0,0 +1,583 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// teamDebateTranscriptType is the SavedDebateTranscript debate type for team debates
+	teamDebateTranscriptType = "team_vs_team"
+	speakerStatsCollection   = "speaker_stats"
+)
+
+// teamSpeaker is one speaker in a team debate together with what they contributed
+type teamSpeaker struct {
+	TeamID       primitive.ObjectID
+	UserID       primitive.ObjectID
+	DisplayName  string
+	Stance       string
+	IsBot        bool
+	Contribution MemberContribution
+}
+
+// ErrTeamDebateNotFinished is returned when completing a debate that has not reached its finished phase
+var ErrTeamDebateNotFinished = errors.New("the debate has not reached its finished phase")
+
+// TeamSpeakingTimeSource reports how many seconds each member of a debate spoke, keyed by user ID (hex).
+// It returns nil when nothing is known about the debate.
+type TeamSpeakingTimeSource func(debateID primitive.ObjectID) map[string]int
+
+var teamSpeakingTimeSource TeamSpeakingTimeSource
+
+// SetTeamSpeakingTimeSource sets where completion reads speaking time from, so a debate completed
+// through the REST API stores it just like one completed from its room
+func SetTeamSpeakingTimeSource(source TeamSpeakingTimeSource) {
+	teamSpeakingTimeSource = source
+}
+
+// CompleteTeamDebate judges a team debate, records the outcome on the debate, updates ratings and
+// saves a transcript for every human member. Only a running debate whose stored phase is "finished"
+// can be judged, and only by the first call; later calls return the debate as it is.
+func CompleteTeamDebate(debateID primitive.ObjectID) (*models.TeamDebate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+
+	collection := db.GetCollection("team_debates")
+	claim, err := collection.UpdateOne(ctx,
+		bson.M{"_id": debateID, "status": "active", "currentPhase": "finished"},
+		bson.M{"$set": bson.M{"status": "judging", "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	var debate models.TeamDebate
+	if err := collection.FindOne(ctx, bson.M{"_id": debateID}).Decode(&debate); err != nil {
+		return nil, err
+	}
+	if claim.ModifiedCount == 0 {
+		if debate.Status == "active" {
+			return nil, ErrTeamDebateNotFinished
+		}
+		// Already judged, or being judged by another caller
+		return &debate, nil
+	}
+
+	if teamSpeakingTimeSource != nil {
+		if speakingTime := teamSpeakingTimeSource(debateID); len(speakingTime) > 0 {
+			if err := RecordTeamSpeakingTime(debateID, speakingTime); err != nil {
+				log.Printf("[CompleteTeamDebate] Failed to record speaking time for %s: %v", debateID.Hex(), err)
+			} else {
+				debate.SpeakingTime = speakingTime
+			}
+		}
+	}
+
+	// Until the outcome is written, any failure hands the debate back so it can be judged again
+	finished := false
+	defer func() {
+		if !finished {
+			releaseTeamDebateClaim(debateID)
+		}
+	}()
+
+	merged, messages, speakers := assembleTeamTranscript(&debate)
+	result := JudgeDebateHumanVsHuman(merged)
+	if !isLikelyJSONResult(result) {
+		result = buildFallbackJudgeResult(merged)
+	}
+	debate.Result = result
+	debate.Winner = teamDebateWinner(&debate, judgeWinnerSide(result))
+	debate.SpeakerResults = judgeTeamSpeakers(ctx, &debate, speakers)
+
+	// The outcome is stored before ratings and career stats change, so a failed write never leaves
+	// ratings applied to a debate that still looks unfinished
+	now := time.Now()
+	debate.Status = "finished"
+	debate.FinishedAt = &now
+	debate.UpdatedAt = now
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": debateID, "status": "judging"}, bson.M{"$set": bson.M{
+		"status":         debate.Status,
+		"result":         debate.Result,
+		"winner":         debate.Winner,
+		"speakerResults": debate.SpeakerResults,
+		"finishedAt":     debate.FinishedAt,
+		"updatedAt":      debate.UpdatedAt,
+	}})
+	if err != nil {
+		return nil, err
+	}
+	finished = true
+
+	// Bots have no rating, so debates against them are unrated
+	if debate.Mode != models.TeamDebateModeVsBots {
+		updateTeamDebateRatings(ctx, &debate)
+		_, err = collection.UpdateOne(ctx, bson.M{"_id": debateID}, bson.M{"$set": bson.M{
+			"speakerResults": debate.SpeakerResults,
+			"team1EloAfter":  debate.Team1EloAfter,
+			"team2EloAfter":  debate.Team2EloAfter,
+		}})
+		if err != nil {
+			log.Printf("failed to store ratings for team debate %s: %v", debateID.Hex(), err)
+		}
+	}
+	saveTeamDebateTranscripts(&debate, messages, merged)
+	updateSpeakerCareerStats(ctx, debate.SpeakerResults)
+
+	go notifyTeamDebateFinished(debate)
+	return &debate, nil
+}
+
+// releaseTeamDebateClaim puts a debate that failed to be judged back to active
+func releaseTeamDebateClaim(debateID primitive.ObjectID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.GetCollection("team_debates").UpdateOne(ctx,
+		bson.M{"_id": debateID, "status": "judging"},
+		bson.M{"$set": bson.M{"status": "active", "updatedAt": time.Now()}},
+	)
+	if err != nil {
+		log.Printf("failed to release judging claim on team debate %s: %v", debateID.Hex(), err)
+	}
+}
+
+// assembleTeamTranscript merges every recorded speech into the phase transcript used for judging,
+// a readable message log, and the per-speaker contributions. Speeches given outside the speaker's
+// own phases stay in the log but are not judged as part of the phase. Contributions are replayed
+// from the stored speeches because the room's turn manager does not outlive the room.
+func assembleTeamTranscript(debate *models.TeamDebate) (map[string]string, []models.Message, []*teamSpeaker) {
+	speeches := append([]models.TeamDebateSpeech(nil), debate.Speeches...)
+	sort.SliceStable(speeches, func(i, j int) bool {
+		return speeches[i].Timestamp.Before(speeches[j].Timestamp)
+	})
+
+	var speakers []*teamSpeaker
+	byUser := make(map[primitive.ObjectID]*teamSpeaker)
+	addSpeaker := func(teamID, userID primitive.ObjectID, name string, isBot bool) *teamSpeaker {
+		if speaker, ok := byUser[userID]; ok {
+			return speaker
+		}
+		stance := debate.Team1Stance
+		if teamID == debate.Team2ID {
+			stance = debate.Team2Stance
+		}
+		speaker := &teamSpeaker{TeamID: teamID, UserID: userID, DisplayName: name, Stance: stance, IsBot: isBot}
+		byUser[userID] = speaker
+		speakers = append(speakers, speaker)
+		return speaker
+	}
+	// Every member gets a result, including those who never spoke
+	for _, member := range debate.Team1Members {
+		addSpeaker(debate.Team1ID, member.UserID, member.DisplayName, member.IsBot)
+	}
+	for _, member := range debate.Team2Members {
+		addSpeaker(debate.Team2ID, member.UserID, member.DisplayName, member.IsBot)
+	}
+
+	turnManager := NewTeamTurnManager()
+	merged := make(map[string]string)
+	messages := make([]models.Message, 0, len(speeches))
+	for _, speech := range speeches {
+		text := strings.TrimSpace(speech.Text)
+		if text == "" {
+			continue
+		}
+		speaker := addSpeaker(speech.TeamID, speech.UserID, speech.DisplayName, speech.IsBot)
+		turnManager.RecordContribution(speech.TeamID, speech.UserID, speech.Phase, text)
+
+		teamName := debate.Team1Name
+		if speech.TeamID == debate.Team2ID {
+			teamName = debate.Team2Name
+		}
+		messages = append(messages, models.Message{
+			Sender:    fmt.Sprintf("%s (%s)", speech.DisplayName, teamName),
+			Text:      text,
+			Phase:     speech.Phase,
+			Citations: speech.Citations,
+		})
+
+		if PhaseSide(debate.Format, speech.Phase) == speaker.Stance {
+			merged[speech.Phase] = strings.TrimSpace(merged[speech.Phase] + " " + text)
+		}
+	}
+	for _, speaker := range speakers {
+		speaker.Contribution = turnManager.GetContribution(speaker.TeamID, speaker.UserID)
+	}
+	return merged, messages, speakers
+}
+
+// judgeWinnerSide reads the winning side ("for" or "against") from a judge result, or "" for a draw
+func judgeWinnerSide(result string) string {
+	var judgeResponse struct {
+		Verdict struct {
+			Winner string `json:"winner"`
+		} `json:"verdict"`
+	}
+	if err := json.Unmarshal([]byte(result), &judgeResponse); err != nil {
+		return ""
+	}
+	switch strings.ToLower(strings.TrimSpace(judgeResponse.Verdict.Winner)) {
+	case "for":
+		return "for"
+	case "against":
+		return "against"
+	}
+	return ""
+}
+
+// teamDebateWinner maps the winning side onto "team1", "team2" or "draw"
+func teamDebateWinner(debate *models.TeamDebate, side string) string {
+	switch {
+	case side != "" && strings.EqualFold(side, debate.Team1Stance):
+		return "team1"
+	case side != "" && strings.EqualFold(side, debate.Team2Stance):
+		return "team2"
+	}
+	return "draw"
+}
+
+// teamResultFor returns "win", "loss" or "draw" for one team
+func teamResultFor(debate *models.TeamDebate, teamID primitive.ObjectID) string {
+	switch debate.Winner {
+	case "team1":
+		if teamID == debate.Team1ID {
+			return "win"
+		}
+		return "loss"
+	case "team2":
+		if teamID == debate.Team2ID {
+			return "win"
+		}
+		return "loss"
+	}
+	return "draw"
+}
+
+// judgeTeamSpeakers awards each speaker points for content, style and strategy (0-10 each) with
+// feedback. Gemini is used when available; otherwise the points come from heuristicSpeakerPoints.
+func judgeTeamSpeakers(ctx context.Context, debate *models.TeamDebate, speakers []*teamSpeaker) []models.TeamSpeakerResult {
+	results := make([]models.TeamSpeakerResult, 0, len(speakers))
+	index := make(map[string]int, len(speakers))
+	spoken := 0
+	for _, speaker := range speakers {
+		contribution := speaker.Contribution
+		content, style, strategy := heuristicSpeakerPoints(contribution, speaker.Stance)
+		feedback := fmt.Sprintf("Scored without a judge model from %d words across %d speeches.", contribution.Words, len(contribution.Speeches))
+		if contribution.Words == 0 {
+			feedback = "Did not speak during the debate."
+		} else {
+			spoken++
+		}
+		index[speaker.UserID.Hex()] = len(results)
+		results = append(results, models.TeamSpeakerResult{
+			TeamID:      speaker.TeamID,
+			UserID:      speaker.UserID,
+			DisplayName: speaker.DisplayName,
+			IsBot:       speaker.IsBot,
+			Speeches:    len(contribution.Speeches),
+			Words:       contribution.Words,
+			Content:     content,
+			Style:       style,
+			Strategy:    strategy,
+			Points:      content + style + strategy,
+			Feedback:    feedback,
+		})
+	}
+	if geminiClient == nil || spoken == 0 {
+		return results
+	}
+
+	var transcript strings.Builder
+	for _, speaker := range speakers {
+		if len(speaker.Contribution.Speeches) == 0 {
+			continue
+		}
+		transcript.WriteString(fmt.Sprintf("Speaker %s — %s (side: %s):\n%s\n\n",
+			speaker.UserID.Hex(), speaker.DisplayName, speaker.Stance, strings.Join(speaker.Contribution.Speeches, "\n")))
+	}
+	prompt := fmt.Sprintf(`Act as a professional debate judge awarding speaker points in a team debate on the topic "%s".
+Score every speaker from 0 to 10 on each of:
+- content: the quality of their arguments, reasoning and evidence
+- style: clarity, structure and persuasiveness of their delivery
+- strategy: how well they fulfilled their role in each phase and engaged the other team's arguments
+Give each speaker one or two sentences of feedback addressed to them.
+
+Return ONLY JSON in this format:
+{
+  "speakers": [{"id": "speaker id", "content": 0, "style": 0, "strategy": 0, "feedback": "text"}]
+}
+
+Speeches by speaker:
+%s`, debate.Topic, transcript.String())
+
+	text, err := generateDefaultModelText(ctx, prompt)
+	if err != nil {
+		log.Printf("Falling back to heuristic speaker points: %v", err)
+		return results
+	}
+	var judged struct {
+		Speakers []struct {
+			ID       string `json:"id"`
+			Content  int    `json:"content"`
+			Style    int    `json:"style"`
+			Strategy int    `json:"strategy"`
+			Feedback string `json:"feedback"`
+		} `json:"speakers"`
+	}
+	if err := json.Unmarshal([]byte(text), &judged); err != nil {
+		log.Printf("Falling back to heuristic speaker points: %v", err)
+		return results
+	}
+	for _, s := range judged.Speakers {
+		i, ok := index[s.ID]
+		if !ok || results[i].Words == 0 {
+			continue
+		}
+		results[i].Content = clampScore(s.Content, 10)
+		results[i].Style = clampScore(s.Style, 10)
+		results[i].Strategy = clampScore(s.Strategy, 10)
+		results[i].Points = results[i].Content + results[i].Style + results[i].Strategy
+		if strings.TrimSpace(s.Feedback) != "" {
+			results[i].Feedback = strings.TrimSpace(s.Feedback)
+		}
+	}
+	return results
+}
+
+// heuristicSpeakerPoints scores a contribution without a model: content from the structure of the
+// arguments, style from sentence length and fallacies, and strategy from speaking in the speaker's
+// own phases rather than out of turn.
+func heuristicSpeakerPoints(contribution MemberContribution, stance string) (content, style, strategy int) {
+	if contribution.Words == 0 {
+		return 0, 0, 0
+	}
+	text := strings.Join(contribution.Speeches, " ")
+	analysis := heuristicArgumentAnalysis(text)
+
+	content = fallbackScoreFromWords(contribution.Words)/2 + len(analysis.Warrants) + len(analysis.Evidence)
+
+	style = 6 - 2*len(analysis.Fallacies)
+	if sentences := len(sentenceSplitRegex.FindAllString(text, -1)); sentences > 0 {
+		if average := contribution.Words / sentences; average >= 8 && average <= 25 {
+			style += 2
+		}
+	}
+	if contribution.Words >= 60 {
+		style++
+	}
+
+	for phase, count := range contribution.Phases {
+		switch side := phaseKeySide(phase); {
+		case side == "":
+		case strings.EqualFold(side, stance):
+			strategy += 2
+			if strings.HasPrefix(phase, "closing") {
+				strategy++
+			}
+		default:
+			strategy -= count
+		}
+	}
+
+	return clampScore(content, 10), clampScore(style, 10), clampScore(strategy, 10)
+}
+
+func clampScore(score, max int) int {
+	if score < 0 {
+		return 0
+	}
+	if score > max {
+		return max
+	}
+	return score
+}
+
+// updateSpeakerCareerStats adds each human speaker's points to their career totals
+func updateSpeakerCareerStats(ctx context.Context, results []models.TeamSpeakerResult) {
+	collection := db.GetCollection(speakerStatsCollection)
+	for _, result := range results {
+		if result.IsBot {
+			continue
+		}
+		_, err := collection.UpdateOne(ctx,
+			bson.M{"userId": result.UserID},
+			bson.M{
+				"$inc": bson.M{
+					"debates":       1,
+					"totalContent":  result.Content,
+					"totalStyle":    result.Style,
+					"totalStrategy": result.Strategy,
+					"totalPoints":   result.Points,
+				},
+				"$max": bson.M{"bestPoints": result.Points},
+				"$set": bson.M{"updatedAt": time.Now()},
+			},
+			options.Update().SetUpsert(true),
+		)
+		if err != nil {
+			log.Printf("failed to update speaker stats for %s: %v", result.UserID.Hex(), err)
+		}
+	}
+}
+
+// GetSpeakerCareerStats returns a user's aggregated speaker points, or nil if they have no team debates
+func GetSpeakerCareerStats(userID primitive.ObjectID) (*models.SpeakerCareerStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var stats models.SpeakerCareerStats
+	err := db.GetCollection(speakerStatsCollection).FindOne(ctx, bson.M{"userId": userID}).Decode(&stats)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+// updateTeamDebateRatings pairs members of the two teams and updates their ratings. When the teams
+// differ in size, members of the smaller team are paired more than once.
+func updateTeamDebateRatings(ctx context.Context, debate *models.TeamDebate) {
+	team1, team2 := debate.Team1Members, debate.Team2Members
+	if len(team1) == 0 || len(team2) == 0 {
+		return
+	}
+
+	outcome := 0.5
+	switch debate.Winner {
+	case "team1":
+		outcome = 1.0
+	case "team2":
+		outcome = 0.0
+	}
+	result1 := teamResultFor(debate, debate.Team1ID)
+	result2 := teamResultFor(debate, debate.Team2ID)
+
+	preRatings := make(map[primitive.ObjectID]float64)
+	postRatings := make(map[primitive.ObjectID]float64)
+	var records []interface{}
+	pairs := len(team1)
+	if len(team2) > pairs {
+		pairs = len(team2)
+	}
+	for i := 0; i < pairs; i++ {
+		member1, member2 := team1[i%len(team1)], team2[i%len(team2)]
+		record1, record2, err := UpdateRatings(member1.UserID, member2.UserID, outcome, time.Now())
+		if err != nil {
+			log.Printf("failed to update ratings for %s vs %s: %v", member1.UserID.Hex(), member2.UserID.Hex(), err)
+			continue
+		}
+		record1.Topic, record1.Result = debate.Topic, result1
+		record2.Topic, record2.Result = debate.Topic, result2
+		records = append(records, record1, record2)
+
+		if _, ok := preRatings[member1.UserID]; !ok {
+			preRatings[member1.UserID] = record1.PreRating
+		}
+		if _, ok := preRatings[member2.UserID]; !ok {
+			preRatings[member2.UserID] = record2.PreRating
+		}
+		postRatings[member1.UserID] = record1.PostRating
+		postRatings[member2.UserID] = record2.PostRating
+	}
+	if len(records) == 0 {
+		return
+	}
+	if _, err := db.MongoDatabase.Collection("debates").InsertMany(ctx, records); err != nil {
+		log.Printf("failed to store rating history for team debate %s: %v", debate.ID.Hex(), err)
+	}
+
+	for i := range debate.SpeakerResults {
+		userID := debate.SpeakerResults[i].UserID
+		if post, ok := postRatings[userID]; ok {
+			debate.SpeakerResults[i].PreRating = preRatings[userID]
+			debate.SpeakerResults[i].PostRating = post
+		}
+	}
+	debate.Team1EloAfter = updateTeamMemberElo(ctx, debate.Team1ID, postRatings)
+	debate.Team2EloAfter = updateTeamMemberElo(ctx, debate.Team2ID, postRatings)
+}
+
+// updateTeamMemberElo stores members' new ratings on the team and returns its new average Elo
+func updateTeamMemberElo(ctx context.Context, teamID primitive.ObjectID, ratings map[primitive.ObjectID]float64) float64 {
+	collection := db.GetCollection("teams")
+	var team models.Team
+	if err := collection.FindOne(ctx, bson.M{"_id": teamID}).Decode(&team); err != nil {
+		log.Printf("failed to load team %s for rating update: %v", teamID.Hex(), err)
+		return 0
+	}
+	if len(team.Members) == 0 {
+		return team.AverageElo
+	}
+
+	totalElo := 0.0
+	for i := range team.Members {
+		if rating, ok := ratings[team.Members[i].UserID]; ok {
+			team.Members[i].Elo = rating
+		}
+		totalElo += team.Members[i].Elo
+	}
+	averageElo := totalElo / float64(len(team.Members))
+
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": teamID}, bson.M{"$set": bson.M{
+		"members":    team.Members,
+		"averageElo": averageElo,
+		"updatedAt":  time.Now(),
+	}})
+	if err != nil {
+		log.Printf("failed to update ratings for team %s: %v", teamID.Hex(), err)
+	}
+	return averageElo
+}
+
+// saveTeamDebateTranscripts saves the debate to the transcript history of every human member
+func saveTeamDebateTranscripts(debate *models.TeamDebate, messages []models.Message, merged map[string]string) {
+	save := func(members []models.TeamMember, teamID primitive.ObjectID, opponent string) {
+		result := teamResultFor(debate, teamID)
+		for _, member := range members {
+			if member.IsBot {
+				continue
+			}
+			err := saveDebateTranscript(debate.ID.Hex(), member.UserID, member.Email, teamDebateTranscriptType, debate.Topic, opponent, result, messages, merged)
+			if err != nil {
+				log.Printf("failed to save team debate transcript for %s: %v", member.UserID.Hex(), err)
+			}
+		}
+	}
+	save(debate.Team1Members, debate.Team1ID, debate.Team2Name)
+	save(debate.Team2Members, debate.Team2ID, debate.Team1Name)
+}
+
+func notifyTeamDebateFinished(debate models.TeamDebate) {
+	for _, members := range [][]models.TeamMember{debate.Team1Members, debate.Team2Members} {
+		for _, member := range members {
+			if member.IsBot {
+				continue
+			}
+			CreateNotification(
+				member.UserID,
+				models.NotificationTypeTournament,
+				"Team Debate Finished",
+				"Your team debate on '"+debate.Topic+"' has been judged.",
+				"/team-debate/"+debate.ID.Hex(),
+			)
+		}
+	}
+}
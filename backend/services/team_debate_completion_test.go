@@ -0,0 +1,106 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAssembleTeamTranscript(t *testing.T) {
+	start := time.Now()
+	alice := models.TeamMember{UserID: primitive.NewObjectID(), DisplayName: "Alice"}
+	bob := models.TeamMember{UserID: primitive.NewObjectID(), DisplayName: "Bob"}
+	quiet := models.TeamMember{UserID: primitive.NewObjectID(), DisplayName: "Quiet"}
+	yoda := models.TeamMember{UserID: primitive.NewObjectID(), DisplayName: "Yoda", IsBot: true}
+
+	debate := &models.TeamDebate{
+		Team1ID:      primitive.NewObjectID(),
+		Team2ID:      primitive.NewObjectID(),
+		Team1Name:    "Humans",
+		Team2Name:    "Yoda",
+		Team1Members: []models.TeamMember{alice, bob, quiet},
+		Team2Members: []models.TeamMember{yoda},
+		Team1Stance:  "for",
+		Team2Stance:  "against",
+	}
+	speech := func(team primitive.ObjectID, m models.TeamMember, phase, text string, offset int) models.TeamDebateSpeech {
+		return models.TeamDebateSpeech{TeamID: team, UserID: m.UserID, DisplayName: m.DisplayName, IsBot: m.IsBot,
+			Phase: phase, Text: text, Timestamp: start.Add(time.Duration(offset) * time.Second)}
+	}
+	debate.Speeches = []models.TeamDebateSpeech{
+		speech(debate.Team1ID, bob, "openingFor", "and it saves money", 2),
+		speech(debate.Team1ID, alice, "openingFor", "Remote work helps", 1),
+		speech(debate.Team2ID, yoda, "openingAgainst", "Wrong, you are", 3),
+		// Interruption outside the speaker's own phase
+		speech(debate.Team1ID, alice, "openingAgainst", "Not true!", 4),
+	}
+
+	merged, messages, speakers := assembleTeamTranscript(debate)
+
+	if merged["openingFor"] != "Remote work helps and it saves money" {
+		t.Errorf("expected speeches merged in time order, got %q", merged["openingFor"])
+	}
+	if merged["openingAgainst"] != "Wrong, you are" {
+		t.Errorf("expected out-of-phase speech to be left out, got %q", merged["openingAgainst"])
+	}
+	if len(messages) != 4 || messages[0].Sender != "Alice (Humans)" {
+		t.Errorf("expected all speeches in the log, got %v", messages)
+	}
+	if len(speakers) != 4 {
+		t.Fatalf("expected every member to be a speaker, got %d", len(speakers))
+	}
+	for _, s := range speakers {
+		if s.UserID == quiet.UserID && s.Contribution.Words != 0 {
+			t.Errorf("expected no contribution from a silent member, got %+v", s.Contribution)
+		}
+		if s.UserID == alice.UserID && (len(s.Contribution.Speeches) != 2 || s.Contribution.Phases["openingAgainst"] != 1) {
+			t.Errorf("expected two speeches for Alice, got %+v", s.Contribution)
+		}
+	}
+}
+
+func TestTeamDebateWinner(t *testing.T) {
+	debate := &models.TeamDebate{Team1Stance: "against", Team2Stance: "for"}
+
+	cases := map[string]string{
+		`{"verdict": {"winner": "For"}}`:     "team2",
+		`{"verdict": {"winner": "Against"}}`: "team1",
+		`{"verdict": {"winner": "Draw"}}`:    "draw",
+		"Unable to judge.":                   "draw",
+	}
+	for result, want := range cases {
+		if got := teamDebateWinner(debate, judgeWinnerSide(result)); got != want {
+			t.Errorf("result %q: expected %s, got %s", result, want, got)
+		}
+	}
+}
+
+func TestHeuristicSpeakerPoints(t *testing.T) {
+	turnManager := NewTeamTurnManager()
+	teamID, focused, heckler := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	turnManager.RecordContribution(teamID, focused, "openingFor",
+		"Cities should ban cars downtown because traffic deaths fall when streets are pedestrianised. "+
+			"According to a 2019 study, Oslo recorded zero pedestrian deaths after its ban.")
+	turnManager.RecordContribution(teamID, focused, "closingFor",
+		"We showed the ban saves lives, and the other side never answered the Oslo evidence.")
+	turnManager.RecordContribution(teamID, heckler, "openingAgainst", "You are stupid and everyone knows it.")
+	turnManager.RecordContribution(teamID, heckler, "crossAgainstAnswer", "Wrong again, you idiot.")
+
+	content, style, strategy := heuristicSpeakerPoints(turnManager.GetContribution(teamID, focused), "for")
+	hContent, hStyle, hStrategy := heuristicSpeakerPoints(turnManager.GetContribution(teamID, heckler), "for")
+
+	if content <= hContent || style <= hStyle || strategy <= hStrategy {
+		t.Errorf("expected the focused speaker to outscore the heckler, got %d/%d/%d vs %d/%d/%d",
+			content, style, strategy, hContent, hStyle, hStrategy)
+	}
+	if hStrategy != 0 {
+		t.Errorf("expected no strategy points for speaking only out of turn, got %d", hStrategy)
+	}
+	if c, s, st := heuristicSpeakerPoints(MemberContribution{}, "for"); c+s+st != 0 {
+		t.Errorf("expected zero points for a silent speaker, got %d/%d/%d", c, s, st)
+	}
+}
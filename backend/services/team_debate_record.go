@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RecordTeamDebateSpeech appends a speech to the team debate record
+func RecordTeamDebateSpeech(debateID primitive.ObjectID, speech models.TeamDebateSpeech) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.GetCollection("team_debates").UpdateOne(ctx, bson.M{"_id": debateID}, bson.M{
+		"$push": bson.M{"speeches": speech},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+// RecordTeamSubstitution appends a substitution to the team debate record
+func RecordTeamSubstitution(debateID primitive.ObjectID, substitution models.TeamSubstitution) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.GetCollection("team_debates").UpdateOne(ctx, bson.M{"_id": debateID}, bson.M{
+		"$push": bson.M{"substitutions": substitution},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+// RecordTeamTimeout counts a timeout called by a team
+func RecordTeamTimeout(debateID, teamID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.GetCollection("team_debates").UpdateOne(ctx, bson.M{"_id": debateID}, bson.M{
+		"$inc": bson.M{"timeoutsUsed." + teamID.Hex(): 1},
+		"$set": bson.M{"updatedAt": time.Now()},
+	})
+	return err
+}
+
+// RecordTeamSpeakingTime stores how many seconds each member spoke, keyed by user ID (hex)
+func RecordTeamSpeakingTime(debateID primitive.ObjectID, speakingTime map[string]int) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err := db.GetCollection("team_debates").UpdateOne(ctx, bson.M{"_id": debateID}, bson.M{
+		"$set": bson.M{"speakingTime": speakingTime, "updatedAt": time.Now()},
+	})
+	return err
+}
+
+// CreateTeamDebate creates an active debate between two teams with their current rosters and
+// random stances. The debate room opens when the first member connects to it.
+func CreateTeamDebate(team1, team2 models.Team, topic, format string) (*models.TeamDebate, error) {
+	if team1.ID == team2.ID {
+		return nil, errors.New("cannot create a debate with the same team on both sides")
+	}
+	if format == "" {
+		format = "standard"
+	}
+	if _, ok := debateFormats[format]; !ok {
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	if err := checkTeamPairing(format, len(team1.Members), len(team2.Members)); err != nil {
+		return nil, err
+	}
+
+	team1Stance, team2Stance := "for", "against"
+	if time.Now().Unix()%2 != 0 {
+		team1Stance, team2Stance = "against", "for"
+	}
+
+	now := time.Now()
+	debate := &models.TeamDebate{
+		Team1ID:      team1.ID,
+		Team2ID:      team2.ID,
+		Team1Name:    team1.Name,
+		Team2Name:    team2.Name,
+		Team1Members: team1.Members,
+		Team2Members: team2.Members,
+		Topic:        topic,
+		Team1Stance:  team1Stance,
+		Team2Stance:  team2Stance,
+		Status:       "active",
+		CurrentTurn:  "team1",
+		TurnCount:    0,
+		MaxTurns:     12, // 12 total turns (6 per team)
+		Team1Elo:     team1.AverageElo,
+		Team2Elo:     team2.AverageElo,
+		Format:       format,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := db.GetCollection("team_debates").InsertOne(ctx, debate)
+	if err != nil {
+		return nil, err
+	}
+	debate.ID = result.InsertedID.(primitive.ObjectID)
+	return debate, nil
+}
+
+// HasActiveTeamDebate reports whether the team is already in an active debate
+func HasActiveTeamDebate(teamID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	count, err := db.GetCollection("team_debates").CountDocuments(ctx, bson.M{
+		"$or":    []bson.M{{"team1Id": teamID}, {"team2Id": teamID}},
+		"status": "active",
+	})
+	return count > 0, err
+}
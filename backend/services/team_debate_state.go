@@ -0,0 +1,206 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrTeamDebateStateConflict is returned when the turn state changed since the version a change
+	// was based on. The current state is returned alongside it.
+	ErrTeamDebateStateConflict = errors.New("the debate has moved on since this change was made")
+	// ErrTeamDebateNotActive is returned for turn changes to a debate that is no longer running
+	ErrTeamDebateNotActive = errors.New("the debate is no longer running")
+	// ErrUnknownTeamDebatePhase is returned for phase changes to a phase team debates do not have
+	ErrUnknownTeamDebatePhase = errors.New("unknown debate phase")
+	// ErrTeamDebatePhaseOutOfOrder is returned for phase changes that skip or go back in the format
+	ErrTeamDebatePhaseOutOfOrder = errors.New("the debate can only move on to its next phase")
+	// ErrNotTeamDebateLead is returned when someone other than a captain or co-captain changes the phase
+	ErrNotTeamDebateLead = errors.New("only a team captain or co-captain can change the phase")
+)
+
+// TeamDebateStateListener is told about every committed turn state change
+type TeamDebateStateListener func(state models.TeamDebateState)
+
+var teamDebateStateListener TeamDebateStateListener
+
+// SetTeamDebateStateListener sets the function told about turn state changes, so live rooms follow
+// changes made through the REST API
+func SetTeamDebateStateListener(listener TeamDebateStateListener) {
+	teamDebateStateListener = listener
+}
+
+// GetTeamDebateState returns the stored turn state of a team debate
+func GetTeamDebateState(debateID primitive.ObjectID) (*models.TeamDebateState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var debate models.TeamDebate
+	if err := db.GetCollection("team_debates").FindOne(ctx, bson.M{"_id": debateID}).Decode(&debate); err != nil {
+		return nil, err
+	}
+	state := debate.State()
+	return &state, nil
+}
+
+// AdvanceTeamDebatePhase moves a debate on to its next phase if its state is still at expectedVersion.
+// Entering a speaking phase hands the turn to the team arguing that side and counts a turn. It is
+// for changes the server makes itself; changes asked for by a user go through LeadTeamDebatePhase.
+func AdvanceTeamDebatePhase(debateID primitive.ObjectID, expectedVersion int, phase string) (*models.TeamDebateState, error) {
+	return advanceTeamDebatePhase(debateID, expectedVersion, phase, nil)
+}
+
+// LeadTeamDebatePhase moves a debate on to its next phase on behalf of a user, who must be the
+// captain or a co-captain of one of the debating teams
+func LeadTeamDebatePhase(debateID, userID primitive.ObjectID, expectedVersion int, phase string) (*models.TeamDebateState, error) {
+	return advanceTeamDebatePhase(debateID, expectedVersion, phase, func(debate models.TeamDebate) error {
+		for _, teamID := range []primitive.ObjectID{debate.Team1ID, debate.Team2ID} {
+			if debate.IsBotTeam(teamID) {
+				continue
+			}
+			leads, err := HasTeamPermission(teamID, userID, TeamPermLeadDebate)
+			if err != nil {
+				return err
+			}
+			if leads {
+				return nil
+			}
+		}
+		return ErrNotTeamDebateLead
+	})
+}
+
+// advanceTeamDebatePhase moves a debate on to phase, which must be the phase after the current one
+// in the debate's format. authorize, when set, vets the change against the current debate.
+func advanceTeamDebatePhase(debateID primitive.ObjectID, expectedVersion int, phase string, authorize func(models.TeamDebate) error) (*models.TeamDebateState, error) {
+	if phaseKeySide(phase) == "" && phase != "setup" && phase != "finished" {
+		return nil, fmt.Errorf("%w %q", ErrUnknownTeamDebatePhase, phase)
+	}
+
+	return updateTeamDebateState(debateID, expectedVersion, func(debate models.TeamDebate) (bson.M, error) {
+		side := PhaseSide(debate.Format, phase)
+		if side == "" && phase != "setup" && phase != "finished" {
+			return nil, fmt.Errorf("%w %q", ErrUnknownTeamDebatePhase, phase)
+		}
+		if authorize != nil {
+			if err := authorize(debate); err != nil {
+				return nil, err
+			}
+		}
+		current := debate.State().Phase
+		if current == phase {
+			return nil, nil
+		}
+		if err := checkPhaseTransition(debate.Format, current, phase); err != nil {
+			return nil, err
+		}
+		update := bson.M{"$set": bson.M{"currentPhase": phase}}
+		if side != "" {
+			currentTurn := "team2"
+			if strings.EqualFold(debate.Team1Stance, side) {
+				currentTurn = "team1"
+			}
+			update["$set"].(bson.M)["currentTurn"] = currentTurn
+			update["$inc"] = bson.M{"turnCount": 1}
+		}
+		return update, nil
+	})
+}
+
+// checkPhaseTransition refuses a move from current to phase unless phase comes next in the format
+func checkPhaseTransition(format, current, phase string) error {
+	if current == "finished" || NextPhase(format, current) != phase {
+		return fmt.Errorf("%w: %s cannot follow %s", ErrTeamDebatePhaseOutOfOrder, phase, current)
+	}
+	return nil
+}
+
+// SetTeamDebateSpeaker records who holds the floor if the debate's state is still at expectedVersion
+func SetTeamDebateSpeaker(debateID primitive.ObjectID, expectedVersion int, userID primitive.ObjectID) (*models.TeamDebateState, error) {
+	return updateTeamDebateState(debateID, expectedVersion, func(debate models.TeamDebate) (bson.M, error) {
+		if debate.CurrentUserID == userID {
+			return nil, nil
+		}
+		return bson.M{"$set": bson.M{"currentUserId": userID}}, nil
+	})
+}
+
+// updateTeamDebateState applies the update built from the current debate, provided the debate is
+// running and still at expectedVersion, and bumps the version. A nil update leaves the state as it is.
+func updateTeamDebateState(debateID primitive.ObjectID, expectedVersion int, build func(models.TeamDebate) (bson.M, error)) (*models.TeamDebateState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := db.GetCollection("team_debates")
+	var debate models.TeamDebate
+	if err := collection.FindOne(ctx, bson.M{"_id": debateID}).Decode(&debate); err != nil {
+		return nil, err
+	}
+	current := debate.State()
+	if debate.Status != "active" {
+		return &current, ErrTeamDebateNotActive
+	}
+	if debate.Version != expectedVersion {
+		return &current, ErrTeamDebateStateConflict
+	}
+
+	update, err := build(debate)
+	if err != nil {
+		return &current, err
+	}
+	if update == nil {
+		return &current, nil
+	}
+	if _, ok := update["$set"]; !ok {
+		update["$set"] = bson.M{}
+	}
+	update["$set"].(bson.M)["updatedAt"] = time.Now()
+	if inc, ok := update["$inc"].(bson.M); ok {
+		inc["version"] = 1
+	} else {
+		update["$inc"] = bson.M{"version": 1}
+	}
+
+	var updated models.TeamDebate
+	err = collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": debateID, "status": "active", "version": versionFilter(expectedVersion)},
+		update,
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&updated)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Someone else changed the state between the read and the write
+		latest, loadErr := GetTeamDebateState(debateID)
+		if loadErr != nil {
+			return nil, loadErr
+		}
+		return latest, ErrTeamDebateStateConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	state := updated.State()
+	if teamDebateStateListener != nil {
+		teamDebateStateListener(state)
+	}
+	return &state, nil
+}
+
+// versionFilter matches expectedVersion, treating debates created before versioning as version 0
+func versionFilter(expectedVersion int) interface{} {
+	if expectedVersion == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return expectedVersion
+}
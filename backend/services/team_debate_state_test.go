@@ -0,0 +1,59 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAdvanceTeamDebatePhaseRejectsUnknownPhase(t *testing.T) {
+	for _, phase := range []string{"", "countdown", "rebuttalFor"} {
+		if _, err := AdvanceTeamDebatePhase(primitive.NewObjectID(), 0, phase); !errors.Is(err, ErrUnknownTeamDebatePhase) {
+			t.Errorf("AdvanceTeamDebatePhase(%q) error = %v, want ErrUnknownTeamDebatePhase", phase, err)
+		}
+	}
+}
+
+func TestCheckPhaseTransitionFollowsFormat(t *testing.T) {
+	cases := []struct {
+		format, current, phase string
+		ok                     bool
+	}{
+		{"standard", "setup", "openingFor", true},
+		{"standard", "openingAgainst", "crossForQuestion", true},
+		{"lightning", "openingAgainst", "closingFor", true},
+		{"lightning", "closingAgainst", "finished", true},
+		{"standard", "setup", "finished", false},
+		{"standard", "openingAgainst", "closingFor", false},
+		{"standard", "closingFor", "openingFor", false},
+		{"standard", "finished", "setup", false},
+	}
+	for _, tc := range cases {
+		err := checkPhaseTransition(tc.format, tc.current, tc.phase)
+		if ok := err == nil; ok != tc.ok {
+			t.Errorf("%s: %s -> %s allowed = %v, want %v", tc.format, tc.current, tc.phase, ok, tc.ok)
+		}
+		if err != nil && !errors.Is(err, ErrTeamDebatePhaseOutOfOrder) {
+			t.Errorf("%s: %s -> %s error = %v, want ErrTeamDebatePhaseOutOfOrder", tc.format, tc.current, tc.phase, err)
+		}
+	}
+}
+
+func TestTeamTurnManagerSetCurrentTurn(t *testing.T) {
+	turnManager := NewTeamTurnManager()
+	teamID := primitive.NewObjectID()
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	if err := turnManager.InitializeTurnOrder(teamID, []models.TeamMember{{UserID: a}, {UserID: b}}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !turnManager.SetCurrentTurn(teamID, b) || turnManager.GetCurrentTurn(teamID) != b {
+		t.Error("expected the turn to be restored to a lineup member")
+	}
+	if turnManager.SetCurrentTurn(teamID, primitive.NewObjectID()) || turnManager.GetCurrentTurn(teamID) != b {
+		t.Error("expected a speaker outside the lineup to be ignored")
+	}
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"sync"
 	"time"
 
@@ -18,10 +19,18 @@ var (
 	teamMatchmakingMutex sync.RWMutex
 )
 
+// teamMatchmakingEloRange is the widest average Elo gap between matched teams
+const teamMatchmakingEloRange = 200
+
+// ErrRosterTooSmall is returned when a team has too few members to queue for matchmaking
+var ErrRosterTooSmall = errors.New("team does not have enough members to queue")
+
 type TeamMatchmakingEntry struct {
 	TeamID     primitive.ObjectID
 	Team       models.Team
 	MaxSize    int
+	RosterSize int
+	Formats    []string
 	AverageElo float64
 	Timestamp  time.Time
 }
@@ -38,9 +47,9 @@ func StartTeamMatchmaking(teamID primitive.ObjectID) error {
 		return err
 	}
 
-	// Check if team is full
-	if len(team.Members) < team.MaxSize {
-		return mongo.ErrNoDocuments // Team not ready
+	// Partially full teams may queue once they have the minimum roster
+	if len(team.Members) < MinimumRoster(team.MaxSize) {
+		return ErrRosterTooSmall
 	}
 
 	teamMatchmakingMutex.Lock()
@@ -51,15 +60,55 @@ func StartTeamMatchmaking(teamID primitive.ObjectID) error {
 		teamMatchmakingPool = make(map[string]*TeamMatchmakingEntry)
 	}
 
-	teamMatchmakingPool[teamID.Hex()] = &TeamMatchmakingEntry{
-		TeamID:     teamID,
+	teamMatchmakingPool[teamID.Hex()] = newTeamMatchmakingEntry(team, time.Now())
+
+	return nil
+}
+
+// RefreshTeamMatchmaking updates a queued team's entry after its size or roster changed, keeping
+// its place in the queue. Teams that no longer have the minimum roster are removed. It returns
+// whether the team is still queued.
+func RefreshTeamMatchmaking(teamID primitive.ObjectID) bool {
+	teamMatchmakingMutex.RLock()
+	entry, exists := teamMatchmakingPool[teamID.Hex()]
+	teamMatchmakingMutex.RUnlock()
+	if !exists {
+		return false
+	}
+
+	collection := db.GetCollection("teams")
+	var team models.Team
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := collection.FindOne(ctx, bson.M{"_id": teamID}).Decode(&team); err != nil {
+		RemoveFromMatchmaking(teamID)
+		return false
+	}
+
+	teamMatchmakingMutex.Lock()
+	defer teamMatchmakingMutex.Unlock()
+
+	if _, stillQueued := teamMatchmakingPool[teamID.Hex()]; !stillQueued {
+		return false
+	}
+	if len(team.Members) < MinimumRoster(team.MaxSize) {
+		delete(teamMatchmakingPool, teamID.Hex())
+		return false
+	}
+	teamMatchmakingPool[teamID.Hex()] = newTeamMatchmakingEntry(team, entry.Timestamp)
+	return true
+}
+
+func newTeamMatchmakingEntry(team models.Team, queuedAt time.Time) *TeamMatchmakingEntry {
+	return &TeamMatchmakingEntry{
+		TeamID:     team.ID,
 		Team:       team,
 		MaxSize:    team.MaxSize,
+		RosterSize: len(team.Members),
+		Formats:    TeamFormatsFor(len(team.Members)),
 		AverageElo: team.AverageElo,
-		Timestamp:  time.Now(),
+		Timestamp:  queuedAt,
 	}
-
-	return nil
 }
 
 // FindMatchingTeam finds a team that matches the given team's criteria
@@ -76,27 +125,62 @@ func FindMatchingTeam(lookingTeamID primitive.ObjectID) (*models.Team, error) {
 		return nil, mongo.ErrNoDocuments
 	}
 
-	// Find teams with matching size and similar elo
-	for teamID, entry := range teamMatchmakingPool {
-		if teamID == lookingTeamID.Hex() {
+	if best := bestTeamMatch(lookingEntry, teamMatchmakingPool); best != nil {
+		return &best.Team, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+// bestTeamMatch picks the closest compatible team for an entry: rosters within one member of each
+// other that share a format, with similar Elo. Closer sizes win, then closer Elo, then whoever
+// queued first.
+func bestTeamMatch(looking *TeamMatchmakingEntry, pool map[string]*TeamMatchmakingEntry) *TeamMatchmakingEntry {
+	var best *TeamMatchmakingEntry
+	var bestSizeGap int
+	var bestEloDiff float64
+
+	for _, entry := range pool {
+		if entry.TeamID == looking.TeamID {
+			continue
+		}
+		if !CompatibleTeamSizes(entry.RosterSize, looking.RosterSize) || len(sharedFormats(entry.Formats, looking.Formats)) == 0 {
 			continue
 		}
 
-		// Check if sizes match
-		if entry.MaxSize == lookingEntry.MaxSize {
-			// Check if elo difference is acceptable (within 200 points)
-			eloDiff := entry.AverageElo - lookingEntry.AverageElo
-			if eloDiff < 0 {
-				eloDiff = -eloDiff
-			}
+		eloDiff := entry.AverageElo - looking.AverageElo
+		if eloDiff < 0 {
+			eloDiff = -eloDiff
+		}
+		if eloDiff > teamMatchmakingEloRange {
+			continue
+		}
 
-			if eloDiff <= 200 {
-				return &entry.Team, nil
-			}
+		sizeGap := entry.RosterSize - looking.RosterSize
+		if sizeGap < 0 {
+			sizeGap = -sizeGap
+		}
+
+		if best == nil || sizeGap < bestSizeGap ||
+			(sizeGap == bestSizeGap && (eloDiff < bestEloDiff ||
+				(eloDiff == bestEloDiff && entry.Timestamp.Before(best.Timestamp)))) {
+			best, bestSizeGap, bestEloDiff = entry, sizeGap, eloDiff
 		}
 	}
+	return best
+}
 
-	return nil, mongo.ErrNoDocuments
+// sharedFormats returns the formats in both lists
+func sharedFormats(a, b []string) []string {
+	var shared []string
+	for _, format := range a {
+		for _, other := range b {
+			if format == other {
+				shared = append(shared, format)
+				break
+			}
+		}
+	}
+	return shared
 }
 
 // RemoveFromMatchmaking removes a team from the matchmaking pool
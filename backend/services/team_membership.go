@@ -0,0 +1,444 @@
+package services
+
+import (
+	"context"
+	cryptoRand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TeamPermission is something a team role allows its holder to do
+type TeamPermission string
+
+const (
+	TeamPermManageTeam    TeamPermission = "manage_team"    // Rename, resize or delete the team and assign roles
+	TeamPermManageMembers TeamPermission = "manage_members" // Invite members, decide join requests, remove regular members
+	TeamPermLeadDebate    TeamPermission = "lead_debate"    // Captain controls during a debate
+	TeamPermViewPrep      TeamPermission = "view_prep"      // Read the team's prep rooms and debate reviews
+)
+
+var teamRolePermissions = map[string][]TeamPermission{
+	models.TeamRoleCaptain:   {TeamPermManageTeam, TeamPermManageMembers, TeamPermLeadDebate, TeamPermViewPrep},
+	models.TeamRoleCoCaptain: {TeamPermManageMembers, TeamPermLeadDebate, TeamPermViewPrep},
+	models.TeamRoleMember:    {TeamPermViewPrep},
+	models.TeamRoleCoach:     {TeamPermViewPrep},
+}
+
+const (
+	defaultInvitationTTL = 72 * time.Hour
+	maxInvitationTTL     = 30 * 24 * time.Hour
+	maxInvitationUses    = 50
+)
+
+var (
+	ErrAlreadyInTeam     = errors.New("user is already in a team")
+	ErrTeamFull          = errors.New("team is already full")
+	ErrInvitationInvalid = errors.New("invitation is invalid, expired or used up")
+)
+
+// TeamCan reports whether the user's role in the team grants the permission
+func TeamCan(team models.Team, userID primitive.ObjectID, permission TeamPermission) bool {
+	for _, granted := range teamRolePermissions[team.RoleOf(userID)] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// HasTeamPermission loads the team and reports whether the user's role grants the permission
+func HasTeamPermission(teamID, userID primitive.ObjectID, permission TeamPermission) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var team models.Team
+	if err := db.GetCollection("teams").FindOne(ctx, bson.M{"_id": teamID}).Decode(&team); err != nil {
+		return false, err
+	}
+	return TeamCan(team, userID, permission), nil
+}
+
+// NotifyTeam sends a notification to every member and coach of the team except skipUserID
+func NotifyTeam(team models.Team, skipUserID primitive.ObjectID, title, message string) {
+	link := "/team/" + team.ID.Hex()
+	for _, member := range append(append([]models.TeamMember{}, team.Members...), team.Coaches...) {
+		if member.UserID == skipUserID {
+			continue
+		}
+		CreateNotification(member.UserID, models.NotificationTypeSystem, title, message, link)
+	}
+}
+
+// InitTeamIndexes creates the unique index that keeps a user in at most one team. Teams without
+// members are left out of it.
+func InitTeamIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	_, err := db.GetCollection("teams").Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "members.userId", Value: 1}},
+		Options: options.Index().
+			SetUnique(true).
+			SetPartialFilterExpression(bson.M{"members.userId": bson.M{"$exists": true}}),
+	})
+	return err
+}
+
+// AddTeamMember adds a member to the team if they are not in a team yet and the team has room.
+// It returns the team as it was before the member joined. The unique index on members.userId
+// settles two teams taking the same user at once.
+func AddTeamMember(teamID primitive.ObjectID, member models.TeamMember) (*models.Team, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	collection := db.GetCollection("teams")
+	count, err := collection.CountDocuments(ctx, bson.M{"members.userId": member.UserID})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrAlreadyInTeam
+	}
+
+	var team models.Team
+	if err := collection.FindOne(ctx, bson.M{"_id": teamID}).Decode(&team); err != nil {
+		return nil, err
+	}
+	capacity := team.MaxSize
+	if capacity <= 0 {
+		capacity = 4
+	}
+
+	member.Role = ""
+	member.JoinedAt = time.Now()
+	result, err := collection.UpdateOne(ctx,
+		bson.M{
+			"_id":                                 teamID,
+			"members." + strconv.Itoa(capacity-1): bson.M{"$exists": false},
+			"members.userId":                      bson.M{"$ne": member.UserID},
+			"coaches.userId":                      bson.M{"$ne": member.UserID},
+		},
+		bson.M{
+			"$push": bson.M{"members": member},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrAlreadyInTeam
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		// The same join may have just gone through on another request
+		if joined, err := collection.CountDocuments(ctx, bson.M{"_id": teamID, "members.userId": member.UserID}); err == nil && joined > 0 {
+			return nil, ErrAlreadyInTeam
+		}
+		return nil, ErrTeamFull
+	}
+	return &team, RefreshTeamAverageElo(teamID)
+}
+
+// AddTeamCoach adds a coach to the team. Coaches cannot coach a team they debate for.
+func AddTeamCoach(teamID primitive.ObjectID, coach models.TeamMember) (*models.Team, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := db.GetCollection("teams")
+	var team models.Team
+	if err := collection.FindOne(ctx, bson.M{"_id": teamID}).Decode(&team); err != nil {
+		return nil, err
+	}
+	if team.RoleOf(coach.UserID) != "" {
+		return nil, errors.New("user is already part of this team")
+	}
+
+	coach.Role = ""
+	coach.JoinedAt = time.Now()
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": teamID, "coaches.userId": bson.M{"$ne": coach.UserID}}, bson.M{
+		"$push": bson.M{"coaches": coach},
+		"$set":  bson.M{"updatedAt": time.Now()},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// RefreshTeamAverageElo recomputes the team's average rating from its members
+func RefreshTeamAverageElo(teamID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := db.GetCollection("teams")
+	var team models.Team
+	if err := collection.FindOne(ctx, bson.M{"_id": teamID}).Decode(&team); err != nil {
+		return err
+	}
+	if len(team.Members) == 0 {
+		return nil
+	}
+	total := 0.0
+	for _, member := range team.Members {
+		total += member.Elo
+	}
+	_, err := collection.UpdateOne(ctx, bson.M{"_id": teamID}, bson.M{
+		"$set": bson.M{"averageElo": total / float64(len(team.Members))},
+	})
+	return err
+}
+
+// CreateTeamInvitation creates an invitation link for the team
+func CreateTeamInvitation(team models.Team, createdBy primitive.ObjectID, role string, ttl time.Duration, maxUses int) (*models.TeamInvitation, error) {
+	if role == "" {
+		role = models.TeamRoleMember
+	}
+	if role != models.TeamRoleMember && role != models.TeamRoleCoach {
+		return nil, errors.New("invitations can be for members or coaches only")
+	}
+	if ttl <= 0 {
+		ttl = defaultInvitationTTL
+	}
+	if ttl > maxInvitationTTL {
+		ttl = maxInvitationTTL
+	}
+	if maxUses <= 0 {
+		maxUses = 1
+	}
+	if maxUses > maxInvitationUses {
+		maxUses = maxInvitationUses
+	}
+
+	token := make([]byte, 16)
+	if _, err := cryptoRand.Read(token); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	invitation := &models.TeamInvitation{
+		TeamID:    team.ID,
+		TeamName:  team.Name,
+		Token:     hex.EncodeToString(token),
+		Role:      role,
+		CreatedBy: createdBy,
+		MaxUses:   maxUses,
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := db.GetCollection("team_invitations").InsertOne(ctx, invitation)
+	if err != nil {
+		return nil, err
+	}
+	invitation.ID = result.InsertedID.(primitive.ObjectID)
+	return invitation, nil
+}
+
+// GetTeamInvitation returns a usable invitation by its token
+func GetTeamInvitation(token string) (*models.TeamInvitation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var invitation models.TeamInvitation
+	err := db.GetCollection("team_invitations").FindOne(ctx, bson.M{"token": token}).Decode(&invitation)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	if invitation.Revoked || invitation.Uses >= invitation.MaxUses || time.Now().After(invitation.ExpiresAt) {
+		return nil, ErrInvitationInvalid
+	}
+	return &invitation, nil
+}
+
+// ClaimTeamInvitation uses up one use of an invitation
+func ClaimTeamInvitation(token string) (*models.TeamInvitation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var invitation models.TeamInvitation
+	err := db.GetCollection("team_invitations").FindOneAndUpdate(ctx,
+		bson.M{
+			"token":     token,
+			"revoked":   false,
+			"expiresAt": bson.M{"$gt": time.Now()},
+			"$expr":     bson.M{"$lt": bson.A{"$uses", "$maxUses"}},
+		},
+		bson.M{"$inc": bson.M{"uses": 1}},
+		opts,
+	).Decode(&invitation)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInvitationInvalid
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// ReleaseTeamInvitation gives back a use claimed by a join that did not go through
+func ReleaseTeamInvitation(invitationID primitive.ObjectID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db.GetCollection("team_invitations").UpdateOne(ctx, bson.M{"_id": invitationID, "uses": bson.M{"$gt": 0}}, bson.M{"$inc": bson.M{"uses": -1}})
+}
+
+// ListTeamInvitations returns the team's invitations that can still be used, newest first
+func ListTeamInvitations(teamID primitive.ObjectID) ([]models.TeamInvitation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := db.GetCollection("team_invitations").Find(ctx,
+		bson.M{
+			"teamId":    teamID,
+			"revoked":   false,
+			"expiresAt": bson.M{"$gt": time.Now()},
+			"$expr":     bson.M{"$lt": bson.A{"$uses", "$maxUses"}},
+		},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	invitations := []models.TeamInvitation{}
+	if err := cursor.All(ctx, &invitations); err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// RevokeTeamInvitation stops an invitation from being used
+func RevokeTeamInvitation(teamID, invitationID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.GetCollection("team_invitations").UpdateOne(ctx,
+		bson.M{"_id": invitationID, "teamId": teamID},
+		bson.M{"$set": bson.M{"revoked": true}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// CreateTeamJoinRequest files a pending request to join the team. A user can only have one
+// pending request per team.
+func CreateTeamJoinRequest(request models.TeamJoinRequest) (*models.TeamJoinRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := db.GetCollection("team_join_requests")
+	count, err := collection.CountDocuments(ctx, bson.M{"teamId": request.TeamID, "userId": request.UserID, "status": "pending"})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, errors.New("you already asked to join this team")
+	}
+
+	request.Status = "pending"
+	request.CreatedAt = time.Now()
+	result, err := collection.InsertOne(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	request.ID = result.InsertedID.(primitive.ObjectID)
+	return &request, nil
+}
+
+// ListTeamJoinRequests returns the team's pending join requests, oldest first
+func ListTeamJoinRequests(teamID primitive.ObjectID) ([]models.TeamJoinRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := db.GetCollection("team_join_requests").Find(ctx,
+		bson.M{"teamId": teamID, "status": "pending"},
+		options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	requests := []models.TeamJoinRequest{}
+	if err := cursor.All(ctx, &requests); err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// DecideTeamJoinRequest moves a pending join request to the given status and returns it
+func DecideTeamJoinRequest(teamID, requestID, decidedBy primitive.ObjectID, status string) (*models.TeamJoinRequest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var request models.TeamJoinRequest
+	err := db.GetCollection("team_join_requests").FindOneAndUpdate(ctx,
+		bson.M{"_id": requestID, "teamId": teamID, "status": "pending"},
+		bson.M{"$set": bson.M{"status": status, "decidedBy": decidedBy, "decidedAt": now}},
+		opts,
+	).Decode(&request)
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ReopenTeamJoinRequest puts a join request back to pending when approving it failed
+func ReopenTeamJoinRequest(requestID primitive.ObjectID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db.GetCollection("team_join_requests").UpdateOne(ctx, bson.M{"_id": requestID}, bson.M{
+		"$set":   bson.M{"status": "pending"},
+		"$unset": bson.M{"decidedBy": "", "decidedAt": ""},
+	})
+}
+
+// CancelUserJoinRequests closes a user's other pending join requests once they joined a team
+func CancelUserJoinRequests(userID primitive.ObjectID) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	db.GetCollection("team_join_requests").UpdateMany(ctx,
+		bson.M{"userId": userID, "status": "pending"},
+		bson.M{"$set": bson.M{"status": "cancelled", "decidedAt": time.Now()}},
+	)
+}
+
+// CoachedDebateTeam returns the side of the debate the user coaches
+func CoachedDebateTeam(debate models.TeamDebate, userID primitive.ObjectID) (primitive.ObjectID, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var team models.Team
+	err := db.GetCollection("teams").FindOne(ctx, bson.M{
+		"_id":            bson.M{"$in": bson.A{debate.Team1ID, debate.Team2ID}},
+		"coaches.userId": userID,
+	}).Decode(&team)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+	return team.ID, true
+}
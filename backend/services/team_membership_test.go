@@ -0,0 +1,44 @@
+package services
+
+import (
+	"testing"
+
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTeamCan(t *testing.T) {
+	captain, coCaptain, member, coach, stranger := primitive.NewObjectID(), primitive.NewObjectID(),
+		primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	team := models.Team{
+		CaptainID: captain,
+		Members: []models.TeamMember{
+			{UserID: captain},
+			{UserID: coCaptain, Role: models.TeamRoleCoCaptain},
+			{UserID: member},
+		},
+		Coaches: []models.TeamMember{{UserID: coach}},
+	}
+
+	cases := []struct {
+		name       string
+		userID     primitive.ObjectID
+		permission TeamPermission
+		want       bool
+	}{
+		{"captain manages the team", captain, TeamPermManageTeam, true},
+		{"co-captain cannot manage the team", coCaptain, TeamPermManageTeam, false},
+		{"co-captain manages members", coCaptain, TeamPermManageMembers, true},
+		{"co-captain leads debates", coCaptain, TeamPermLeadDebate, true},
+		{"member cannot lead debates", member, TeamPermLeadDebate, false},
+		{"coach views prep", coach, TeamPermViewPrep, true},
+		{"coach cannot manage members", coach, TeamPermManageMembers, false},
+		{"stranger views nothing", stranger, TeamPermViewPrep, false},
+	}
+	for _, tc := range cases {
+		if got := TeamCan(team, tc.userID, tc.permission); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
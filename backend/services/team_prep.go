@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxPrepNotesLength   = 20000
+	maxPrepMessageLength = 2000
+	maxPinnedArguments   = 20
+)
+
+// ErrPrepNotesConflict is returned when the notes were edited since the version the editor started from
+var ErrPrepNotesConflict = errors.New("the notes were changed by a teammate; reload and try again")
+
+// DebateTeamOf returns the team the user plays for in the debate
+func DebateTeamOf(debate models.TeamDebate, userID primitive.ObjectID) (primitive.ObjectID, bool) {
+	for _, member := range debate.Team1Members {
+		if member.UserID == userID {
+			return debate.Team1ID, true
+		}
+	}
+	for _, member := range debate.Team2Members {
+		if member.UserID == userID {
+			return debate.Team2ID, true
+		}
+	}
+	return primitive.NilObjectID, false
+}
+
+// GetTeamPrepRoom returns a team's prep room for a debate, creating it on first use
+func GetTeamPrepRoom(debateID, teamID primitive.ObjectID) (*models.TeamPrepRoom, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+	var room models.TeamPrepRoom
+	err := db.GetCollection("team_prep_rooms").FindOneAndUpdate(ctx,
+		bson.M{"debateId": debateID, "teamId": teamID},
+		bson.M{"$setOnInsert": bson.M{
+			"notes":        "",
+			"notesVersion": 0,
+			"pinned":       []models.TeamPinnedArgument{},
+			"createdAt":    now,
+			"updatedAt":    now,
+		}},
+		opts,
+	).Decode(&room)
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// UpdateTeamPrepNotes replaces the shared notes if nobody saved them since baseVersion. On a
+// conflict it returns ErrPrepNotesConflict together with the current notes.
+func UpdateTeamPrepNotes(debateID, teamID, userID primitive.ObjectID, baseVersion int, notes string) (*models.TeamPrepRoom, error) {
+	if len(notes) > maxPrepNotesLength {
+		return nil, errors.New("notes are too long")
+	}
+	if _, err := GetTeamPrepRoom(debateID, teamID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	var room models.TeamPrepRoom
+	err := db.GetCollection("team_prep_rooms").FindOneAndUpdate(ctx,
+		bson.M{"debateId": debateID, "teamId": teamID, "notesVersion": baseVersion},
+		bson.M{
+			"$set": bson.M{"notes": notes, "notesUpdatedBy": userID, "notesUpdatedAt": now, "updatedAt": now},
+			"$inc": bson.M{"notesVersion": 1},
+		},
+		opts,
+	).Decode(&room)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		current, getErr := GetTeamPrepRoom(debateID, teamID)
+		if getErr != nil {
+			return nil, getErr
+		}
+		return current, ErrPrepNotesConflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// PinTeamArgument pins an argument in the team's prep room
+func PinTeamArgument(debateID, teamID primitive.ObjectID, pin models.TeamPinnedArgument) (*models.TeamPinnedArgument, error) {
+	pin.Text = strings.TrimSpace(pin.Text)
+	if pin.Text == "" {
+		return nil, errors.New("argument text is required")
+	}
+	if len(pin.Text) > maxPrepMessageLength {
+		return nil, errors.New("argument is too long")
+	}
+	if _, err := GetTeamPrepRoom(debateID, teamID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	pin.ID = primitive.NewObjectID()
+	pin.PinnedAt = time.Now()
+	result, err := db.GetCollection("team_prep_rooms").UpdateOne(ctx,
+		bson.M{
+			"debateId": debateID,
+			"teamId":   teamID,
+			// Only pin while there is room left
+			"pinned." + strconv.Itoa(maxPinnedArguments-1): bson.M{"$exists": false},
+		},
+		bson.M{
+			"$push": bson.M{"pinned": pin},
+			"$set":  bson.M{"updatedAt": pin.PinnedAt},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, errors.New("too many pinned arguments; unpin one first")
+	}
+	return &pin, nil
+}
+
+// UnpinTeamArgument removes a pinned argument from the team's prep room
+func UnpinTeamArgument(debateID, teamID, pinID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := db.GetCollection("team_prep_rooms").UpdateOne(ctx,
+		bson.M{"debateId": debateID, "teamId": teamID, "pinned._id": pinID},
+		bson.M{
+			"$pull": bson.M{"pinned": bson.M{"_id": pinID}},
+			"$set":  bson.M{"updatedAt": time.Now()},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("pinned argument not found")
+	}
+	return nil
+}
+
+// AddTeamPrepMessage stores a message sent in a team's prep channel
+func AddTeamPrepMessage(message models.TeamChatMessage) (*models.TeamChatMessage, error) {
+	message.Message = strings.TrimSpace(message.Message)
+	if message.Message == "" {
+		return nil, errors.New("message is empty")
+	}
+	if len(message.Message) > maxPrepMessageLength {
+		return nil, errors.New("message is too long")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	message.Timestamp = time.Now()
+	result, err := db.GetCollection("team_chat_messages").InsertOne(ctx, message)
+	if err != nil {
+		return nil, err
+	}
+	message.ID = result.InsertedID.(primitive.ObjectID)
+	return &message, nil
+}
+
+// GetTeamPrepMessages returns the latest messages of a team's prep channel, oldest first
+func GetTeamPrepMessages(debateID, teamID primitive.ObjectID, limit int64) ([]models.TeamChatMessage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}}).SetLimit(limit)
+	cursor, err := db.GetCollection("team_chat_messages").Find(ctx, bson.M{"debateId": debateID, "teamId": teamID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	messages := []models.TeamChatMessage{}
+	if err := cursor.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
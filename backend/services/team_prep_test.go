@@ -0,0 +1,44 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDebateTeamOfKeepsPrepRoomsPrivate(t *testing.T) {
+	alice, bob, carol := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	debate := models.TeamDebate{
+		Team1ID:      primitive.NewObjectID(),
+		Team2ID:      primitive.NewObjectID(),
+		Team1Members: []models.TeamMember{{UserID: alice}},
+		Team2Members: []models.TeamMember{{UserID: bob}},
+	}
+
+	if teamID, ok := DebateTeamOf(debate, alice); !ok || teamID != debate.Team1ID {
+		t.Errorf("expected alice to get team 1's prep room, got %s (%v)", teamID.Hex(), ok)
+	}
+	if teamID, ok := DebateTeamOf(debate, bob); !ok || teamID != debate.Team2ID {
+		t.Errorf("expected bob to get team 2's prep room, got %s (%v)", teamID.Hex(), ok)
+	}
+	if _, ok := DebateTeamOf(debate, carol); ok {
+		t.Error("expected someone outside both teams to get no prep room")
+	}
+}
+
+func TestTeamPrepRejectsInvalidInputBeforeSaving(t *testing.T) {
+	debateID, teamID := primitive.NewObjectID(), primitive.NewObjectID()
+
+	if _, err := UpdateTeamPrepNotes(debateID, teamID, primitive.NewObjectID(), 0, strings.Repeat("a", maxPrepNotesLength+1)); err == nil {
+		t.Error("expected notes over the length limit to be rejected")
+	}
+	if _, err := PinTeamArgument(debateID, teamID, models.TeamPinnedArgument{Text: "   "}); err == nil {
+		t.Error("expected an empty pinned argument to be rejected")
+	}
+	if _, err := AddTeamPrepMessage(models.TeamChatMessage{Message: strings.Repeat("a", maxPrepMessageLength+1)}); err == nil {
+		t.Error("expected a prep message over the length limit to be rejected")
+	}
+}
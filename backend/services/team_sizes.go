@@ -0,0 +1,97 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// MinTeamSize and MaxTeamSize bound the sizes a team can be set to
+	MinTeamSize = 2
+	MaxTeamSize = 4
+	// maxTeamSizeGap is how many more debaters one team may field than the other
+	maxTeamSizeGap = 1
+)
+
+// ValidTeamSize reports whether a team can be set to the given size
+func ValidTeamSize(size int) bool {
+	return size >= MinTeamSize && size <= MaxTeamSize
+}
+
+// MinimumRoster returns how many members a team of the given size needs to queue for matchmaking.
+// Teams may be one member short, but never below the smallest team size.
+func MinimumRoster(maxSize int) int {
+	if maxSize-1 < MinTeamSize {
+		return MinTeamSize
+	}
+	return maxSize - 1
+}
+
+// CompatibleTeamSizes reports whether two lineups are close enough in size to debate each other
+func CompatibleTeamSizes(size1, size2 int) bool {
+	gap := size1 - size2
+	if gap < 0 {
+		gap = -gap
+	}
+	return gap <= maxTeamSizeGap
+}
+
+// TeamFormatsFor returns the debate formats a lineup of the given size can fill. Every member must
+// get at least one speaking phase, so larger lineups need formats with more phases per side.
+func TeamFormatsFor(size int) []string {
+	formats := make([]string, 0, len(debateFormats))
+	for format := range debateFormats {
+		if formatFits(format, size) {
+			formats = append(formats, format)
+		}
+	}
+	sort.Strings(formats)
+	return formats
+}
+
+// formatFits reports whether each side of a format has a speaking phase for every member of a lineup
+func formatFits(format string, size int) bool {
+	perSide := 0
+	for _, phase := range debateFormats[format] {
+		if phase.Side == "for" {
+			perSide++
+		}
+	}
+	return size <= perSide
+}
+
+// checkTeamPairing checks two lineups can debate each other in a format
+func checkTeamPairing(format string, size1, size2 int) error {
+	if !CompatibleTeamSizes(size1, size2) {
+		return fmt.Errorf("teams of %d and %d members are too uneven to debate each other", size1, size2)
+	}
+	larger := size1
+	if size2 > larger {
+		larger = size2
+	}
+	if !formatFits(format, larger) {
+		return fmt.Errorf("the %s format does not have enough speeches for teams of %d", format, larger)
+	}
+	return nil
+}
+
+// TeamSpeakingBudgets returns the per-member speaking budget, in seconds, for each team. When one
+// team fields fewer debaters, its members get more time each so both teams have the same total.
+func TeamSpeakingBudgets(format string, stance1 string, lineup1 int, stance2 string, lineup2 int) (int, int) {
+	debateFormat := TeamDebateFormat(format)
+	budget1 := SpeakingBudget(debateFormat, stance1, lineup1)
+	budget2 := SpeakingBudget(debateFormat, stance2, lineup2)
+	if lineup1 < 1 || lineup2 < 1 {
+		return budget1, budget2
+	}
+
+	total := budget1 * lineup1
+	if other := budget2 * lineup2; other > total {
+		total = other
+	}
+	return ceilDiv(total, lineup1), ceilDiv(total, lineup2)
+}
+
+func ceilDiv(a, b int) int {
+	return (a + b - 1) / b
+}
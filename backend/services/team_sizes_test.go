@@ -0,0 +1,68 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTeamFormatsFor(t *testing.T) {
+	if got := TeamFormatsFor(2); !reflect.DeepEqual(got, []string{"lightning", "standard"}) {
+		t.Errorf("TeamFormatsFor(2) = %v", got)
+	}
+	if got := TeamFormatsFor(3); !reflect.DeepEqual(got, []string{"standard"}) {
+		t.Errorf("TeamFormatsFor(3) = %v", got)
+	}
+	if err := checkTeamPairing("lightning", 3, 2); err == nil {
+		t.Error("lightning accepted a team of 3")
+	}
+	if err := checkTeamPairing("standard", 4, 2); err == nil {
+		t.Error("accepted teams of 4 and 2")
+	}
+	if err := checkTeamPairing("standard", 3, 2); err != nil {
+		t.Errorf("rejected teams of 3 and 2: %v", err)
+	}
+}
+
+func TestTeamSpeakingBudgetsCompensateSmallerTeam(t *testing.T) {
+	budget1, budget2 := TeamSpeakingBudgets("standard", "for", 3, "against", 2)
+	if budget1 != 60 || budget2 != 90 {
+		t.Errorf("budgets = %d, %d, want 60, 90", budget1, budget2)
+	}
+	if budget1, budget2 = TeamSpeakingBudgets("standard", "for", 2, "against", 2); budget1 != budget2 {
+		t.Errorf("even teams got %d and %d", budget1, budget2)
+	}
+}
+
+func TestBestTeamMatch(t *testing.T) {
+	entry := func(roster int, elo float64, queuedAt time.Time) *TeamMatchmakingEntry {
+		return &TeamMatchmakingEntry{
+			TeamID:     primitive.NewObjectID(),
+			RosterSize: roster,
+			Formats:    TeamFormatsFor(roster),
+			AverageElo: elo,
+			Timestamp:  queuedAt,
+		}
+	}
+	now := time.Now()
+	looking := entry(3, 1200, now)
+	uneven := entry(2, 1200, now)
+	tooStrong := entry(3, 1500, now)
+	pool := map[string]*TeamMatchmakingEntry{
+		looking.TeamID.Hex():   looking,
+		uneven.TeamID.Hex():    uneven,
+		tooStrong.TeamID.Hex(): tooStrong,
+		"far":                  entry(1, 1200, now),
+	}
+	if got := bestTeamMatch(looking, pool); got != uneven {
+		t.Fatalf("expected the 2-member team, got %+v", got)
+	}
+
+	even := entry(3, 1350, now.Add(time.Minute))
+	pool[even.TeamID.Hex()] = even
+	if got := bestTeamMatch(looking, pool); got != even {
+		t.Errorf("expected the equal-size team to be preferred, got %+v", got)
+	}
+}
@@ -0,0 +1,202 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"arguehub/structs"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// teamPhaseDurations is how long each speaking phase of a team debate lasts
+var teamPhaseDurations = map[string]time.Duration{
+	"openingFor":           60 * time.Second,
+	"openingAgainst":       60 * time.Second,
+	"crossForQuestion":     30 * time.Second,
+	"crossAgainstAnswer":   30 * time.Second,
+	"crossAgainstQuestion": 30 * time.Second,
+	"crossForAnswer":       30 * time.Second,
+	"closingFor":           45 * time.Second,
+	"closingAgainst":       45 * time.Second,
+}
+
+// TeamDebateFormat returns the speaking phases of a team debate format with their durations
+func TeamDebateFormat(format string) structs.DebateFormat {
+	phases := formatPhases(format)
+	sections := make([]structs.Section, 0, len(phases))
+	for _, phase := range phases {
+		sections = append(sections, structs.Section{Name: phase.Key, Duration: teamPhaseDurations[phase.Key]})
+	}
+	return structs.DebateFormat{Sections: sections}
+}
+
+// SpeakingBudget returns how many seconds each member of a team may speak during a debate. The
+// side's total speaking time is shared evenly across the lineup, but never drops below the longest
+// phase so whoever holds the floor can always finish a full speech.
+func SpeakingBudget(format structs.DebateFormat, side string, lineupSize int) int {
+	if lineupSize < 1 {
+		lineupSize = 1
+	}
+	var total, longest time.Duration
+	for _, section := range format.Sections {
+		if !strings.EqualFold(phaseKeySide(section.Name), side) {
+			continue
+		}
+		total += section.Duration
+		if section.Duration > longest {
+			longest = section.Duration
+		}
+	}
+	share := (total + time.Duration(lineupSize)*time.Second - 1) / time.Duration(lineupSize)
+	if share < longest {
+		share = longest
+	}
+	return int(share / time.Second)
+}
+
+// SetTeamBudget gives every member of a team a speaking budget of the given number of seconds.
+// Time already spoken is kept, so a member who has used up part of their time does not get it back.
+// A budget of zero leaves the buckets unchanged.
+func (tbs *TokenBucketService) SetTeamBudget(teamID primitive.ObjectID, seconds int) {
+	if seconds <= 0 {
+		return
+	}
+	prefix := teamID.Hex() + ":"
+
+	tbs.mutex.RLock()
+	defer tbs.mutex.RUnlock()
+
+	for key, bucket := range tbs.buckets {
+		if !strings.HasPrefix(key, prefix) {
+			continue
+		}
+		bucket.Mutex.Lock()
+		bucket.Capacity = seconds
+		bucket.RefillRate = 0
+		bucket.Tokens = seconds - bucket.SpokenSeconds
+		if bucket.Tokens < 0 {
+			bucket.Tokens = 0
+		}
+		bucket.Mutex.Unlock()
+	}
+}
+
+// StartSpeaking starts the speaking clock for a member. It returns false when the member has no
+// time left.
+func (tbs *TokenBucketService) StartSpeaking(teamID, userID primitive.ObjectID, now time.Time) bool {
+	bucket := tbs.bucket(teamID, userID)
+	if bucket == nil {
+		return false
+	}
+
+	bucket.Mutex.Lock()
+	defer bucket.Mutex.Unlock()
+
+	tbs.refillTokens(bucket)
+	if bucket.Tokens <= 0 {
+		return false
+	}
+	if bucket.SpeakingSince.IsZero() {
+		bucket.SpeakingSince = now
+	}
+	return true
+}
+
+// ChargeSpeaking charges a speaking member for every whole second since the clock was last charged
+// and returns the seconds they have left. Members who are not speaking are not charged.
+func (tbs *TokenBucketService) ChargeSpeaking(teamID, userID primitive.ObjectID, now time.Time) int {
+	bucket := tbs.bucket(teamID, userID)
+	if bucket == nil {
+		return 0
+	}
+
+	bucket.Mutex.Lock()
+	defer bucket.Mutex.Unlock()
+
+	tbs.refillTokens(bucket)
+	if !bucket.SpeakingSince.IsZero() {
+		seconds := int(now.Sub(bucket.SpeakingSince) / time.Second)
+		chargeSpeakingSeconds(bucket, seconds)
+		bucket.SpeakingSince = bucket.SpeakingSince.Add(time.Duration(seconds) * time.Second)
+	}
+	return bucket.Tokens
+}
+
+// StopSpeaking stops a member's speaking clock, charging the time since it was last charged
+// rounded to the nearest second, and returns the seconds they have left
+func (tbs *TokenBucketService) StopSpeaking(teamID, userID primitive.ObjectID, now time.Time) int {
+	bucket := tbs.bucket(teamID, userID)
+	if bucket == nil {
+		return 0
+	}
+
+	bucket.Mutex.Lock()
+	defer bucket.Mutex.Unlock()
+
+	if !bucket.SpeakingSince.IsZero() {
+		elapsed := now.Sub(bucket.SpeakingSince) + time.Second/2
+		chargeSpeakingSeconds(bucket, int(elapsed/time.Second))
+		bucket.SpeakingSince = time.Time{}
+	}
+	return bucket.Tokens
+}
+
+// GetSpokenSeconds returns how many seconds a member has spoken so far
+func (tbs *TokenBucketService) GetSpokenSeconds(teamID, userID primitive.ObjectID) int {
+	bucket := tbs.bucket(teamID, userID)
+	if bucket == nil {
+		return 0
+	}
+
+	bucket.Mutex.Lock()
+	defer bucket.Mutex.Unlock()
+	return bucket.SpokenSeconds
+}
+
+// GetCapacity returns a member's speaking budget in seconds
+func (tbs *TokenBucketService) GetCapacity(teamID, userID primitive.ObjectID) int {
+	bucket := tbs.bucket(teamID, userID)
+	if bucket == nil {
+		return 0
+	}
+
+	bucket.Mutex.Lock()
+	defer bucket.Mutex.Unlock()
+	return bucket.Capacity
+}
+
+// SpeakingTimes returns the seconds spoken by every member who has spoken, keyed by user ID (hex)
+func (tbs *TokenBucketService) SpeakingTimes() map[string]int {
+	tbs.mutex.RLock()
+	defer tbs.mutex.RUnlock()
+
+	times := make(map[string]int)
+	for key, bucket := range tbs.buckets {
+		bucket.Mutex.Lock()
+		spoken := bucket.SpokenSeconds
+		bucket.Mutex.Unlock()
+		if spoken > 0 {
+			times[key[strings.Index(key, ":")+1:]] = spoken
+		}
+	}
+	return times
+}
+
+func (tbs *TokenBucketService) bucket(teamID, userID primitive.ObjectID) *TokenBucket {
+	tbs.mutex.RLock()
+	defer tbs.mutex.RUnlock()
+	return tbs.buckets[tbs.getBucketKey(teamID, userID)]
+}
+
+// chargeSpeakingSeconds records spoken time against a bucket, which must be locked
+func chargeSpeakingSeconds(bucket *TokenBucket, seconds int) {
+	if seconds <= 0 {
+		return
+	}
+	bucket.SpokenSeconds += seconds
+	bucket.Tokens -= seconds
+	if bucket.Tokens < 0 {
+		bucket.Tokens = 0
+	}
+}
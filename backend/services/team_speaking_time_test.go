@@ -0,0 +1,63 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSpeakingBudget(t *testing.T) {
+	standard := TeamDebateFormat("standard")
+	tests := []struct {
+		side   string
+		lineup int
+		want   int
+	}{
+		{"for", 1, 165},
+		{"for", 2, 83},
+		{"against", 4, 60}, // never below the longest phase
+	}
+	for _, tt := range tests {
+		if got := SpeakingBudget(standard, tt.side, tt.lineup); got != tt.want {
+			t.Errorf("SpeakingBudget(standard, %q, %d) = %d, want %d", tt.side, tt.lineup, got, tt.want)
+		}
+	}
+	if got := SpeakingBudget(TeamDebateFormat("lightning"), "for", 1); got != 105 {
+		t.Errorf("SpeakingBudget(lightning, for, 1) = %d, want 105", got)
+	}
+}
+
+func TestTokenBucketSpeakingClock(t *testing.T) {
+	tbs := NewTokenBucketService()
+	teamID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	tbs.InitializeMemberBuckets(teamID, []models.TeamMember{{UserID: userID}})
+	tbs.SetTeamBudget(teamID, 5)
+
+	start := time.Now()
+	if !tbs.StartSpeaking(teamID, userID, start) {
+		t.Fatal("expected a member with time left to start speaking")
+	}
+	if remaining := tbs.ChargeSpeaking(teamID, userID, start.Add(2500*time.Millisecond)); remaining != 3 {
+		t.Errorf("remaining after 2.5s = %d, want 3", remaining)
+	}
+	if remaining := tbs.StopSpeaking(teamID, userID, start.Add(3*time.Second)); remaining != 2 {
+		t.Errorf("remaining after stopping at 3s = %d, want 2", remaining)
+	}
+	if remaining := tbs.ChargeSpeaking(teamID, userID, start.Add(10*time.Second)); remaining != 2 {
+		t.Errorf("a silent member was charged: remaining = %d, want 2", remaining)
+	}
+
+	tbs.StartSpeaking(teamID, userID, start)
+	if remaining := tbs.ChargeSpeaking(teamID, userID, start.Add(time.Minute)); remaining != 0 {
+		t.Errorf("remaining after overrunning = %d, want 0", remaining)
+	}
+	if tbs.StartSpeaking(teamID, userID, start) {
+		t.Error("expected a member with no time left to be refused")
+	}
+	if spoken := tbs.GetSpokenSeconds(teamID, userID); spoken != 63 {
+		t.Errorf("spoken seconds = %d, want 63", spoken)
+	}
+}
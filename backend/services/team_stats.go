@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"arguehub/db"
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	teamStatsTopTopics   = 10
+	teamStatsTopSpeakers = 5
+)
+
+// GetTeamStats computes a team's statistics from its finished debates
+func GetTeamStats(teamID primitive.ObjectID) (*models.TeamStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var team models.Team
+	if err := db.GetCollection("teams").FindOne(ctx, bson.M{"_id": teamID}).Decode(&team); err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"status": "finished",
+		"$or":    []bson.M{{"team1Id": teamID}, {"team2Id": teamID}},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "finishedAt", Value: 1}})
+	cursor, err := db.GetCollection("team_debates").Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var debates []models.TeamDebate
+	if err := cursor.All(ctx, &debates); err != nil {
+		return nil, err
+	}
+
+	stats := buildTeamStats(teamID, debates)
+	stats.TeamName = team.Name
+	return stats, nil
+}
+
+// teamDebateSide is one team's view of a finished debate
+type teamDebateSide struct {
+	stance       string
+	members      []models.TeamMember
+	eloBefore    float64
+	eloAfter     float64
+	opponentID   primitive.ObjectID
+	opponentName string
+}
+
+func sideOf(debate models.TeamDebate, teamID primitive.ObjectID) teamDebateSide {
+	if debate.Team1ID == teamID {
+		return teamDebateSide{debate.Team1Stance, debate.Team1Members, debate.Team1Elo, debate.Team1EloAfter, debate.Team2ID, debate.Team2Name}
+	}
+	return teamDebateSide{debate.Team2Stance, debate.Team2Members, debate.Team2Elo, debate.Team2EloAfter, debate.Team1ID, debate.Team1Name}
+}
+
+// buildTeamStats aggregates finished debates, oldest first, into a team's statistics
+func buildTeamStats(teamID primitive.ObjectID, debates []models.TeamDebate) *models.TeamStats {
+	stats := &models.TeamStats{
+		TeamID:        teamID.Hex(),
+		ByStance:      make(map[string]models.TeamRecord),
+		ByTopic:       []models.TeamTopicRecord{},
+		RatingHistory: []models.TeamRatingPoint{},
+		BestSpeakers:  []models.TeamSpeakerSummary{},
+		HeadToHead:    []models.TeamHeadToHead{},
+	}
+
+	topics := make(map[string]*models.TeamTopicRecord)
+	var topicOrder []string
+	opponents := make(map[primitive.ObjectID]*models.TeamHeadToHead)
+	var opponentOrder []primitive.ObjectID
+	speakers := make(map[primitive.ObjectID]*models.TeamSpeakerSummary)
+	var balanceTotal float64
+	var balanced int
+
+	for _, debate := range debates {
+		side := sideOf(debate, teamID)
+		result := teamResultFor(&debate, teamID)
+
+		addTeamResult(&stats.Record, result)
+
+		stance := strings.ToLower(side.stance)
+		stanceRecord := stats.ByStance[stance]
+		addTeamResult(&stanceRecord, result)
+		stats.ByStance[stance] = stanceRecord
+
+		topicKey := strings.ToLower(strings.TrimSpace(debate.Topic))
+		if _, ok := topics[topicKey]; !ok {
+			topics[topicKey] = &models.TeamTopicRecord{Topic: strings.TrimSpace(debate.Topic)}
+			topicOrder = append(topicOrder, topicKey)
+		}
+		addTeamResult(&topics[topicKey].TeamRecord, result)
+
+		// Bot teams are one-off personalities, so debates against them have no rivalry or rating
+		if debate.Mode != models.TeamDebateModeVsBots {
+			if _, ok := opponents[side.opponentID]; !ok {
+				opponents[side.opponentID] = &models.TeamHeadToHead{OpponentID: side.opponentID.Hex()}
+				opponentOrder = append(opponentOrder, side.opponentID)
+			}
+			opponents[side.opponentID].OpponentName = side.opponentName
+			addTeamResult(&opponents[side.opponentID].TeamRecord, result)
+
+			if side.eloAfter > 0 {
+				point := models.TeamRatingPoint{DebateID: debate.ID.Hex(), Before: side.eloBefore, After: side.eloAfter}
+				if debate.FinishedAt != nil {
+					point.At = *debate.FinishedAt
+				}
+				stats.RatingHistory = append(stats.RatingHistory, point)
+			}
+		}
+
+		for _, speaker := range debate.SpeakerResults {
+			if speaker.TeamID != teamID || speaker.IsBot {
+				continue
+			}
+			summary, ok := speakers[speaker.UserID]
+			if !ok {
+				summary = &models.TeamSpeakerSummary{UserID: speaker.UserID.Hex()}
+				speakers[speaker.UserID] = summary
+			}
+			summary.DisplayName = speaker.DisplayName
+			summary.Debates++
+			summary.TotalPoints += speaker.Points
+			if speaker.Points > summary.BestPoints {
+				summary.BestPoints = speaker.Points
+			}
+		}
+
+		if balance, ok := speakingBalance(debate, teamID, side.members); ok {
+			balanceTotal += balance
+			balanced++
+		}
+	}
+
+	for _, key := range topicOrder {
+		stats.ByTopic = append(stats.ByTopic, *topics[key])
+	}
+	sort.SliceStable(stats.ByTopic, func(i, j int) bool { return stats.ByTopic[i].Played > stats.ByTopic[j].Played })
+	if len(stats.ByTopic) > teamStatsTopTopics {
+		stats.ByTopic = stats.ByTopic[:teamStatsTopTopics]
+	}
+
+	for _, id := range opponentOrder {
+		stats.HeadToHead = append(stats.HeadToHead, *opponents[id])
+	}
+	sort.SliceStable(stats.HeadToHead, func(i, j int) bool { return stats.HeadToHead[i].Played > stats.HeadToHead[j].Played })
+
+	for _, summary := range speakers {
+		summary.AveragePoints = float64(summary.TotalPoints) / float64(summary.Debates)
+		stats.BestSpeakers = append(stats.BestSpeakers, *summary)
+	}
+	sort.Slice(stats.BestSpeakers, func(i, j int) bool {
+		a, b := stats.BestSpeakers[i], stats.BestSpeakers[j]
+		if a.AveragePoints != b.AveragePoints {
+			return a.AveragePoints > b.AveragePoints
+		}
+		if a.BestPoints != b.BestPoints {
+			return a.BestPoints > b.BestPoints
+		}
+		return a.UserID < b.UserID
+	})
+	if len(stats.BestSpeakers) > teamStatsTopSpeakers {
+		stats.BestSpeakers = stats.BestSpeakers[:teamStatsTopSpeakers]
+	}
+
+	if balanced > 0 {
+		stats.AverageSpeakingBalance = balanceTotal / float64(balanced)
+	}
+	return stats
+}
+
+// addTeamResult counts a "win", "loss" or "draw" in a record
+func addTeamResult(record *models.TeamRecord, result string) {
+	record.Played++
+	switch result {
+	case "win":
+		record.Wins++
+	case "loss":
+		record.Losses++
+	default:
+		record.Draws++
+	}
+	record.WinRate = float64(record.Wins) / float64(record.Played)
+}
+
+// speakingBalance compares the least and most any member of a team spoke in a debate, using the
+// recorded speaking time or, for debates without it, the words in each member's speeches. It
+// returns false when the team has a single member or nobody spoke.
+func speakingBalance(debate models.TeamDebate, teamID primitive.ObjectID, members []models.TeamMember) (float64, bool) {
+	if len(members) < 2 {
+		return 0, false
+	}
+
+	amounts := make([]int, 0, len(members))
+	if len(debate.SpeakingTime) > 0 {
+		for _, member := range members {
+			amounts = append(amounts, debate.SpeakingTime[member.UserID.Hex()])
+		}
+	} else {
+		words := make(map[primitive.ObjectID]int)
+		for _, speaker := range debate.SpeakerResults {
+			if speaker.TeamID == teamID {
+				words[speaker.UserID] = speaker.Words
+			}
+		}
+		for _, member := range members {
+			amounts = append(amounts, words[member.UserID])
+		}
+	}
+
+	least, most := amounts[0], amounts[0]
+	for _, amount := range amounts[1:] {
+		if amount < least {
+			least = amount
+		}
+		if amount > most {
+			most = amount
+		}
+	}
+	if most == 0 {
+		return 0, false
+	}
+	return float64(least) / float64(most), true
+}
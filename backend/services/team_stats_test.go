@@ -0,0 +1,65 @@
+package services
+
+import (
+	"testing"
+
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBuildTeamStats(t *testing.T) {
+	teamID, rivalID := primitive.NewObjectID(), primitive.NewObjectID()
+	a, b := primitive.NewObjectID(), primitive.NewObjectID()
+	members := []models.TeamMember{{UserID: a}, {UserID: b}}
+
+	debates := []models.TeamDebate{
+		{
+			ID: primitive.NewObjectID(), Team1ID: teamID, Team2ID: rivalID, Team2Name: "Rivals",
+			Team1Members: members, Team1Stance: "for", Team2Stance: "against", Topic: "AI in schools",
+			Winner: "team1", Team1Elo: 1200, Team1EloAfter: 1216,
+			SpeakingTime: map[string]int{a.Hex(): 60, b.Hex(): 30},
+			SpeakerResults: []models.TeamSpeakerResult{
+				{TeamID: teamID, UserID: a, DisplayName: "A", Points: 24},
+				{TeamID: teamID, UserID: b, DisplayName: "B", Points: 18},
+			},
+		},
+		{
+			ID: primitive.NewObjectID(), Team1ID: rivalID, Team2ID: teamID, Team1Name: "Rivals",
+			Team2Members: members, Team1Stance: "for", Team2Stance: "against", Topic: "ai in schools ",
+			Winner: "team1", Team2Elo: 1216, Team2EloAfter: 1201,
+			SpeakerResults: []models.TeamSpeakerResult{
+				{TeamID: teamID, UserID: a, DisplayName: "A", Points: 20, Words: 100},
+				{TeamID: teamID, UserID: b, DisplayName: "B", Points: 26, Words: 100},
+			},
+		},
+		{
+			ID: primitive.NewObjectID(), Team1ID: teamID, Team2ID: primitive.NewObjectID(), Mode: models.TeamDebateModeVsBots,
+			Team1Members: members, Team1Stance: "against", Topic: "Space travel", Winner: "draw",
+		},
+	}
+
+	stats := buildTeamStats(teamID, debates)
+
+	if r := stats.Record; r.Played != 3 || r.Wins != 1 || r.Losses != 1 || r.Draws != 1 {
+		t.Errorf("record = %+v, want 1-1-1 over 3 debates", r)
+	}
+	if r := stats.ByStance["against"]; r.Played != 2 || r.Losses != 1 || r.Draws != 1 {
+		t.Errorf("against record = %+v, want a loss and a draw", r)
+	}
+	if len(stats.ByTopic) != 2 || stats.ByTopic[0].Topic != "AI in schools" || stats.ByTopic[0].Played != 2 {
+		t.Errorf("topics = %+v, want the two AI debates grouped first", stats.ByTopic)
+	}
+	if len(stats.HeadToHead) != 1 || stats.HeadToHead[0].OpponentName != "Rivals" || stats.HeadToHead[0].Played != 2 {
+		t.Errorf("head to head = %+v, want two debates against Rivals and none against bots", stats.HeadToHead)
+	}
+	if len(stats.RatingHistory) != 2 || stats.RatingHistory[1].After != 1201 {
+		t.Errorf("rating history = %+v, want the two rated debates", stats.RatingHistory)
+	}
+	if len(stats.BestSpeakers) != 2 || stats.BestSpeakers[0].UserID != b.Hex() || stats.BestSpeakers[0].BestPoints != 26 {
+		t.Errorf("best speakers = %+v, want B first on the tie-break by best points", stats.BestSpeakers)
+	}
+	if stats.AverageSpeakingBalance != 0.75 {
+		t.Errorf("speaking balance = %v, want 0.75", stats.AverageSpeakingBalance)
+	}
+}
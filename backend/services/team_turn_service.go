@@ -26,11 +26,13 @@ type TokenBucketService struct {
 
 // TokenBucket represents a token bucket for a team member
 type TokenBucket struct {
-	Capacity   int          // Maximum tokens
-	Tokens     int          // Current tokens
-	RefillRate int          // Tokens per second
-	LastRefill time.Time    // Last time tokens were refilled
-	Mutex      sync.RWMutex // Mutex for thread safety
+	Capacity      int          // Maximum tokens
+	Tokens        int          // Current tokens
+	RefillRate    int          // Tokens per second
+	LastRefill    time.Time    // Last time tokens were refilled
+	SpeakingSince time.Time    // When the speaking clock was last charged; zero while silent
+	SpokenSeconds int          // Seconds spoken so far
+	Mutex         sync.RWMutex // Mutex for thread safety
 }
 
 // NewTokenBucketService creates a new token bucket service
@@ -52,6 +54,13 @@ func (tbs *TokenBucketService) InitializeTeamBuckets(teamID primitive.ObjectID)
 		return err
 	}
 
+	tbs.InitializeMemberBuckets(teamID, team.Members)
+	return nil
+}
+
+// InitializeMemberBuckets initializes token buckets for the given members, for teams that are
+// not stored in the teams collection (such as bot teams)
+func (tbs *TokenBucketService) InitializeMemberBuckets(teamID primitive.ObjectID, members []models.TeamMember) {
 	tbs.mutex.Lock()
 	defer tbs.mutex.Unlock()
 
@@ -60,7 +69,7 @@ func (tbs *TokenBucketService) InitializeTeamBuckets(teamID primitive.ObjectID)
 	}
 
 	// Initialize bucket for each team member
-	for _, member := range team.Members {
+	for _, member := range members {
 		bucketKey := tbs.getBucketKey(teamID, member.UserID)
 		if existing, ok := tbs.buckets[bucketKey]; ok {
 			existing.Mutex.Lock()
@@ -80,8 +89,6 @@ func (tbs *TokenBucketService) InitializeTeamBuckets(teamID primitive.ObjectID)
 			LastRefill: time.Now(),
 		}
 	}
-
-	return nil
 }
 
 // ConsumeToken attempts to consume a token from a team member's bucket
@@ -153,16 +160,68 @@ func (tbs *TokenBucketService) getBucketKey(teamID, userID primitive.ObjectID) s
 
 // GetTeamTurnManager manages turns within a team
 type TeamTurnManager struct {
-	currentTurn map[string]primitive.ObjectID   // teamID -> current userID
-	turnOrder   map[string][]primitive.ObjectID // teamID -> ordered list of userIDs
-	mutex       sync.RWMutex
+	currentTurn   map[string]primitive.ObjectID                         // teamID -> current userID
+	turnOrder     map[string][]primitive.ObjectID                       // teamID -> ordered list of userIDs
+	lineup        map[string][]primitive.ObjectID                       // teamID -> members taking part in the debate
+	phaseOrders   map[string]map[string][]primitive.ObjectID            // teamID -> phase -> speaking order set by the captain
+	contributions map[string]map[primitive.ObjectID]*MemberContribution // teamID -> userID -> contribution
+	mutex         sync.RWMutex
+}
+
+// MemberContribution is everything a team member has said during a debate
+type MemberContribution struct {
+	Speeches []string
+	Phases   map[string]int // phase -> number of speeches given in it
+	Words    int
 }
 
 // NewTeamTurnManager creates a new team turn manager
 func NewTeamTurnManager() *TeamTurnManager {
 	return &TeamTurnManager{
-		currentTurn: make(map[string]primitive.ObjectID),
-		turnOrder:   make(map[string][]primitive.ObjectID),
+		currentTurn:   make(map[string]primitive.ObjectID),
+		turnOrder:     make(map[string][]primitive.ObjectID),
+		lineup:        make(map[string][]primitive.ObjectID),
+		phaseOrders:   make(map[string]map[string][]primitive.ObjectID),
+		contributions: make(map[string]map[primitive.ObjectID]*MemberContribution),
+	}
+}
+
+// RecordContribution records a speech or debate message given by a team member
+func (ttm *TeamTurnManager) RecordContribution(teamID, userID primitive.ObjectID, phase, text string) {
+	ttm.mutex.Lock()
+	defer ttm.mutex.Unlock()
+
+	teamIDStr := teamID.Hex()
+	if ttm.contributions[teamIDStr] == nil {
+		ttm.contributions[teamIDStr] = make(map[primitive.ObjectID]*MemberContribution)
+	}
+	contribution, exists := ttm.contributions[teamIDStr][userID]
+	if !exists {
+		contribution = &MemberContribution{Phases: make(map[string]int)}
+		ttm.contributions[teamIDStr][userID] = contribution
+	}
+	contribution.Speeches = append(contribution.Speeches, text)
+	contribution.Phases[phase]++
+	contribution.Words += countWords(text)
+}
+
+// GetContribution returns a copy of what a team member has contributed so far
+func (ttm *TeamTurnManager) GetContribution(teamID, userID primitive.ObjectID) MemberContribution {
+	ttm.mutex.RLock()
+	defer ttm.mutex.RUnlock()
+
+	contribution, exists := ttm.contributions[teamID.Hex()][userID]
+	if !exists {
+		return MemberContribution{Phases: map[string]int{}}
+	}
+	phases := make(map[string]int, len(contribution.Phases))
+	for phase, count := range contribution.Phases {
+		phases[phase] = count
+	}
+	return MemberContribution{
+		Speeches: append([]string(nil), contribution.Speeches...),
+		Phases:   phases,
+		Words:    contribution.Words,
 	}
 }
 
@@ -178,12 +237,18 @@ func (ttm *TeamTurnManager) InitializeTeamTurns(teamID primitive.ObjectID) error
 		return err
 	}
 
+	return ttm.InitializeTurnOrder(teamID, team.Members)
+}
+
+// InitializeTurnOrder sets the turn order for a team from the given members, for teams that are
+// not stored in the teams collection (such as bot teams)
+func (ttm *TeamTurnManager) InitializeTurnOrder(teamID primitive.ObjectID, members []models.TeamMember) error {
 	ttm.mutex.Lock()
 	defer ttm.mutex.Unlock()
 
 	// Create ordered list of team members
 	var userIDs []primitive.ObjectID
-	for _, member := range team.Members {
+	for _, member := range members {
 		userIDs = append(userIDs, member.UserID)
 	}
 
@@ -193,11 +258,118 @@ func (ttm *TeamTurnManager) InitializeTeamTurns(teamID primitive.ObjectID) error
 
 	teamIDStr := teamID.Hex()
 	ttm.turnOrder[teamIDStr] = userIDs
+	ttm.lineup[teamIDStr] = append([]primitive.ObjectID(nil), userIDs...)
 	ttm.currentTurn[teamIDStr] = userIDs[0] // Start with first member
 
 	return nil
 }
 
+// GetLineup returns the members taking part in the debate for a team
+func (ttm *TeamTurnManager) GetLineup(teamID primitive.ObjectID) []primitive.ObjectID {
+	ttm.mutex.RLock()
+	defer ttm.mutex.RUnlock()
+	return append([]primitive.ObjectID(nil), ttm.lineup[teamID.Hex()]...)
+}
+
+// SetPhaseOrder sets who speaks, and in which order, during one phase. Every speaker must be in
+// the team's lineup.
+func (ttm *TeamTurnManager) SetPhaseOrder(teamID primitive.ObjectID, phase string, order []primitive.ObjectID) error {
+	ttm.mutex.Lock()
+	defer ttm.mutex.Unlock()
+
+	teamIDStr := teamID.Hex()
+	if len(order) == 0 {
+		return fmt.Errorf("speaking order cannot be empty")
+	}
+	seen := make(map[primitive.ObjectID]bool, len(order))
+	for _, userID := range order {
+		if seen[userID] {
+			return fmt.Errorf("member %s appears more than once", userID.Hex())
+		}
+		seen[userID] = true
+		if indexOfUser(ttm.lineup[teamIDStr], userID) == -1 {
+			return fmt.Errorf("member %s is not in the lineup", userID.Hex())
+		}
+	}
+
+	if ttm.phaseOrders[teamIDStr] == nil {
+		ttm.phaseOrders[teamIDStr] = make(map[string][]primitive.ObjectID)
+	}
+	ttm.phaseOrders[teamIDStr][phase] = append([]primitive.ObjectID(nil), order...)
+	return nil
+}
+
+// StartPhase switches a team to the speaking order set for the phase and returns the member who
+// speaks first. Without an order for the phase, the lineup keeps rotating from the current speaker.
+func (ttm *TeamTurnManager) StartPhase(teamID primitive.ObjectID, phase string) primitive.ObjectID {
+	ttm.mutex.Lock()
+	defer ttm.mutex.Unlock()
+
+	teamIDStr := teamID.Hex()
+	if order, exists := ttm.phaseOrders[teamIDStr][phase]; exists {
+		ttm.turnOrder[teamIDStr] = append([]primitive.ObjectID(nil), order...)
+		ttm.currentTurn[teamIDStr] = order[0]
+		return order[0]
+	}
+
+	lineup := ttm.lineup[teamIDStr]
+	if len(lineup) == 0 {
+		return ttm.currentTurn[teamIDStr]
+	}
+	ttm.turnOrder[teamIDStr] = append([]primitive.ObjectID(nil), lineup...)
+	if indexOfUser(lineup, ttm.currentTurn[teamIDStr]) == -1 {
+		ttm.currentTurn[teamIDStr] = lineup[0]
+	}
+	return ttm.currentTurn[teamIDStr]
+}
+
+// Substitute hands every turn of one member to another, in the lineup and in every speaking order
+func (ttm *TeamTurnManager) Substitute(teamID, outUserID, inUserID primitive.ObjectID) error {
+	ttm.mutex.Lock()
+	defer ttm.mutex.Unlock()
+
+	teamIDStr := teamID.Hex()
+	if outUserID == inUserID {
+		return fmt.Errorf("a member cannot substitute for themselves")
+	}
+	if indexOfUser(ttm.lineup[teamIDStr], outUserID) == -1 {
+		return fmt.Errorf("member %s is not in the lineup", outUserID.Hex())
+	}
+
+	ttm.lineup[teamIDStr] = substituteUser(ttm.lineup[teamIDStr], outUserID, inUserID)
+	ttm.turnOrder[teamIDStr] = substituteUser(ttm.turnOrder[teamIDStr], outUserID, inUserID)
+	for phase, order := range ttm.phaseOrders[teamIDStr] {
+		ttm.phaseOrders[teamIDStr][phase] = substituteUser(order, outUserID, inUserID)
+	}
+	if ttm.currentTurn[teamIDStr] == outUserID {
+		ttm.currentTurn[teamIDStr] = inUserID
+	}
+	return nil
+}
+
+// substituteUser replaces outUserID with inUserID, or just drops outUserID if inUserID is already listed
+func substituteUser(order []primitive.ObjectID, outUserID, inUserID primitive.ObjectID) []primitive.ObjectID {
+	index := indexOfUser(order, outUserID)
+	if index == -1 {
+		return order
+	}
+	updated := append([]primitive.ObjectID(nil), order...)
+	if indexOfUser(order, inUserID) != -1 {
+		return append(updated[:index], updated[index+1:]...)
+	}
+	updated[index] = inUserID
+	return updated
+}
+
+func indexOfUser(order []primitive.ObjectID, userID primitive.ObjectID) int {
+	for i, id := range order {
+		if id == userID {
+			return i
+		}
+	}
+	return -1
+}
+
 // GetCurrentTurn returns the current team member whose turn it is
 func (ttm *TeamTurnManager) GetCurrentTurn(teamID primitive.ObjectID) primitive.ObjectID {
 	ttm.mutex.RLock()
@@ -211,6 +383,20 @@ func (ttm *TeamTurnManager) GetCurrentTurn(teamID primitive.ObjectID) primitive.
 	return primitive.NilObjectID
 }
 
+// SetCurrentTurn gives the turn to a member of the team's lineup, such as when a room is restored
+// from the stored debate state. It returns false if the member is not in the lineup.
+func (ttm *TeamTurnManager) SetCurrentTurn(teamID, userID primitive.ObjectID) bool {
+	ttm.mutex.Lock()
+	defer ttm.mutex.Unlock()
+
+	teamIDStr := teamID.Hex()
+	if indexOfUser(ttm.lineup[teamIDStr], userID) == -1 {
+		return false
+	}
+	ttm.currentTurn[teamIDStr] = userID
+	return true
+}
+
 // NextTurn advances to the next team member's turn
 func (ttm *TeamTurnManager) NextTurn(teamID primitive.ObjectID) primitive.ObjectID {
 	ttm.mutex.Lock()
@@ -289,12 +475,18 @@ func (tbs *TokenBucketService) GetTeamSpeakingStatus(teamID primitive.ObjectID,
 		remainingTokens := tbs.GetRemainingTokens(teamID, member.UserID)
 		isCurrentTurn := member.UserID == currentTurn
 
+		contribution := ttm.GetContribution(teamID, member.UserID)
+
 		status[member.UserID.Hex()] = map[string]interface{}{
 			"userId":          member.UserID,
 			"displayName":     member.DisplayName,
 			"remainingTokens": remainingTokens,
+			"spokenSeconds":   tbs.GetSpokenSeconds(teamID, member.UserID),
+			"speakingBudget":  tbs.GetCapacity(teamID, member.UserID),
 			"isCurrentTurn":   isCurrentTurn,
 			"canSpeak":        remainingTokens > 0 && isCurrentTurn,
+			"speeches":        len(contribution.Speeches),
+			"words":           contribution.Words,
 		}
 	}
 
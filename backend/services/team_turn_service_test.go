@@ -0,0 +1,58 @@
+package services
+
+import (
+	"testing"
+
+	"arguehub/models"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTeamTurnManagerCaptainControls(t *testing.T) {
+	turnManager := NewTeamTurnManager()
+	teamID := primitive.NewObjectID()
+	a, b, c := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	members := []models.TeamMember{{UserID: a}, {UserID: b}, {UserID: c}}
+	if err := turnManager.InitializeTurnOrder(teamID, members); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := turnManager.SetPhaseOrder(teamID, "openingFor", []primitive.ObjectID{b, b}); err == nil {
+		t.Error("expected a duplicate speaker to be rejected")
+	}
+	if err := turnManager.SetPhaseOrder(teamID, "openingFor", []primitive.ObjectID{primitive.NewObjectID()}); err == nil {
+		t.Error("expected a speaker outside the lineup to be rejected")
+	}
+	if err := turnManager.SetPhaseOrder(teamID, "openingFor", []primitive.ObjectID{c, a}); err != nil {
+		t.Fatal(err)
+	}
+
+	if first := turnManager.StartPhase(teamID, "openingFor"); first != c {
+		t.Errorf("expected the captain's first speaker, got %s", first.Hex())
+	}
+	if next := turnManager.NextTurn(teamID); next != a {
+		t.Errorf("expected the captain's second speaker, got %s", next.Hex())
+	}
+	if next := turnManager.NextTurn(teamID); next != c {
+		t.Errorf("expected the order to wrap around without b, got %s", next.Hex())
+	}
+
+	// Without an order the lineup keeps rotating from the current speaker
+	if first := turnManager.StartPhase(teamID, "closingFor"); first != c {
+		t.Errorf("expected rotation to continue from the current speaker, got %s", first.Hex())
+	}
+
+	// c drops out and b, already in the lineup, takes their turns
+	if err := turnManager.Substitute(teamID, c, b); err != nil {
+		t.Fatal(err)
+	}
+	if current := turnManager.GetCurrentTurn(teamID); current != b {
+		t.Errorf("expected the substitute to take the current turn, got %s", current.Hex())
+	}
+	if first := turnManager.StartPhase(teamID, "openingFor"); first != b {
+		t.Errorf("expected the substitute in the captain's order, got %s", first.Hex())
+	}
+	if lineup := turnManager.GetLineup(teamID); len(lineup) != 2 {
+		t.Errorf("expected the substituted member to leave the lineup, got %v", lineup)
+	}
+}
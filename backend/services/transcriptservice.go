@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -89,9 +90,10 @@ func SubmitTranscripts(
 
 		// Store the result
 		resultDoc := models.DebateResult{
-			RoomID:    roomID,
-			Result:    result,
-			CreatedAt: time.Now(),
+			RoomID:         roomID,
+			Result:         result,
+			AudienceResult: AudienceResultFor(roomID),
+			CreatedAt:      time.Now(),
 		}
 		_, err = resultCollection.InsertOne(ctx, resultDoc)
 		if err != nil {
@@ -177,7 +179,8 @@ func SubmitTranscripts(
 				topic := resolveDebateTopic(ctx, roomID, forSubmission, againstSubmission)
 
 				// Save transcript for "for" user
-				err = SaveDebateTranscript(
+				err = saveDebateTranscript(
+					roomID,
 					forUser.ID,
 					forUser.Email,
 					"user_vs_user",
@@ -191,7 +194,8 @@ func SubmitTranscripts(
 				}
 
 				// Save transcript for "against" user
-				err = SaveDebateTranscript(
+				err = saveDebateTranscript(
+					roomID,
 					againstUser.ID,
 					againstUser.Email,
 					"user_vs_user",
@@ -660,6 +664,12 @@ func buildFallbackJudgeResult(merged map[string]string) string {
 
 // SaveDebateTranscript saves a debate transcript for later viewing
 func SaveDebateTranscript(userID primitive.ObjectID, email, debateType, topic, opponent, result string, messages []models.Message, transcripts map[string]string) error {
+	return saveDebateTranscript("", userID, email, debateType, topic, opponent, result, messages, transcripts)
+}
+
+// saveDebateTranscript saves a transcript linked to the room or debate it comes from, so results
+// decided later, like the audience's verdict, can be added to it
+func saveDebateTranscript(debateID string, userID primitive.ObjectID, email, debateType, topic, opponent, result string, messages []models.Message, transcripts map[string]string) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -680,16 +690,23 @@ func SaveDebateTranscript(userID primitive.ObjectID, email, debateType, topic, o
 	if err == nil {
 		// Transcript already exists, check if we need to update it
 
-		// If the result has changed or is "pending", update the transcript
-		if existingTranscript.Result != result || existingTranscript.Result == "pending" {
-			update := bson.M{
-				"$set": bson.M{
-					"result":      result,
-					"messages":    messages,
-					"transcripts": transcripts,
-					"updatedAt":   time.Now(),
-				},
+		// If the result has changed or is "pending", or the transcript isn't linked to its debate
+		// yet, update the transcript
+		if existingTranscript.Result != result || existingTranscript.Result == "pending" ||
+			(debateID != "" && existingTranscript.DebateID != debateID) {
+			fields := bson.M{
+				"result":      result,
+				"messages":    messages,
+				"transcripts": transcripts,
+				"updatedAt":   time.Now(),
 			}
+			if debateID != "" {
+				fields["debateId"] = debateID
+				if audienceResult := AudienceResultFor(debateID); audienceResult != nil {
+					fields["audienceResult"] = audienceResult
+				}
+			}
+			update := bson.M{"$set": fields}
 
 			_, err = collection.UpdateOne(ctx, bson.M{"_id": existingTranscript.ID}, update)
 			if err != nil {
@@ -713,9 +730,13 @@ func SaveDebateTranscript(userID primitive.ObjectID, email, debateType, topic, o
 		Result:      result,
 		Messages:    messages,
 		Transcripts: transcripts,
+		DebateID:    debateID,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
+	if debateID != "" {
+		savedTranscript.AudienceResult = AudienceResultFor(debateID)
+	}
 
 	_, err = collection.InsertOne(ctx, savedTranscript)
 	if err != nil {
@@ -977,12 +998,31 @@ func GetDebateStats(userID primitive.ObjectID) (map[string]interface{}, error) {
 		winRate = float64(wins) / float64(totalDebates) * 100
 	}
 
-	return map[string]interface{}{
+	stats := map[string]interface{}{
 		"totalDebates":  totalDebates,
 		"wins":          wins,
 		"losses":        losses,
 		"draws":         draws,
 		"winRate":       winRate,
 		"recentDebates": recentDebates,
-	}, nil
+	}
+
+	// Speaker points from team debates. They are extra, so the rest of the stats are still returned
+	// without them.
+	speakerStats, err := GetSpeakerCareerStats(userID)
+	if err != nil {
+		log.Printf("Failed to load speaker stats for user %s: %v", userID.Hex(), err)
+	} else if speakerStats != nil && speakerStats.Debates > 0 {
+		debates := float64(speakerStats.Debates)
+		stats["speakerStats"] = map[string]interface{}{
+			"debates":         speakerStats.Debates,
+			"averagePoints":   float64(speakerStats.TotalPoints) / debates,
+			"averageContent":  float64(speakerStats.TotalContent) / debates,
+			"averageStyle":    float64(speakerStats.TotalStyle) / debates,
+			"averageStrategy": float64(speakerStats.TotalStrategy) / debates,
+			"bestPoints":      speakerStats.BestPoints,
+		}
+	}
+
+	return stats, nil
 }
@@ -0,0 +1,28 @@
+package websocket
+
+import (
+	"errors"
+	"log"
+
+	"arguehub/services"
+)
+
+// trackAudienceDecision moves a debate's audience polls along with the debate: the opening poll is
+// counted when the first speech starts and the closing poll opens when the debate finishes. Only
+// phases moved by the debaters or the server may reach it, never a spectator's message.
+func trackAudienceDecision(debateID, format, phase string) {
+	var step func(string) error
+	switch {
+	case phase == "finished":
+		step = services.OpenAudiencePostPoll
+	case services.PhaseSide(format, phase) != "":
+		step = services.StartAudienceDebate
+	default:
+		return
+	}
+	go func() {
+		if err := step(debateID); err != nil && !errors.Is(err, services.ErrNoAudienceDecision) {
+			log.Printf("failed to update the audience decision for debate %s: %v", debateID, err)
+		}
+	}()
+}
@@ -0,0 +1,51 @@
+package websocket
+
+import (
+	"log"
+
+	"arguehub/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// pushAudienceQuestions sends the top approved spectator questions to a debate room when the
+// format's question phase starts. Taking questions drains the queue, so callers push only the
+// first time a phase is seen (when publishPhaseFeed publishes it), and spectators' phase reports
+// must never reach it.
+func pushAudienceQuestions(debateID, format, phase string, send func(payload map[string]interface{})) {
+	if phase != services.AudienceQuestionPhase(format) {
+		return
+	}
+	go func() {
+		questions, err := services.TakeAudienceQuestions(debateID)
+		if err != nil {
+			log.Printf("failed to load audience questions for debate %s: %v", debateID, err)
+		}
+		if len(questions) == 0 {
+			return
+		}
+		send(map[string]interface{}{
+			"type":      "audienceQuestions",
+			"phase":     phase,
+			"questions": questions,
+		})
+	}()
+}
+
+// markAudienceQuestionAnswered records that the debaters answered a spectator question and tells
+// the room
+func markAudienceQuestionAnswered(debateID, questionID string, send func(payload map[string]interface{})) {
+	objectID, err := primitive.ObjectIDFromHex(questionID)
+	if err != nil {
+		return
+	}
+	question, err := services.SetDebateQuestionAnswered(debateID, objectID, true)
+	if err != nil {
+		log.Printf("failed to mark audience question %s answered in debate %s: %v", questionID, debateID, err)
+		return
+	}
+	send(map[string]interface{}{
+		"type":     "audienceQuestionAnswered",
+		"question": question,
+	})
+}
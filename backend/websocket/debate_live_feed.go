@@ -0,0 +1,79 @@
+package websocket
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"arguehub/internal/debate"
+)
+
+// The 1v1 and team rooms mirror phases, speeches and debater chat into the spectator hub
+// (/ws/debate/:debateID), giving spectators a read-only feed of the debate itself
+
+var (
+	liveFeedPhases   = make(map[string]string) // debateID -> last published phase
+	liveFeedPhasesMu sync.Mutex
+)
+
+// publishLiveFeed streams an event from a debate room to the debate's spectators
+func publishLiveFeed(debateID, eventType string, payload interface{}) {
+	event, err := debate.NewEvent(eventType, payload)
+	if err != nil {
+		return
+	}
+	PublishSpectatorEvent(debateID, event)
+}
+
+// publishPhaseFeed publishes a phase change once, however many debaters report it, and reports
+// whether this call was the one that published it
+func publishPhaseFeed(debateID string, payload debate.PhasePayload) bool {
+	liveFeedPhasesMu.Lock()
+	if liveFeedPhases[debateID] == payload.Phase {
+		liveFeedPhasesMu.Unlock()
+		return false
+	}
+	liveFeedPhases[debateID] = payload.Phase
+	liveFeedPhasesMu.Unlock()
+
+	payload.Timestamp = time.Now().Unix()
+	publishLiveFeed(debateID, "phase", payload)
+	recordTimelinePhase(debateID, payload)
+	return true
+}
+
+// liveFeedPhase returns the last phase published for a debate, or "" before the first
+func liveFeedPhase(debateID string) string {
+	liveFeedPhasesMu.Lock()
+	defer liveFeedPhasesMu.Unlock()
+	return liveFeedPhases[debateID]
+}
+
+// publishSpeechFeed publishes a finished speech
+func publishSpeechFeed(debateID string, payload debate.SpeechPayload) {
+	if strings.TrimSpace(payload.Text) == "" {
+		return
+	}
+	payload.Timestamp = time.Now().Unix()
+	publishLiveFeed(debateID, "speechText", payload)
+	recordTimelineSegment(debateID, payload)
+}
+
+// publishChatFeed publishes a chat message sent by a debater
+func publishChatFeed(debateID string, payload debate.ChatPayload) {
+	if strings.TrimSpace(payload.Content) == "" {
+		return
+	}
+	if payload.Timestamp == 0 {
+		payload.Timestamp = time.Now().Unix()
+	}
+	publishLiveFeed(debateID, "chat", payload)
+}
+
+// forgetLiveFeed drops a closed room's feed state
+func forgetLiveFeed(debateID string) {
+	liveFeedPhasesMu.Lock()
+	delete(liveFeedPhases, debateID)
+	liveFeedPhasesMu.Unlock()
+	finishReactionTimeline(debateID)
+}
@@ -4,15 +4,25 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net"
+	"os"
+	"runtime"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"arguehub/internal/debate"
+	"arguehub/models"
+	"arguehub/services"
+	"arguehub/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 var debateUpgrader = websocket.Upgrader{
@@ -21,10 +31,35 @@ var debateUpgrader = websocket.Upgrader{
 	},
 }
 
+const (
+	spectatorPongWait   = 60 * time.Second           // how long a spectator may stay silent before it is dropped
+	spectatorPingPeriod = spectatorPongWait * 9 / 10 // how often spectators are pinged
+	spectatorWriteWait  = 10 * time.Second
+
+	// spectatorLingerAfterEnd keeps spectators connected after the debaters leave, long enough for
+	// the closing audience poll and its verdict
+	spectatorLingerAfterEnd = 5 * time.Minute
+)
+
 // DebateHub manages WebSocket connections for spectators
 type DebateHub struct {
 	debates map[string]*DebateRoom
 	mu      sync.RWMutex
+
+	roomsOpened      atomic.Int64
+	roomsClosed      atomic.Int64
+	staleDisconnects atomic.Int64
+}
+
+// SpectatorHubMetrics describes the hub's open rooms and connections
+type SpectatorHubMetrics struct {
+	OpenRooms        int   `json:"openRooms"`
+	Spectators       int   `json:"spectators"`
+	StreamConsumers  int64 `json:"streamConsumers"` // running consumers, one per open room
+	Goroutines       int   `json:"goroutines"`
+	RoomsOpened      int64 `json:"roomsOpened"`
+	RoomsClosed      int64 `json:"roomsClosed"`
+	StaleDisconnects int64 `json:"staleDisconnects"` // spectators dropped for missing heartbeats
 }
 
 // DebateRoom holds connections for a specific debate
@@ -32,7 +67,7 @@ type DebateRoom struct {
 	debateID string
 	clients  map[*websocket.Conn]*SpectatorClient
 	mu       sync.RWMutex
-	consumer *debate.StreamConsumer
+	consumer debate.StreamConsumer
 }
 
 // SpectatorClient represents a connected spectator
@@ -40,10 +75,33 @@ type SpectatorClient struct {
 	conn          *websocket.Conn
 	writeMu       sync.Mutex
 	spectatorHash string
-	lastEventID   string
+	userID        string // set for logged-in spectators
+	username      string
+	ipHash        string
+	lastEventID   string // last stream event covered by the replay
+	firstLiveID   string // first stream event delivered live before the replay was sent
+	replayed      bool
 	debateID      string
 }
 
+// spectatorIdentity is who is behind a spectator connection
+type spectatorIdentity struct {
+	spectatorHash string
+	userID        string // set for logged-in spectators
+	username      string
+	ipHash        string
+}
+
+// replaySkippedEvents are left out of the replay because the poll snapshot or chat history already
+// covers them, or, for reactions, because they only mean something live
+var replaySkippedEvents = map[string]bool{
+	"vote":                   true,
+	"poll_created":           true,
+	"spectator_chat":         true,
+	"spectator_chat_deleted": true,
+	"reaction":               true,
+}
+
 // NewDebateHub creates a new DebateHub
 func NewDebateHub() *DebateHub {
 	hub := &DebateHub{
@@ -53,9 +111,8 @@ func NewDebateHub() *DebateHub {
 }
 
 // Register registers a new WebSocket connection for a debate
-func (h *DebateHub) Register(debateID string, conn *websocket.Conn, spectatorHash string) *SpectatorClient {
+func (h *DebateHub) Register(debateID string, conn *websocket.Conn, identity spectatorIdentity) *SpectatorClient {
 	h.mu.Lock()
-	defer h.mu.Unlock()
 
 	// Get or create debate room
 	room, exists := h.debates[debateID]
@@ -66,17 +123,19 @@ func (h *DebateHub) Register(debateID string, conn *websocket.Conn, spectatorHas
 			consumer: debate.NewStreamConsumer(h),
 		}
 		h.debates[debateID] = room
+		h.roomsOpened.Add(1)
 
-		// Start consumer group for this debate (only if Redis is available)
-		if room.consumer != nil {
-			go room.consumer.StartConsumerGroup(debateID)
-		}
+		// Start forwarding this debate's stream (Redis, or in memory in single-instance mode)
+		go room.consumer.StartConsumerGroup(debateID)
 	}
 
 	// Create client
 	client := &SpectatorClient{
 		conn:          conn,
-		spectatorHash: spectatorHash,
+		spectatorHash: identity.spectatorHash,
+		userID:        identity.userID,
+		username:      identity.username,
+		ipHash:        identity.ipHash,
 		debateID:      debateID,
 	}
 
@@ -84,6 +143,7 @@ func (h *DebateHub) Register(debateID string, conn *websocket.Conn, spectatorHas
 	room.clients[conn] = client
 	clientCount := len(room.clients)
 	room.mu.Unlock()
+	h.mu.Unlock()
 
 	// Broadcast presence update
 	presenceEvent := map[string]interface{}{
@@ -98,21 +158,35 @@ func (h *DebateHub) Register(debateID string, conn *websocket.Conn, spectatorHas
 	return client
 }
 
-// Unregister removes a WebSocket connection
+// Unregister removes a WebSocket connection. The last spectator to leave closes the room and
+// stops its stream consumer.
 func (h *DebateHub) Unregister(debateID string, conn *websocket.Conn) {
-	h.mu.RLock()
+	h.mu.Lock()
 	room, exists := h.debates[debateID]
-	h.mu.RUnlock()
-
 	if !exists {
+		h.mu.Unlock()
 		return
 	}
 
 	room.mu.Lock()
+	_, registered := room.clients[conn]
 	delete(room.clients, conn)
 	clientCount := len(room.clients)
 	room.mu.Unlock()
 
+	if registered && clientCount == 0 {
+		delete(h.debates, debateID)
+	}
+	h.mu.Unlock()
+
+	if !registered {
+		return
+	}
+	if clientCount == 0 {
+		h.stopRoom(room)
+		return
+	}
+
 	// Broadcast presence update
 	presenceEvent := map[string]interface{}{
 		"type": "presence",
@@ -124,6 +198,88 @@ func (h *DebateHub) Unregister(debateID string, conn *websocket.Conn) {
 	h.BroadcastPresence(debateID, presenceEvent)
 }
 
+// CloseDebate disconnects a debate's spectators and closes its room
+func (h *DebateHub) CloseDebate(debateID string) {
+	h.mu.Lock()
+	room, exists := h.debates[debateID]
+	if exists {
+		delete(h.debates, debateID)
+	}
+	h.mu.Unlock()
+
+	if !exists {
+		return
+	}
+
+	room.mu.RLock()
+	clients := make([]*SpectatorClient, 0, len(room.clients))
+	for _, client := range room.clients {
+		clients = append(clients, client)
+	}
+	room.mu.RUnlock()
+
+	closing := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "debate ended")
+	for _, client := range clients {
+		client.WriteJSON(map[string]interface{}{
+			"type":      "debate_closed",
+			"payload":   map[string]interface{}{"debateId": debateID},
+			"timestamp": time.Now().Unix(),
+		})
+		client.conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(spectatorWriteWait))
+		client.conn.Close()
+	}
+	h.stopRoom(room)
+}
+
+// CloseDebateLater closes a debate's spectator room once the linger period is over, unless
+// stillLive reports the debate has started again by then
+func (h *DebateHub) CloseDebateLater(debateID string, stillLive func() bool) {
+	time.AfterFunc(spectatorLingerAfterEnd, func() {
+		if !stillLive() {
+			h.CloseDebate(debateID)
+		}
+	})
+}
+
+// stopRoom stops a closed room's stream consumer
+func (h *DebateHub) stopRoom(room *DebateRoom) {
+	room.consumer.Stop()
+	h.roomsClosed.Add(1)
+}
+
+// Metrics reports the hub's open rooms and connections, for spotting leaks
+func (h *DebateHub) Metrics() SpectatorHubMetrics {
+	h.mu.RLock()
+	metrics := SpectatorHubMetrics{OpenRooms: len(h.debates)}
+	for _, room := range h.debates {
+		room.mu.RLock()
+		metrics.Spectators += len(room.clients)
+		room.mu.RUnlock()
+	}
+	h.mu.RUnlock()
+
+	metrics.StreamConsumers = debate.RunningConsumers()
+	metrics.Goroutines = runtime.NumGoroutine()
+	metrics.RoomsOpened = h.roomsOpened.Load()
+	metrics.RoomsClosed = h.roomsClosed.Load()
+	metrics.StaleDisconnects = h.staleDisconnects.Load()
+	return metrics
+}
+
+// SpectatorCount returns how many spectators are watching a debate on this instance
+func (h *DebateHub) SpectatorCount(debateID string) int {
+	h.mu.RLock()
+	room, exists := h.debates[debateID]
+	h.mu.RUnlock()
+
+	if !exists {
+		return 0
+	}
+	room.mu.RLock()
+	defer room.mu.RUnlock()
+	return len(room.clients)
+}
+
 // BroadcastToDebate broadcasts an event to all connected clients for a debate
 func (h *DebateHub) BroadcastToDebate(debateID string, event *debate.Event) {
 	h.mu.RLock()
@@ -141,7 +297,22 @@ func (h *DebateHub) BroadcastToDebate(debateID string, event *debate.Event) {
 	}
 	room.mu.RUnlock()
 
-	// Convert event to frontend format - parse payload
+	// Convert event to frontend format
+	eventData := spectatorEventData(event)
+
+	// Broadcast to all clients
+	for _, client := range clients {
+		if err := client.writeStreamEvent(event.ID, eventData); err != nil {
+		}
+	}
+
+	if event.Type == "spectator_chat_sanction" {
+		h.disconnectBanned(debateID, event)
+	}
+}
+
+// spectatorEventData converts an event to the format the frontend expects
+func spectatorEventData(event *debate.Event) map[string]interface{} {
 	var payload interface{}
 	if err := json.Unmarshal(event.Payload, &payload); err != nil {
 		// If unmarshal fails, use raw payload
@@ -153,12 +324,10 @@ func (h *DebateHub) BroadcastToDebate(debateID string, event *debate.Event) {
 		"payload":   payload,
 		"timestamp": event.Timestamp,
 	}
-
-	// Broadcast to all clients
-	for _, client := range clients {
-		if err := client.WriteJSON(eventData); err != nil {
-		}
+	if event.ID != "" {
+		eventData["id"] = event.ID
 	}
+	return eventData
 }
 
 // BroadcastPresence broadcasts a presence update directly
@@ -185,6 +354,11 @@ func (h *DebateHub) BroadcastPresence(debateID string, presenceEvent map[string]
 	}
 }
 
+// authenticated reports whether the spectator logged in
+func (c *SpectatorClient) authenticated() bool {
+	return c.userID != ""
+}
+
 // WriteJSON safely writes JSON to the WebSocket connection
 func (c *SpectatorClient) WriteJSON(v interface{}) error {
 	c.writeMu.Lock()
@@ -192,6 +366,76 @@ func (c *SpectatorClient) WriteJSON(v interface{}) error {
 	return c.conn.WriteJSON(v)
 }
 
+// heartbeat pings the spectator until done is closed. A spectator that stops answering is dropped
+// by the read deadline in readPump.
+func (c *SpectatorClient) heartbeat(done <-chan struct{}) {
+	ticker := time.NewTicker(spectatorPingPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(spectatorWriteWait)); err != nil {
+				c.conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// writeStreamEvent writes a live event read from the stream, skipping events the replay already sent
+func (c *SpectatorClient) writeStreamEvent(id string, v interface{}) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if id != "" {
+		if c.lastEventID != "" && debate.CompareEventIDs(id, c.lastEventID) <= 0 {
+			return nil
+		}
+		if !c.replayed && c.firstLiveID == "" {
+			c.firstLiveID = id
+		}
+	}
+	return c.conn.WriteJSON(v)
+}
+
+// sendReplay sends a late-joining spectator everything that happened in the debate so far.
+// Events already delivered live are left out, and live events the replay covers are skipped later.
+func (c *SpectatorClient) sendReplay() error {
+	events, err := debate.ReadStream(c.debateID)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	replay := make([]map[string]interface{}, 0, len(events))
+	for _, event := range events {
+		if c.firstLiveID != "" && debate.CompareEventIDs(event.ID, c.firstLiveID) >= 0 {
+			break
+		}
+		if !replaySkippedEvents[event.Type] {
+			replay = append(replay, spectatorEventData(event))
+		}
+	}
+	if c.firstLiveID == "" && len(events) > 0 {
+		c.lastEventID = events[len(events)-1].ID
+	}
+	c.replayed = true
+
+	return c.conn.WriteJSON(map[string]interface{}{
+		"type": "replay",
+		"payload": map[string]interface{}{
+			"events":      replay,
+			"lastEventId": c.lastEventID,
+		},
+		"timestamp": time.Now().Unix(),
+	})
+}
+
 // DebateWebsocketHandler handles WebSocket connections for debate spectators
 func DebateWebsocketHandler(c *gin.Context) {
 	debateID := c.Param("debateID")
@@ -201,39 +445,66 @@ func DebateWebsocketHandler(c *gin.Context) {
 		return
 	}
 
-	// Upgrade connection
-	conn, err := debateUpgrader.Upgrade(c.Writer, c.Request, nil)
+	identity, err := identifySpectator(c)
 	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
 		return
 	}
-	defer conn.Close()
 
-	// Get or generate spectator hash
-	spectatorID := c.Query("spectatorId")
+	hub := GetDebateHub()
+	settings := spectatorSettings(debateID)
+	if spectatorBanned(debateID, identity) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You are banned from this debate"})
+		return
+	}
 
-	var spectatorHash string
-	if spectatorID != "" {
-		// Hash the spectator ID
-		h := sha256.Sum256([]byte(spectatorID))
-		spectatorHash = hex.EncodeToString(h[:])
-	} else {
-		// Generate ephemeral ID
-		ephemeralID := uuid.New().String()
-		h := sha256.Sum256([]byte(ephemeralID))
-		spectatorHash = hex.EncodeToString(h[:])
+	// Anonymous viewers are limited per IP address so reconnecting with new IDs can't stuff votes.
+	// The slot is taken atomically before the upgrade and held until the viewer leaves.
+	if identity.userID == "" {
+		rateLimiter := debate.NewRateLimiter()
+		allowed, err := rateLimiter.AcquireAnonymousViewer(debateID, identity.ipHash, settings.MaxAnonymousPerIP)
+		if err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Anonymous spectating is unavailable. Log in to watch."})
+			return
+		}
+		if !allowed {
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many anonymous viewers from your network. Log in to watch."})
+			return
+		}
+		defer rateLimiter.ReleaseAnonymousViewer(debateID, identity.ipHash)
 	}
 
+	// Upgrade connection
+	conn, err := debateUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
 	// Register client
-	hub := GetDebateHub()
-	client := hub.Register(debateID, conn, spectatorHash)
+	client := hub.Register(debateID, conn, identity)
 	defer hub.Unregister(debateID, conn)
 
-	// Send initial poll snapshot
+	// Tell the spectator who they are and what they need to log in for
+	client.WriteJSON(map[string]interface{}{
+		"type": "identity",
+		"payload": map[string]interface{}{
+			"spectatorHash": identity.spectatorHash,
+			"authenticated": client.authenticated(),
+			"username":      identity.username,
+			"settings":      settings,
+		},
+		"timestamp": time.Now().Unix(),
+	})
+
+	// Send initial poll snapshot, closing timed polls whose timer was lost first
+	services.CloseExpiredPolls(debateID)
 	snapshot, err := loadPollSnapshot(debateID)
 	if err == nil && snapshot != nil {
-		conn.WriteJSON(snapshot)
+		client.WriteJSON(snapshot)
 	} else if err != nil {
 	}
+	client.sendChatHistory()
 
 	// Send initial presence count - get it from the hub after registration
 	hub.mu.RLock()
@@ -254,27 +525,101 @@ func DebateWebsocketHandler(c *gin.Context) {
 		},
 		"timestamp": time.Now().Unix(),
 	}
-	conn.WriteJSON(presenceEvent)
+	client.WriteJSON(presenceEvent)
+
+	// Replay the debate so far for spectators joining late
+	client.sendReplay()
+
+	// Ping the spectator while the connection is open
+	done := make(chan struct{})
+	defer close(done)
+	go client.heartbeat(done)
+
+	// Read until the spectator leaves, stops answering pings or the debate closes
+	readPump(client, hub)
+}
 
-	// Read pump
-	go readPump(client, hub)
+// identifySpectator works out who is behind a spectator connection. Logged-in spectators (JWT in the
+// Authorization header or token query parameter) vote under their account; anyone else under the
+// spectatorId they send, or a random ID. The per-IP limits for anonymous spectators rely on
+// c.ClientIP(), which only honours X-Forwarded-For from the router's trusted proxies, so a
+// deployment behind a load balancer must list it there or every viewer shares the proxy's address.
+func identifySpectator(c *gin.Context) (spectatorIdentity, error) {
+	ipSum := sha256.Sum256([]byte("ip:" + c.ClientIP()))
+	identity := spectatorIdentity{ipHash: hex.EncodeToString(ipSum[:])}
+
+	token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if token != "" {
+		configPath := os.Getenv("CONFIG_PATH")
+		if configPath == "" {
+			configPath = "./config/config.yml"
+		}
+		valid, email, err := utils.ValidateTokenAndFetchEmail(configPath, token, c)
+		if err != nil || !valid || email == "" {
+			return identity, errors.New("invalid token")
+		}
+		userID, username, _, _, err := getUserDetails(email)
+		if err != nil {
+			return identity, err
+		}
+		h := sha256.Sum256([]byte("user:" + userID))
+		identity.spectatorHash = hex.EncodeToString(h[:])
+		identity.userID = userID
+		identity.username = username
+		return identity, nil
+	}
+
+	// The "user:" prefix is reserved so anonymous IDs can't collide with logged-in spectators
+	spectatorID := c.Query("spectatorId")
+	if spectatorID == "" || strings.HasPrefix(spectatorID, "user:") {
+		spectatorID = uuid.New().String()
+	}
+	h := sha256.Sum256([]byte(spectatorID))
+	identity.spectatorHash = hex.EncodeToString(h[:])
+	return identity, nil
+}
+
+// spectatorSettings loads a debate's spectator settings, using the defaults if they can't be loaded
+func spectatorSettings(debateID string) models.SpectatorSettings {
+	settings, err := services.GetSpectatorSettings(debateID)
+	if err != nil {
+		return services.DefaultSpectatorSettings(debateID)
+	}
+	return settings
+}
 
-	// Keep connection alive
-	select {}
+// rejectSpectatorAction tells a spectator why their vote or question was turned away
+func rejectSpectatorAction(client *SpectatorClient, eventType, reason string) {
+	client.WriteJSON(map[string]interface{}{
+		"type":      eventType,
+		"payload":   map[string]interface{}{"error": reason},
+		"timestamp": time.Now().Unix(),
+	})
 }
 
 // readPump handles incoming messages from client
 func readPump(client *SpectatorClient, hub *DebateHub) {
 	defer client.conn.Close()
 
+	client.conn.SetReadDeadline(time.Now().Add(spectatorPongWait))
+	client.conn.SetPongHandler(func(string) error {
+		client.conn.SetReadDeadline(time.Now().Add(spectatorPongWait))
+		return nil
+	})
+
 	for {
 		_, messageBytes, err := client.conn.ReadMessage()
 		if err != nil {
-			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-			} else {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				hub.staleDisconnects.Add(1)
 			}
 			break
 		}
+		client.conn.SetReadDeadline(time.Now().Add(spectatorPongWait))
 
 		// Parse client message
 		var clientMsg debate.ClientMessage
@@ -289,10 +634,16 @@ func readPump(client *SpectatorClient, hub *DebateHub) {
 			handleVote(client, clientMsg.Payload)
 		case "question":
 			handleQuestion(client, clientMsg.Payload)
+		case "upvoteQuestion", "upvote_question":
+			handleUpvoteQuestion(client, clientMsg.Payload)
 		case "reaction":
 			handleReaction(client, clientMsg.Payload)
 		case "createPoll", "create_poll":
 			handleCreatePoll(client, clientMsg.Payload)
+		case "closePoll", "close_poll":
+			handleClosePoll(client, clientMsg.Payload)
+		case "spectatorChat", "spectator_chat":
+			handleSpectatorChat(client, clientMsg.Payload)
 		default:
 		}
 	}
@@ -307,111 +658,128 @@ func handleVote(client *SpectatorClient, payloadBytes []byte) {
 
 	// Set spectator hash and timestamp
 	payload.SpectatorHash = client.spectatorHash
+	payload.Authenticated = client.authenticated()
 	if payload.ClientEventID == "" {
 		payload.ClientEventID = uuid.New().String()
 	}
 	payload.Timestamp = time.Now().Unix()
 
+	settings := spectatorSettings(client.debateID)
+	if settings.RequireLoginToVote && !client.authenticated() {
+		rejectSpectatorAction(client, "vote_rejected", "Log in to vote in this debate")
+		return
+	}
+
 	// Check rate limit
 	rateLimiter := debate.NewRateLimiter()
 	canVote, err := rateLimiter.CheckVoteRateLimit(client.debateID, payload.PollID, client.spectatorHash)
 	if err != nil || !canVote {
 		return
 	}
+	if !client.authenticated() {
+		allowed, err := rateLimiter.AllowAnonymousVote(client.debateID, payload.PollID, client.ipHash, client.spectatorHash, settings.MaxAnonymousVotesPerIP)
+		if err != nil {
+			return
+		}
+		if !allowed {
+			rejectSpectatorAction(client, "vote_rejected", "Too many anonymous votes from your network. Log in to vote.")
+			return
+		}
+	}
 
 	// Process vote
 	store := debate.NewPollStore()
-	success, err := store.Vote(client.debateID, payload.PollID, payload.Option, client.spectatorHash)
-	if err != nil {
+	success, err := store.Vote(client.debateID, payload.PollID, payload.Option, client.spectatorHash, client.authenticated())
+	if errors.Is(err, debate.ErrPollClosed) {
+		rejectSpectatorAction(client, "vote_rejected", "This poll is closed")
 		return
 	}
-
-	if !success {
+	if errors.Is(err, debate.ErrPollNotFound) || errors.Is(err, debate.ErrPollOption) {
+		rejectSpectatorAction(client, "vote_rejected", "This poll has no such option")
 		return
 	}
-
-	// Get hub to broadcast
-	hub := GetDebateHub()
-
-	// Create event for broadcasting (in frontend format)
-	voteEvent := map[string]interface{}{
-		"type":      "vote",
-		"payload":   payload,
-		"timestamp": payload.Timestamp,
+	if err != nil {
+		return
 	}
 
-	// Broadcast directly to all connected clients
-	hub.mu.RLock()
-	room, exists := hub.debates[client.debateID]
-	if exists {
-		room.mu.RLock()
-		for _, c := range room.clients {
-			c.WriteJSON(voteEvent)
-		}
-		room.mu.RUnlock()
+	if !success {
+		return
 	}
-	hub.mu.RUnlock()
 
-	// Also publish to stream for persistence (if Redis is available)
+	// Publish to the stream, which broadcasts it to all connected clients
 	event, err := debate.NewEvent("vote", payload)
 	if err == nil {
-		debate.PublishEvent(client.debateID, event) // Ignore error if Redis unavailable
+		PublishSpectatorEvent(client.debateID, event)
 	}
 }
 
-// handleQuestion handles a question request
+// handleQuestion queues a spectator question for the hosts to review. Approved questions are
+// shown to every spectator and the most upvoted ones are put to the debaters.
 func handleQuestion(client *SpectatorClient, payloadBytes []byte) {
 	var payload debate.QuestionPayload
 	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
 		return
 	}
 
-	// Set spectator hash and timestamp
-	payload.SpectatorHash = client.spectatorHash
-	if payload.QID == "" {
-		payload.QID = uuid.New().String()
+	settings := spectatorSettings(client.debateID)
+	if settings.RequireLoginToAsk && !client.authenticated() {
+		rejectSpectatorAction(client, "question_rejected", "Log in to ask questions in this debate")
+		return
 	}
-	payload.Timestamp = time.Now().Unix()
 
-	// Check rate limit
+	// Check rate limit. Anonymous spectators also share a limit per IP address, so reconnecting
+	// with a new spectatorId doesn't reset it.
 	rateLimiter := debate.NewRateLimiter()
 	config := debate.DefaultRateLimitConfig()
-	canAsk, err := rateLimiter.CheckQuestionRateLimit(client.debateID, client.spectatorHash, config)
+	canAsk, err := rateLimiter.AllowQuestion(client.debateID, client.spectatorHash, config.MaxQuestions, config.QuestionWindow)
 	if err != nil || !canAsk {
 		return
 	}
+	if !client.authenticated() {
+		perIP := config.MaxQuestions * settings.MaxAnonymousPerIP
+		canAsk, err = rateLimiter.AllowQuestion(client.debateID, "ip:"+client.ipHash, perIP, config.QuestionWindow)
+		if err != nil || !canAsk {
+			return
+		}
+	}
 
-	// Record rate limit
-	rateLimiter.RecordQuestion(client.debateID, client.spectatorHash, config)
+	question, duplicate, err := services.SubmitDebateQuestion(client.debateID, payload.Text, client.spectatorHash)
+	if err != nil {
+		rejectSpectatorAction(client, "question_rejected", err.Error())
+		return
+	}
 
-	// Get hub to broadcast
-	hub := GetDebateHub()
+	// Only the asker hears about the question until it is approved
+	client.WriteJSON(map[string]interface{}{
+		"type": "question_received",
+		"payload": map[string]interface{}{
+			"qId":       question.ID.Hex(),
+			"status":    question.Status,
+			"upvotes":   question.Upvotes,
+			"duplicate": duplicate,
+		},
+		"timestamp": time.Now().Unix(),
+	})
+}
 
-	// Create event for broadcasting (in frontend format)
-	questionEvent := map[string]interface{}{
-		"type":      "question",
-		"payload":   payload,
-		"timestamp": payload.Timestamp,
+// handleUpvoteQuestion counts a spectator's upvote for a question in the Q&A queue
+func handleUpvoteQuestion(client *SpectatorClient, payloadBytes []byte) {
+	var payload struct {
+		QID string `json:"qId"`
 	}
-
-	// Broadcast directly to all connected clients
-	hub.mu.RLock()
-	room, exists := hub.debates[client.debateID]
-	if exists {
-		room.mu.RLock()
-		for _, c := range room.clients {
-			if err := c.WriteJSON(questionEvent); err != nil {
-			}
-		}
-		room.mu.RUnlock()
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return
 	}
-	hub.mu.RUnlock()
-
-	// Also publish to stream for persistence (if Redis is available)
-	event, err := debate.NewEvent("question", payload)
-	if err == nil {
-		debate.PublishEvent(client.debateID, event) // Ignore error if Redis unavailable
+	questionID, err := primitive.ObjectIDFromHex(payload.QID)
+	if err != nil {
+		return
+	}
+	if spectatorSettings(client.debateID).RequireLoginToAsk && !client.authenticated() {
+		return
 	}
+
+	// Approved questions announce their new count to every spectator
+	services.UpvoteDebateQuestion(client.debateID, questionID, client.spectatorHash)
 }
 
 // handleReaction handles a reaction request
@@ -435,125 +803,86 @@ func handleReaction(client *SpectatorClient, payloadBytes []byte) {
 
 	// Record rate limit
 	rateLimiter.RecordReaction(client.debateID, client.spectatorHash, config)
+	countReaction(client.debateID, payload.Reaction, payload.Timestamp)
 
-	// Get hub to broadcast
-	hub := GetDebateHub()
-
-	// Create event for broadcasting (in frontend format)
-	reactionEvent := map[string]interface{}{
-		"type":      "reaction",
-		"payload":   payload,
-		"timestamp": payload.Timestamp,
-	}
-
-	// Broadcast directly to all connected clients
-	hub.mu.RLock()
-	room, exists := hub.debates[client.debateID]
-	if exists {
-		room.mu.RLock()
-		for _, c := range room.clients {
-			c.WriteJSON(reactionEvent)
-		}
-		room.mu.RUnlock()
-	}
-	hub.mu.RUnlock()
-
-	// Also publish to stream for persistence (if Redis is available)
+	// Publish to the stream, which broadcasts it to all connected clients
 	event, err := debate.NewEvent("reaction", payload)
 	if err == nil {
-		debate.PublishEvent(client.debateID, event) // Ignore error if Redis unavailable
+		PublishSpectatorEvent(client.debateID, event)
 	}
 }
 
-// handleCreatePoll handles a poll creation request
+// handleCreatePoll opens a poll for the debate's spectators (debate hosts only)
 func handleCreatePoll(client *SpectatorClient, payloadBytes []byte) {
 	var payload debate.CreatePollPayload
 	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
 		return
 	}
 
-	payload.SpectatorHash = client.spectatorHash
-	payload.Timestamp = time.Now().Unix()
-
-	store := debate.NewPollStore()
-	if store == nil {
+	if !client.isDebateHost() {
+		rejectSpectatorAction(client, "poll_rejected", "Only the debate's hosts can create polls")
 		return
 	}
 
-	pollID, err := store.CreatePoll(client.debateID, payload.PollID, payload.Question, payload.Options)
-	if err != nil {
+	poll := debate.PollMetadata{
+		PollID:    payload.PollID,
+		Question:  payload.Question,
+		Options:   payload.Options,
+		CreatedBy: client.userID,
+	}
+	// The poll reaches every spectator through the poll_created event
+	if _, err := services.OpenDebatePoll(client.debateID, poll, time.Duration(payload.Duration)*time.Second); err != nil {
+		rejectSpectatorAction(client, "poll_rejected", err.Error())
+	}
+}
+
+// handleClosePoll closes a poll and announces its final results (the poll's creator or a debate
+// host only)
+func handleClosePoll(client *SpectatorClient, payloadBytes []byte) {
+	var payload struct {
+		PollID string `json:"pollId"`
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil || payload.PollID == "" {
 		return
 	}
 
-	// Load current snapshot for counts/metadata
-	snapshot, err := loadPollSnapshot(client.debateID)
+	meta, err := services.GetDebatePollMetadata(client.debateID, payload.PollID)
 	if err != nil {
-		// Even if snapshot fails, continue with created event
-	}
-
-	// Determine created poll data for event
-	var createdPoll map[string]interface{}
-	if snapshot != nil {
-		if payloadMap, ok := snapshot["payload"].(map[string]interface{}); ok {
-			if pollsRaw, ok := payloadMap["polls"].([]interface{}); ok {
-				for _, p := range pollsRaw {
-					if pollObj, ok := p.(map[string]interface{}); ok {
-						if id, ok := pollObj["pollId"].(string); ok && id == pollID {
-							createdPoll = pollObj
-							break
-						}
-					}
-				}
-			}
-		}
+		rejectSpectatorAction(client, "poll_rejected", err.Error())
+		return
 	}
-
-	if createdPoll == nil {
-		// Fallback payload
-		createdPoll = map[string]interface{}{
-			"pollId":   pollID,
-			"question": payload.Question,
-			"options":  payload.Options,
-			"counts":   map[string]int64{},
-			"voters":   int64(0),
-		}
+	if !client.authenticated() || (meta.CreatedBy != client.userID && !client.isDebateHost()) {
+		rejectSpectatorAction(client, "poll_rejected", "Only the poll's creator or the debate's hosts can close it")
+		return
 	}
 
-	createdEvent := map[string]interface{}{
-		"type":      "poll_created",
-		"payload":   createdPoll,
-		"timestamp": payload.Timestamp,
+	if _, err := services.CloseDebatePoll(client.debateID, payload.PollID); err != nil {
+		rejectSpectatorAction(client, "poll_rejected", "Failed to close the poll")
 	}
+}
 
-	hub := GetDebateHub()
-	hub.mu.RLock()
-	room, exists := hub.debates[client.debateID]
-	if exists {
-		room.mu.RLock()
-		for _, c := range room.clients {
-			c.WriteJSON(createdEvent)
-			if snapshot != nil {
-				c.WriteJSON(snapshot)
-			}
-		}
-		room.mu.RUnlock()
+// isDebateHost reports whether the spectator is logged in as one of the debate's hosts
+func (c *SpectatorClient) isDebateHost() bool {
+	if !c.authenticated() {
+		return false
 	}
-	hub.mu.RUnlock()
-
-	// Publish event for persistence
-	eventPayload := debate.PollCreatedPayload{
-		PollID:    pollID,
-		Question:  payload.Question,
-		Options:   payload.Options,
-		Timestamp: payload.Timestamp,
+	userID, err := primitive.ObjectIDFromHex(c.userID)
+	if err != nil {
+		return false
 	}
-	event, err := debate.NewEvent("poll_created", eventPayload)
-	if err == nil {
-		debate.PublishEvent(client.debateID, event)
+	isHost, err := services.IsDebateHost(c.debateID, userID)
+	return err == nil && isHost
+}
+
+// PublishSpectatorEvent publishes a server-generated event to the debate's stream, from which it
+// reaches spectators and late joiners. If the stream can't be written it is broadcast directly.
+func PublishSpectatorEvent(debateID string, event *debate.Event) {
+	if err := debate.PublishEvent(debateID, event); err != nil {
+		GetDebateHub().BroadcastToDebate(debateID, event)
 	}
 }
 
-// loadPollSnapshot loads the current poll state from Redis
+// loadPollSnapshot loads the current poll state
 func loadPollSnapshot(debateID string) (map[string]interface{}, error) {
 	store := debate.NewPollStore()
 	pollState, votersCount, metadata, err := store.GetPollState(debateID)
@@ -561,15 +890,37 @@ func loadPollSnapshot(debateID string) (map[string]interface{}, error) {
 		return nil, err
 	}
 
+	pollIDs := make([]string, 0, len(pollState))
+	for pollID := range pollState {
+		pollIDs = append(pollIDs, pollID)
+	}
+	authCounts, err := store.GetAuthenticatedCounts(debateID, pollIDs)
+	if err != nil {
+		authCounts = map[string]map[string]int64{}
+	}
+
 	polls := make([]map[string]interface{}, 0, len(pollState))
 	for pollID, counts := range pollState {
 		meta := metadata[pollID]
+
+		// Split the counts into logged-in and anonymous votes
+		authenticated := make(map[string]int64, len(counts))
+		anonymous := make(map[string]int64, len(counts))
+		for option, count := range counts {
+			authenticated[option] = authCounts[pollID][option]
+			anonymous[option] = count - authCounts[pollID][option]
+		}
+
 		poll := map[string]interface{}{
-			"pollId":   pollID,
-			"question": meta.Question,
-			"options":  meta.Options,
-			"counts":   counts,
-			"voters":   votersCount[pollID],
+			"pollId":              pollID,
+			"question":            meta.Question,
+			"options":             meta.Options,
+			"counts":              counts,
+			"authenticatedCounts": authenticated,
+			"anonymousCounts":     anonymous,
+			"voters":              votersCount[pollID],
+			"closesAt":            meta.ClosesAt,
+			"closedAt":            meta.ClosedAt,
 		}
 		polls = append(polls, poll)
 	}
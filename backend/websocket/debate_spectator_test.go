@@ -0,0 +1,225 @@
+package websocket
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"arguehub/internal/debate"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// spectatorMessage is the part of a message sent to a spectator the tests look at
+type spectatorMessage struct {
+	Type    string `json:"type"`
+	ID      string `json:"id"`
+	Payload struct {
+		Events []struct {
+			Type string `json:"type"`
+		} `json:"events"`
+		LastEventID string `json:"lastEventId"`
+	} `json:"payload"`
+}
+
+// connPair returns the server and client ends of a websocket connection
+func connPair(t *testing.T) (*websocket.Conn, *websocket.Conn) {
+	serverConns := make(chan *websocket.Conn, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := debateUpgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade: %v", err)
+			return
+		}
+		serverConns <- conn
+	}))
+	t.Cleanup(server.Close)
+
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	serverConn := <-serverConns
+	t.Cleanup(func() {
+		client.Close()
+		serverConn.Close()
+	})
+	return serverConn, client
+}
+
+// publishEvents adds events of the given types to a debate's stream and returns them as stored
+func publishEvents(t *testing.T, debateID string, types ...string) []*debate.Event {
+	for _, eventType := range types {
+		event, _ := debate.NewEvent(eventType, map[string]string{"debateId": debateID})
+		if err := debate.PublishEvent(debateID, event); err != nil {
+			t.Fatalf("PublishEvent: %v", err)
+		}
+	}
+	events, err := debate.ReadStream(debateID)
+	if err != nil {
+		t.Fatalf("ReadStream: %v", err)
+	}
+	return events
+}
+
+func readSpectatorMessage(t *testing.T, conn *websocket.Conn) spectatorMessage {
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg spectatorMessage
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	return msg
+}
+
+func replayTypes(msg spectatorMessage) string {
+	types := make([]string, 0, len(msg.Payload.Events))
+	for _, event := range msg.Payload.Events {
+		types = append(types, event.Type)
+	}
+	return strings.Join(types, ",")
+}
+
+func TestReplayLeavesOutLiveOnlyEvents(t *testing.T) {
+	debateID := primitive.NewObjectID().Hex()
+	events := publishEvents(t, debateID, "speech", "vote", "reaction", "phase", "spectator_chat")
+	serverConn, clientConn := connPair(t)
+	client := &SpectatorClient{conn: serverConn, debateID: debateID}
+
+	if err := client.sendReplay(); err != nil {
+		t.Fatalf("sendReplay: %v", err)
+	}
+
+	replay := readSpectatorMessage(t, clientConn)
+	if replay.Type != "replay" || replayTypes(replay) != "speech,phase" {
+		t.Errorf("replay = %s [%s], want speech,phase", replay.Type, replayTypes(replay))
+	}
+	if replay.Payload.LastEventID != events[len(events)-1].ID {
+		t.Errorf("lastEventId = %q, want %q", replay.Payload.LastEventID, events[len(events)-1].ID)
+	}
+}
+
+func TestStreamEventsCoveredByTheReplayAreNotRepeated(t *testing.T) {
+	debateID := primitive.NewObjectID().Hex()
+	events := publishEvents(t, debateID, "speech", "phase")
+	serverConn, clientConn := connPair(t)
+	client := &SpectatorClient{conn: serverConn, debateID: debateID}
+	if err := client.sendReplay(); err != nil {
+		t.Fatalf("sendReplay: %v", err)
+	}
+	readSpectatorMessage(t, clientConn)
+
+	// The consumer can still hand over events the replay already sent
+	for _, event := range events {
+		client.writeStreamEvent(event.ID, spectatorEventData(event))
+	}
+	events = publishEvents(t, debateID, "speech")
+	latest := events[len(events)-1]
+	client.writeStreamEvent(latest.ID, spectatorEventData(latest))
+
+	if msg := readSpectatorMessage(t, clientConn); msg.ID != latest.ID {
+		t.Errorf("next message is event %q, want only the new event %q", msg.ID, latest.ID)
+	}
+}
+
+func TestReplayStopsAtTheFirstLiveEvent(t *testing.T) {
+	debateID := primitive.NewObjectID().Hex()
+	events := publishEvents(t, debateID, "speech", "phase", "speech")
+	serverConn, clientConn := connPair(t)
+	client := &SpectatorClient{conn: serverConn, debateID: debateID}
+
+	// The last event reached the spectator live before the replay was read
+	client.writeStreamEvent(events[2].ID, spectatorEventData(events[2]))
+	readSpectatorMessage(t, clientConn)
+	if err := client.sendReplay(); err != nil {
+		t.Fatalf("sendReplay: %v", err)
+	}
+
+	if replay := readSpectatorMessage(t, clientConn); replayTypes(replay) != "speech,phase" {
+		t.Errorf("replay = [%s], want the two events before the live one", replayTypes(replay))
+	}
+}
+
+// anonymousSpectator identifies a spectator connecting without a token from remoteAddr
+func anonymousSpectator(t *testing.T, remoteAddr, spectatorID string) spectatorIdentity {
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/ws/debate/d1?spectatorId="+spectatorID, nil)
+	c.Request.RemoteAddr = remoteAddr
+	identity, err := identifySpectator(c)
+	if err != nil {
+		t.Fatalf("identifySpectator: %v", err)
+	}
+	return identity
+}
+
+func TestIdentifyAnonymousSpectator(t *testing.T) {
+	first := anonymousSpectator(t, "192.0.2.1:4000", "viewer-1")
+	again := anonymousSpectator(t, "192.0.2.1:4001", "viewer-1")
+	elsewhere := anonymousSpectator(t, "198.51.100.7:4000", "viewer-1")
+
+	if first.userID != "" || first.spectatorHash != again.spectatorHash {
+		t.Errorf("expected a stable anonymous identity for the same spectatorId, got %+v and %+v", first, again)
+	}
+	if first.ipHash != again.ipHash || first.ipHash == elsewhere.ipHash {
+		t.Error("expected the IP hash to follow the address, not the port or spectatorId")
+	}
+
+	fresh := anonymousSpectator(t, "192.0.2.1:4000", "")
+	if fresh.spectatorHash == "" || fresh.spectatorHash == anonymousSpectator(t, "192.0.2.1:4000", "").spectatorHash {
+		t.Error("expected spectators without a spectatorId to get a new random identity")
+	}
+}
+
+func TestAnonymousSpectatorCannotClaimAnAccount(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+	account := sha256.Sum256([]byte("user:" + userID))
+
+	identity := anonymousSpectator(t, "192.0.2.1:4000", "user:"+userID)
+
+	if identity.spectatorHash == hex.EncodeToString(account[:]) {
+		t.Error("expected a reserved user: spectatorId to be replaced")
+	}
+}
+
+// waitFor polls until done reports true or a second has passed
+func waitFor(t *testing.T, what string, done func() bool) {
+	deadline := time.Now().Add(time.Second)
+	for !done() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestLastSpectatorLeavingClosesTheRoom(t *testing.T) {
+	hub := NewDebateHub()
+	debateID := primitive.NewObjectID().Hex()
+	consumers := debate.RunningConsumers()
+	first, _ := connPair(t)
+	second, _ := connPair(t)
+
+	hub.Register(debateID, first, spectatorIdentity{spectatorHash: "a"})
+	hub.Register(debateID, second, spectatorIdentity{spectatorHash: "b"})
+	waitFor(t, "the stream consumer to start", func() bool { return debate.RunningConsumers() == consumers+1 })
+	if metrics := hub.Metrics(); metrics.OpenRooms != 1 || metrics.Spectators != 2 {
+		t.Fatalf("metrics = %+v, want one room with two spectators", metrics)
+	}
+
+	hub.Unregister(debateID, first)
+	hub.Unregister(debateID, first)
+	if metrics := hub.Metrics(); metrics.OpenRooms != 1 || metrics.Spectators != 1 || metrics.RoomsClosed != 0 {
+		t.Fatalf("metrics = %+v, want the room kept open for the remaining spectator", metrics)
+	}
+
+	hub.Unregister(debateID, second)
+	if metrics := hub.Metrics(); metrics.OpenRooms != 0 || metrics.RoomsOpened != 1 || metrics.RoomsClosed != 1 {
+		t.Errorf("metrics = %+v, want the room closed once", metrics)
+	}
+	waitFor(t, "the stream consumer to stop", func() bool { return debate.RunningConsumers() == consumers })
+}
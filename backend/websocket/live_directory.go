@@ -0,0 +1,179 @@
+package websocket
+
+import (
+	"sort"
+
+	"arguehub/models"
+	"arguehub/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// LiveDebates lists the debates in progress for the live directory: the public 1v1 rooms and team
+// rooms open on this instance and the running exhibitions, with their spectator counts from this
+// instance's hub. Rooms open on other instances, and their spectators there, are not included.
+func LiveDebates() ([]services.LiveDebate, error) {
+	oneOnOne, err := liveOneOnOneDebates()
+	if err != nil {
+		return nil, err
+	}
+	teams, err := liveTeamDebates()
+	if err != nil {
+		return nil, err
+	}
+	exhibitions, err := services.ListRunningExhibitions()
+	if err != nil {
+		return nil, err
+	}
+
+	debates := append(append(oneOnOne, teams...), exhibitions...)
+	hub := GetDebateHub()
+	for i := range debates {
+		debates[i].Spectators = hub.SpectatorCount(debates[i].DebateID)
+	}
+	return debates, nil
+}
+
+// liveOneOnOneDebates lists the public 1v1 rooms with a debater in them. Only rooms stored as
+// public are listed, so a room whose type can't be confirmed stays out.
+func liveOneOnOneDebates() ([]services.LiveDebate, error) {
+	roomsMutex.Lock()
+	open := make(map[string]*Room, len(rooms))
+	for roomID, room := range rooms {
+		open[roomID] = room
+	}
+	roomsMutex.Unlock()
+
+	debates := make([]services.LiveDebate, 0, len(open))
+	for roomID, room := range open {
+		entry := services.LiveDebate{
+			DebateID:     roomID,
+			Type:         services.LiveDebateOneOnOne,
+			Participants: []services.LiveDebateParticipant{},
+		}
+		room.Mutex.Lock()
+		entry.Topic = room.Topic
+		entry.StartedAt = room.CreatedAt
+		for _, client := range room.Clients {
+			if client.IsSpectator {
+				continue
+			}
+			entry.Participants = append(entry.Participants, services.LiveDebateParticipant{
+				UserID: client.UserID,
+				Name:   client.Username,
+				Side:   client.Role,
+				Rating: float64(client.Elo),
+			})
+		}
+		room.Mutex.Unlock()
+
+		if len(entry.Participants) == 0 {
+			continue
+		}
+		sortLiveParticipants(entry.Participants)
+		entry.Phase = liveFeedPhase(roomID)
+		entry.Rating = services.AverageRating(entry.Participants)
+		debates = append(debates, entry)
+	}
+
+	roomIDs := make([]string, 0, len(debates))
+	for _, entry := range debates {
+		roomIDs = append(roomIDs, entry.DebateID)
+	}
+	public, err := services.PublicRoomIDs(roomIDs)
+	if err != nil {
+		return nil, err
+	}
+	listed := debates[:0]
+	for _, entry := range debates {
+		if public[entry.DebateID] {
+			listed = append(listed, entry)
+		}
+	}
+	return listed, nil
+}
+
+// liveTeamDebates lists the open team rooms, with both teams' line-ups and ratings from the stored
+// debate
+func liveTeamDebates() ([]services.LiveDebate, error) {
+	teamRoomsMutex.Lock()
+	open := make([]*TeamRoom, 0, len(teamRooms))
+	for _, room := range teamRooms {
+		open = append(open, room)
+	}
+	teamRoomsMutex.Unlock()
+
+	ids := make([]primitive.ObjectID, 0, len(open))
+	for _, room := range open {
+		ids = append(ids, room.DebateID)
+	}
+	stored, err := services.LoadTeamDebates(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	debates := make([]services.LiveDebate, 0, len(open))
+	for _, room := range open {
+		teamDebate, ok := stored[room.DebateID]
+		if !ok || teamDebate.Status == "finished" {
+			continue
+		}
+
+		room.Mutex.Lock()
+		topic, phase := room.CurrentTopic, room.CurrentPhase
+		room.Mutex.Unlock()
+		if topic == "" {
+			topic = teamDebate.Topic
+		}
+
+		entry := services.LiveDebate{
+			DebateID:     teamDebate.ID.Hex(),
+			Type:         services.LiveDebateTeam,
+			Topic:        topic,
+			Phase:        phase,
+			Participants: []services.LiveDebateParticipant{},
+			StartedAt:    teamDebate.CreatedAt,
+		}
+		entry.Participants = append(entry.Participants,
+			teamParticipants(teamDebate, teamDebate.Team1ID, teamDebate.Team1Name, teamDebate.Team1Stance, teamDebate.Team1Members)...)
+		entry.Participants = append(entry.Participants,
+			teamParticipants(teamDebate, teamDebate.Team2ID, teamDebate.Team2Name, teamDebate.Team2Stance, teamDebate.Team2Members)...)
+		entry.Rating = services.AverageRating(entry.Participants)
+		debates = append(debates, entry)
+	}
+	return debates, nil
+}
+
+func teamParticipants(teamDebate models.TeamDebate, teamID primitive.ObjectID, teamName, side string, members []models.TeamMember) []services.LiveDebateParticipant {
+	participants := make([]services.LiveDebateParticipant, 0, len(members))
+	for _, member := range members {
+		participants = append(participants, services.LiveDebateParticipant{
+			UserID:   member.UserID.Hex(),
+			Name:     member.DisplayName,
+			Side:     side,
+			TeamID:   teamID.Hex(),
+			TeamName: teamName,
+			Rating:   member.Elo,
+			IsBot:    teamDebate.IsBotTeam(teamID),
+		})
+	}
+	return participants
+}
+
+// sortLiveParticipants puts the for side first and debaters without a side last, then orders
+// debaters by name
+func sortLiveParticipants(participants []services.LiveDebateParticipant) {
+	sideOrder := map[string]int{"for": 0, "against": 1}
+	rank := func(side string) int {
+		if order, ok := sideOrder[side]; ok {
+			return order
+		}
+		return len(sideOrder)
+	}
+	sort.Slice(participants, func(i, j int) bool {
+		if ri, rj := rank(participants[i].Side), rank(participants[j].Side); ri != rj {
+			return ri < rj
+		}
+		return participants[i].Name < participants[j].Name
+	})
+}
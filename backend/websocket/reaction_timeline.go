@@ -0,0 +1,116 @@
+package websocket
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"arguehub/internal/debate"
+	"arguehub/models"
+	"arguehub/services"
+)
+
+// reactionFlushInterval is how often counted reactions are written to the debate's timeline
+const reactionFlushInterval = 5 * time.Second
+
+// Reactions are counted per second in memory and flushed to the debate's timeline in batches, so a
+// burst of reactions costs one write
+var (
+	pendingReactions    = make(map[string]map[int64]map[string]int64) // debateID -> unix second -> reaction -> count
+	pendingReactionsMu  sync.Mutex
+	reactionFlusherOnce sync.Once
+)
+
+// countReaction adds a spectator's reaction to the debate's timeline
+func countReaction(debateID, reaction string, at int64) {
+	if !services.IsTimelineReaction(reaction) {
+		return
+	}
+	reactionFlusherOnce.Do(func() { go runReactionFlusher() })
+
+	pendingReactionsMu.Lock()
+	defer pendingReactionsMu.Unlock()
+	seconds := pendingReactions[debateID]
+	if seconds == nil {
+		seconds = make(map[int64]map[string]int64)
+		pendingReactions[debateID] = seconds
+	}
+	if seconds[at] == nil {
+		seconds[at] = make(map[string]int64)
+	}
+	seconds[at][reaction]++
+}
+
+func runReactionFlusher() {
+	ticker := time.NewTicker(reactionFlushInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		pendingReactionsMu.Lock()
+		debateIDs := make([]string, 0, len(pendingReactions))
+		for debateID := range pendingReactions {
+			debateIDs = append(debateIDs, debateID)
+		}
+		pendingReactionsMu.Unlock()
+
+		for _, debateID := range debateIDs {
+			flushReactions(debateID)
+		}
+	}
+}
+
+// flushReactions writes a debate's counted reactions to its timeline. Counts that fail to save are
+// dropped rather than retried; the heatmap is best effort.
+func flushReactions(debateID string) {
+	pendingReactionsMu.Lock()
+	buckets := pendingReactions[debateID]
+	delete(pendingReactions, debateID)
+	pendingReactionsMu.Unlock()
+
+	if err := services.RecordReactionBuckets(debateID, buckets); err != nil {
+		log.Printf("failed to save reactions for debate %s: %v", debateID, err)
+	}
+}
+
+// recordTimelinePhase marks a phase's start on the debate's timeline
+func recordTimelinePhase(debateID string, payload debate.PhasePayload) {
+	go func() {
+		err := services.RecordTimelinePhase(debateID, models.TimelinePhase{
+			Phase:     payload.Phase,
+			Side:      payload.Side,
+			TeamID:    payload.TeamID,
+			StartedAt: time.Unix(payload.Timestamp, 0),
+		})
+		if err != nil {
+			log.Printf("failed to record phase %s on the timeline of debate %s: %v", payload.Phase, debateID, err)
+		}
+	}()
+}
+
+// recordTimelineSegment adds a finished speech to the debate's timeline
+func recordTimelineSegment(debateID string, payload debate.SpeechPayload) {
+	go func() {
+		err := services.RecordTimelineSegment(debateID, models.TimelineSegment{
+			UserID:   payload.UserID,
+			Username: payload.Username,
+			TeamID:   payload.TeamID,
+			Side:     payload.Side,
+			Phase:    payload.Phase,
+			Text:     payload.Text,
+			IsBot:    payload.IsBot,
+			EndedAt:  time.Unix(payload.Timestamp, 0),
+		})
+		if err != nil {
+			log.Printf("failed to record a speech on the timeline of debate %s: %v", debateID, err)
+		}
+	}()
+}
+
+// finishReactionTimeline saves a closed debate's last reactions and marks its timeline finished
+func finishReactionTimeline(debateID string) {
+	go func() {
+		flushReactions(debateID)
+		if err := services.FinishReactionTimeline(debateID, time.Now()); err != nil {
+			log.Printf("failed to finish the reaction timeline of debate %s: %v", debateID, err)
+		}
+	}()
+}
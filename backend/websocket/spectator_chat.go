@@ -0,0 +1,143 @@
+package websocket
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"arguehub/internal/debate"
+	"arguehub/models"
+	"arguehub/services"
+
+	"github.com/gorilla/websocket"
+)
+
+// minChatInterval keeps spectators to one chat message a second when slow mode is off
+const minChatInterval = time.Second
+
+// handleSpectatorChat posts a spectator's chat message, unless the chat is off, they need to log in,
+// they are muted or banned, or slow mode makes them wait
+func handleSpectatorChat(client *SpectatorClient, payloadBytes []byte) {
+	var payload struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(payloadBytes, &payload); err != nil {
+		return
+	}
+
+	settings := spectatorSettings(client.debateID)
+	if settings.ChatDisabled {
+		rejectSpectatorAction(client, "chat_rejected", "The chat is turned off for this debate")
+		return
+	}
+	if settings.RequireLoginToChat && !client.authenticated() {
+		rejectSpectatorAction(client, "chat_rejected", "Log in to chat in this debate")
+		return
+	}
+
+	sanction, err := services.ActiveSpectatorSanction(client.debateID, client.spectatorHash, client.ipHash, !client.authenticated())
+	if err != nil {
+		rejectSpectatorAction(client, "chat_rejected", "The chat is unavailable right now")
+		return
+	}
+	if sanction != nil {
+		reason := "You are muted in this debate's chat"
+		if sanction.Kind == models.SpectatorChatBan {
+			reason = "You are banned from this debate"
+		}
+		client.WriteJSON(map[string]interface{}{
+			"type":      "chat_rejected",
+			"payload":   map[string]interface{}{"error": reason, "sanction": sanction},
+			"timestamp": time.Now().Unix(),
+		})
+		return
+	}
+
+	interval := time.Duration(settings.ChatSlowModeSeconds) * time.Second
+	if interval < minChatInterval {
+		interval = minChatInterval
+	}
+	allowed, wait, err := debate.NewRateLimiter().AllowChatMessage(client.debateID, client.spectatorHash, interval)
+	if err != nil {
+		return
+	}
+	if !allowed {
+		client.WriteJSON(map[string]interface{}{
+			"type": "chat_rejected",
+			"payload": map[string]interface{}{
+				"error":        "Slow down: wait before sending another message",
+				"retryAfterMs": wait.Milliseconds(),
+			},
+			"timestamp": time.Now().Unix(),
+		})
+		return
+	}
+
+	sender := services.SpectatorChatSender{
+		SpectatorHash: client.spectatorHash,
+		IPHash:        client.ipHash,
+		UserID:        client.userID,
+		Username:      client.username,
+	}
+	if _, err := services.PostSpectatorChatMessage(client.debateID, sender, payload.Content, settings.ChatBlockedWords); err != nil {
+		if errors.Is(err, services.ErrInvalidSpectatorChat) {
+			rejectSpectatorAction(client, "chat_rejected", err.Error())
+		} else {
+			rejectSpectatorAction(client, "chat_rejected", "Failed to send your message")
+		}
+	}
+}
+
+// spectatorBanned reports whether a spectator is banned from a debate and can't connect
+func spectatorBanned(debateID string, identity spectatorIdentity) bool {
+	sanction, err := services.ActiveSpectatorSanction(debateID, identity.spectatorHash, identity.ipHash, identity.userID == "")
+	return err == nil && sanction != nil && sanction.Kind == models.SpectatorChatBan
+}
+
+// sendChatHistory sends a joining spectator the latest chat messages
+func (c *SpectatorClient) sendChatHistory() error {
+	messages, err := services.ListSpectatorChatMessages(c.debateID, false)
+	if err != nil {
+		return err
+	}
+	return c.WriteJSON(map[string]interface{}{
+		"type":      "chat_history",
+		"payload":   map[string]interface{}{"messages": messages},
+		"timestamp": time.Now().Unix(),
+	})
+}
+
+// disconnectBanned drops the connections of a spectator banned by a sanction event. Each instance
+// reads the debate's stream through its own consumer group, so every instance gets the event and
+// the spectator is dropped wherever they are connected.
+func (h *DebateHub) disconnectBanned(debateID string, event *debate.Event) {
+	var sanction struct {
+		Kind          string `json:"kind"`
+		SpectatorHash string `json:"spectatorHash"`
+	}
+	if err := json.Unmarshal(event.Payload, &sanction); err != nil || sanction.Kind != models.SpectatorChatBan {
+		return
+	}
+
+	h.mu.RLock()
+	room, exists := h.debates[debateID]
+	h.mu.RUnlock()
+	if !exists {
+		return
+	}
+
+	room.mu.RLock()
+	banned := make([]*SpectatorClient, 0, 1)
+	for _, client := range room.clients {
+		if client.spectatorHash == sanction.SpectatorHash {
+			banned = append(banned, client)
+		}
+	}
+	room.mu.RUnlock()
+
+	closing := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, "banned")
+	for _, client := range banned {
+		client.conn.WriteControl(websocket.CloseMessage, closing, time.Now().Add(spectatorWriteWait))
+		client.conn.Close()
+	}
+}
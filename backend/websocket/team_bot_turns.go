@@ -0,0 +1,166 @@
+package websocket
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"arguehub/internal/debate"
+	"arguehub/models"
+	"arguehub/services"
+)
+
+// teamBotSpeechPause gives the human team time to read a bot speech before the debate moves on
+var teamBotSpeechPause = 10 * time.Second
+
+// hasBotTeam reports whether team 2 in this room is played by bot personalities
+func (room *TeamRoom) hasBotTeam() bool {
+	return !room.BotTeamID.IsZero()
+}
+
+// oppositeRole returns the other debate side
+func oppositeRole(role string) string {
+	if strings.EqualFold(role, "for") {
+		return "against"
+	}
+	return "for"
+}
+
+// maybeStartBotTurn starts the bot team's speech when the room has entered one of its phases
+func maybeStartBotTurn(room *TeamRoom, roomKey string) {
+	if !room.hasBotTeam() {
+		return
+	}
+
+	room.Mutex.Lock()
+	phase := room.CurrentPhase
+	side := services.PhaseSide(room.Format, phase)
+	if side == "" || !strings.EqualFold(side, room.Team2Role) || room.botPhase == phase {
+		room.Mutex.Unlock()
+		return
+	}
+	room.botPhase = phase
+	room.Mutex.Unlock()
+
+	go runBotTurn(room, roomKey, phase)
+}
+
+// runBotTurn has the bot whose turn it is deliver a speech for the phase, then moves the debate on
+func runBotTurn(room *TeamRoom, roomKey, phase string) {
+	speakerID := room.TurnManager.GetCurrentTurn(room.BotTeamID)
+	var speaker *models.TeamMember
+	for i := range room.BotMembers {
+		if room.BotMembers[i].UserID == speakerID {
+			speaker = &room.BotMembers[i]
+			break
+		}
+	}
+	if speaker == nil {
+		log.Printf("[runBotTurn] No bot speaker found for team %s in room %s", room.BotTeamID.Hex(), roomKey)
+		return
+	}
+
+	room.Mutex.Lock()
+	topic := room.CurrentTopic
+	stance := room.Team2Role
+	history := append([]models.Message(nil), room.History...)
+	room.Mutex.Unlock()
+
+	setBotSpeaking(room, speaker, true)
+	text, citations := services.GenerateTeamBotSpeech(speaker.DisplayName, topic, room.Format, stance, phase, history)
+	setBotSpeaking(room, speaker, false)
+
+	if !teamRoomActive(roomKey, room) {
+		return
+	}
+
+	room.Mutex.Lock()
+	room.History = append(room.History, models.Message{Sender: "Bot", Text: text, Phase: phase, Citations: citations})
+	room.Mutex.Unlock()
+	room.TurnManager.RecordContribution(room.BotTeamID, speaker.UserID, phase, text)
+
+	broadcastAll(room, map[string]interface{}{
+		"type":       "speechText",
+		"userId":     speaker.UserID.Hex(),
+		"username":   speaker.DisplayName,
+		"speechText": text,
+		"phase":      phase,
+		"teamId":     room.BotTeamID.Hex(),
+		"isBot":      true,
+		"citations":  citations,
+	})
+	publishSpeechFeed(roomKey, debate.SpeechPayload{
+		UserID:   speaker.UserID.Hex(),
+		Username: speaker.DisplayName,
+		TeamID:   room.BotTeamID.Hex(),
+		Side:     stance,
+		Phase:    phase,
+		Text:     text,
+		IsBot:    true,
+	})
+
+	err := services.RecordTeamDebateSpeech(room.DebateID, models.TeamDebateSpeech{
+		TeamID:      room.BotTeamID,
+		UserID:      speaker.UserID,
+		DisplayName: speaker.DisplayName,
+		IsBot:       true,
+		Phase:       phase,
+		Text:        text,
+		Citations:   citations,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to record bot speech for debate %s: %v", room.DebateID.Hex(), err)
+	}
+	room.TurnManager.NextTurn(room.BotTeamID)
+
+	time.Sleep(teamBotSpeechPause)
+	advanceAfterBotTurn(room, roomKey, phase)
+}
+
+// advanceAfterBotTurn moves the room to the next phase unless the humans already moved on
+func advanceAfterBotTurn(room *TeamRoom, roomKey, phase string) {
+	// Hold the phase while a captain's timeout runs
+	for {
+		room.Mutex.Lock()
+		remaining := time.Until(room.TimeoutUntil)
+		room.Mutex.Unlock()
+		if remaining <= 0 {
+			break
+		}
+		time.Sleep(remaining)
+	}
+	if !teamRoomActive(roomKey, room) {
+		return
+	}
+
+	room.Mutex.Lock()
+	if room.CurrentPhase != phase {
+		room.Mutex.Unlock()
+		return
+	}
+	version := room.StateVersion
+	room.Mutex.Unlock()
+
+	if err := changeTeamPhase(room, roomKey, services.NextPhase(room.Format, phase), version); err != nil {
+		log.Printf("failed to advance debate %s after the bot turn: %v", roomKey, err)
+	}
+}
+
+// setBotSpeaking broadcasts the speaking indicator for a bot while its speech is being generated
+func setBotSpeaking(room *TeamRoom, speaker *models.TeamMember, speaking bool) {
+	broadcastAll(room, map[string]interface{}{
+		"type":       "speakingIndicator",
+		"userId":     speaker.UserID.Hex(),
+		"username":   speaker.DisplayName,
+		"isSpeaking": speaking,
+		"teamId":     room.BotTeamID.Hex(),
+	})
+}
+
+// teamRoomActive reports whether the room is still the live room for its key
+func teamRoomActive(roomKey string, room *TeamRoom) bool {
+	teamRoomsMutex.Lock()
+	defer teamRoomsMutex.Unlock()
+	return teamRooms[roomKey] == room
+}
@@ -0,0 +1,43 @@
+package websocket
+
+import (
+	"testing"
+
+	"arguehub/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBotTeamSpeaksInItsSidesPhases(t *testing.T) {
+	want := map[string][]string{
+		"standard":  {"openingAgainst", "crossAgainstAnswer", "crossAgainstQuestion", "closingAgainst"},
+		"lightning": {"openingAgainst", "closingAgainst"},
+	}
+	for format, phases := range want {
+		room := &TeamRoom{
+			Format:      format,
+			Team2Role:   "against",
+			BotTeamID:   primitive.NewObjectID(),
+			TurnManager: services.NewTeamTurnManager(),
+		}
+
+		var spoke []string
+		for phase := services.NextPhase(format, "setup"); phase != "finished"; phase = services.NextPhase(format, phase) {
+			room.CurrentPhase = phase
+			maybeStartBotTurn(room, "bot-turns-"+format)
+			if room.botPhase == phase {
+				spoke = append(spoke, phase)
+			}
+		}
+
+		if len(spoke) != len(phases) {
+			t.Fatalf("%s: bot team spoke in %v, want %v", format, spoke, phases)
+		}
+		for i := range phases {
+			if spoke[i] != phases[i] {
+				t.Errorf("%s: bot team spoke in %v, want %v", format, spoke, phases)
+				break
+			}
+		}
+	}
+}
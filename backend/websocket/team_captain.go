@@ -0,0 +1,217 @@
+package websocket
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"arguehub/models"
+	"arguehub/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// maxTeamTimeouts is how many timeouts each team may call in a debate
+	maxTeamTimeouts = 2
+	// teamTimeoutDuration is how long a timeout holds the debate
+	teamTimeoutDuration = 60 * time.Second
+)
+
+// requireCaptain checks the client is the captain or a co-captain of their team and reports an
+// error to them otherwise
+func requireCaptain(client *TeamClient, command string) bool {
+	isCaptain, err := services.HasTeamPermission(client.TeamID, client.UserID, services.TeamPermLeadDebate)
+	if err != nil {
+		log.Printf("[requireCaptain] Failed to load team %s: %v", client.TeamID.Hex(), err)
+		sendCaptainError(client, command, "Failed to verify team captain")
+		return false
+	}
+	if !isCaptain {
+		sendCaptainError(client, command, "Only the team captain or a co-captain can do this")
+		return false
+	}
+	return true
+}
+
+// sendCaptainError reports a rejected captain command to the captain who sent it
+func sendCaptainError(client *TeamClient, command, reason string) {
+	client.SafeWriteJSON(map[string]interface{}{
+		"type":    "captainError",
+		"command": command,
+		"error":   reason,
+	})
+}
+
+// teamRole returns the side a team argues in the room
+func teamRole(room *TeamRoom, teamID primitive.ObjectID) string {
+	room.Mutex.Lock()
+	defer room.Mutex.Unlock()
+	if teamID == room.Team1ID {
+		return room.Team1Role
+	}
+	return room.Team2Role
+}
+
+// handleSetSpeakingOrder lets a captain choose who speaks, and in which order, during one of
+// their team's phases. An order for the phase in progress takes effect immediately.
+func handleSetSpeakingOrder(room *TeamRoom, message TeamMessage, client *TeamClient, roomKey string) {
+	const command = "setSpeakingOrder"
+	if !requireCaptain(client, command) {
+		return
+	}
+
+	side := services.PhaseSide(room.Format, message.Phase)
+	if side == "" {
+		sendCaptainError(client, command, "Speaking orders can only be set for speaking phases")
+		return
+	}
+	if role := teamRole(room, client.TeamID); role != "" && !strings.EqualFold(role, side) {
+		sendCaptainError(client, command, "Your team does not speak in this phase")
+		return
+	}
+
+	order := make([]primitive.ObjectID, 0, len(message.Order))
+	for _, hex := range message.Order {
+		userID, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			sendCaptainError(client, command, "Invalid user ID in speaking order")
+			return
+		}
+		order = append(order, userID)
+	}
+	if err := room.TurnManager.SetPhaseOrder(client.TeamID, message.Phase, order); err != nil {
+		sendCaptainError(client, command, err.Error())
+		return
+	}
+
+	room.Mutex.Lock()
+	currentPhase := room.CurrentPhase
+	room.Mutex.Unlock()
+	if currentPhase == message.Phase {
+		room.TurnManager.StartPhase(client.TeamID, message.Phase)
+		syncTeamSpeaker(room, roomKey)
+	}
+
+	broadcastAll(room, map[string]interface{}{
+		"type":   "speakingOrder",
+		"teamId": client.TeamID.Hex(),
+		"phase":  message.Phase,
+		"order":  message.Order,
+	})
+	broadcastTeamStatus(room, client.TeamID, roomKey)
+}
+
+// handleTeamSubstitution lets a captain replace a disconnected member with a connected teammate
+func handleTeamSubstitution(room *TeamRoom, message TeamMessage, client *TeamClient, roomKey string) {
+	const command = "substitute"
+	if !requireCaptain(client, command) {
+		return
+	}
+
+	outUserID, outErr := primitive.ObjectIDFromHex(message.OutUserID)
+	inUserID, inErr := primitive.ObjectIDFromHex(message.InUserID)
+	if outErr != nil || inErr != nil {
+		sendCaptainError(client, command, "Invalid user ID")
+		return
+	}
+	if findClientByUserID(room, message.OutUserID) != nil {
+		sendCaptainError(client, command, "Only disconnected members can be substituted")
+		return
+	}
+	incoming := findClientByUserID(room, message.InUserID)
+	if incoming == nil || incoming.TeamID != client.TeamID {
+		sendCaptainError(client, command, "The substitute must be a connected member of your team")
+		return
+	}
+	if err := room.TurnManager.Substitute(client.TeamID, outUserID, inUserID); err != nil {
+		sendCaptainError(client, command, err.Error())
+		return
+	}
+
+	room.Mutex.Lock()
+	phase := room.CurrentPhase
+	room.Mutex.Unlock()
+
+	err := services.RecordTeamSubstitution(room.DebateID, models.TeamSubstitution{
+		TeamID:    client.TeamID,
+		OutUserID: outUserID,
+		InUserID:  inUserID,
+		Phase:     phase,
+		Timestamp: time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to record substitution for debate %s: %v", room.DebateID.Hex(), err)
+	}
+
+	broadcastAll(room, map[string]interface{}{
+		"type":      "substitution",
+		"teamId":    client.TeamID.Hex(),
+		"outUserId": message.OutUserID,
+		"inUserId":  message.InUserID,
+		"username":  incoming.Username,
+		"phase":     phase,
+	})
+	syncTeamSpeaker(room, roomKey)
+	broadcastTeamStatus(room, client.TeamID, roomKey)
+}
+
+// handleTeamTimeout lets a captain pause the debate. Phase changes are held until the timeout ends.
+func handleTeamTimeout(room *TeamRoom, client *TeamClient, roomKey string) {
+	const command = "callTimeout"
+	if !requireCaptain(client, command) {
+		return
+	}
+
+	teamKey := client.TeamID.Hex()
+	room.Mutex.Lock()
+	if services.PhaseSide(room.Format, room.CurrentPhase) == "" {
+		room.Mutex.Unlock()
+		sendCaptainError(client, command, "Timeouts can only be called while the debate is running")
+		return
+	}
+	if time.Now().Before(room.TimeoutUntil) {
+		room.Mutex.Unlock()
+		sendCaptainError(client, command, "A timeout is already in progress")
+		return
+	}
+	if room.TimeoutsUsed[teamKey] >= maxTeamTimeouts {
+		room.Mutex.Unlock()
+		sendCaptainError(client, command, "Your team has no timeouts left")
+		return
+	}
+	room.TimeoutsUsed[teamKey]++
+	remaining := maxTeamTimeouts - room.TimeoutsUsed[teamKey]
+	endsAt := time.Now().Add(teamTimeoutDuration)
+	room.TimeoutUntil = endsAt
+	room.Mutex.Unlock()
+
+	if err := services.RecordTeamTimeout(room.DebateID, client.TeamID); err != nil {
+		log.Printf("failed to record timeout for debate %s: %v", room.DebateID.Hex(), err)
+	}
+
+	broadcastAll(room, map[string]interface{}{
+		"type":              "timeoutStarted",
+		"teamId":            teamKey,
+		"username":          client.Username,
+		"duration":          int(teamTimeoutDuration.Seconds()),
+		"timeoutsRemaining": remaining,
+		"endsAt":            endsAt.UnixMilli(),
+	})
+
+	go func() {
+		time.Sleep(teamTimeoutDuration)
+		if !teamRoomActive(roomKey, room) {
+			return
+		}
+		room.Mutex.Lock()
+		current := room.TimeoutUntil.Equal(endsAt)
+		room.Mutex.Unlock()
+		if current {
+			broadcastAll(room, map[string]interface{}{
+				"type":   "timeoutEnded",
+				"teamId": teamKey,
+			})
+		}
+	}()
+}
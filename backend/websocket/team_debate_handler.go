@@ -58,19 +58,19 @@ func TeamDebateHubRun() {
 		select {
 		case client := <-teamDebateHub.register:
 			room := teamDebateHub.debates[client.debateID]
-			if room == nil {
-				// Load debate from database
-				collection := db.GetCollection("team_debates")
-				var debate models.TeamDebate
-				err := collection.FindOne(context.Background(), bson.M{"_id": client.debateID}).Decode(&debate)
-				if err == nil {
+			// Reload the debate on every join so its turn state matches what the live room stored
+			collection := db.GetCollection("team_debates")
+			var debate models.TeamDebate
+			err := collection.FindOne(context.Background(), bson.M{"_id": client.debateID}).Decode(&debate)
+			if err == nil {
+				if room == nil {
 					room = &TeamDebateRoom{
-						debate:       debate,
 						team1Clients: make(map[*TeamDebateClient]bool),
 						team2Clients: make(map[*TeamDebateClient]bool),
 					}
 					teamDebateHub.debates[client.debateID] = room
 				}
+				room.debate = debate
 			}
 
 			if room != nil {
@@ -0,0 +1,145 @@
+package websocket
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"arguehub/internal/debate"
+	"arguehub/models"
+	"arguehub/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ApplyTeamDebateState brings the live room for a debate in line with a committed turn state change,
+// including changes made through the REST API
+func ApplyTeamDebateState(state models.TeamDebateState) {
+	roomKey := state.DebateID.Hex()
+	teamRoomsMutex.Lock()
+	room := teamRooms[roomKey]
+	teamRoomsMutex.Unlock()
+	if room != nil {
+		applyTeamDebateState(room, roomKey, state)
+	}
+}
+
+// TeamSpeakingTimes returns the seconds each member has spoken in the live room for a debate, or nil
+// if the debate has no room on this instance
+func TeamSpeakingTimes(debateID primitive.ObjectID) map[string]int {
+	teamRoomsMutex.Lock()
+	room := teamRooms[debateID.Hex()]
+	teamRoomsMutex.Unlock()
+	if room == nil || room.TokenBucket == nil {
+		return nil
+	}
+	return room.TokenBucket.SpeakingTimes()
+}
+
+// applyTeamDebateState moves a room to a stored state. States the room has already seen are ignored.
+func applyTeamDebateState(room *TeamRoom, roomKey string, state models.TeamDebateState) {
+	room.Mutex.Lock()
+	if state.Version <= room.StateVersion {
+		room.Mutex.Unlock()
+		return
+	}
+	phaseChanged := room.CurrentPhase != state.Phase
+	room.StateVersion = state.Version
+	room.CurrentPhase = state.Phase
+	room.Mutex.Unlock()
+
+	if phaseChanged {
+		broadcastAll(room, TeamMessage{
+			Type:    "phaseChange",
+			Phase:   state.Phase,
+			Version: state.Version,
+		})
+		phaseFeed := debate.PhasePayload{Phase: state.Phase, Side: services.PhaseSide(room.Format, state.Phase)}
+		if teamID, ok := speakingTeam(room, state.Phase); ok {
+			phaseFeed.TeamID = teamID.Hex()
+		}
+		firstReport := publishPhaseFeed(roomKey, phaseFeed)
+		trackAudienceDecision(roomKey, room.Format, state.Phase)
+		if firstReport {
+			pushAudienceQuestions(roomKey, room.Format, state.Phase, func(payload map[string]interface{}) {
+				broadcastAll(room, payload)
+			})
+		}
+		onTeamPhaseStarted(room, roomKey)
+		return
+	}
+
+	if teamID, ok := speakingTeam(room, state.Phase); ok && !state.CurrentUserID.IsZero() {
+		if room.TurnManager.GetCurrentTurn(teamID) != state.CurrentUserID && room.TurnManager.SetCurrentTurn(teamID, state.CurrentUserID) {
+			broadcastTeamStatus(room, teamID, roomKey)
+		}
+	}
+}
+
+// restoreTeamDebateState resumes a new room from the debate's stored turn state, so a debate picks
+// up where it was after a restart
+func restoreTeamDebateState(room *TeamRoom, debate models.TeamDebate) {
+	state := debate.State()
+	room.CurrentPhase = state.Phase
+	room.StateVersion = state.Version
+	if teamID, ok := speakingTeam(room, state.Phase); ok && !state.CurrentUserID.IsZero() {
+		room.TurnManager.SetCurrentTurn(teamID, state.CurrentUserID)
+	}
+}
+
+// changeTeamPhase commits a phase change made in the room. expectedVersion is the state version the
+// change was based on; if the state moved on since, the room catches up and the change is refused.
+func changeTeamPhase(room *TeamRoom, roomKey, phase string, expectedVersion int) error {
+	state, err := services.AdvanceTeamDebatePhase(room.DebateID, expectedVersion, phase)
+	if state != nil {
+		applyTeamDebateState(room, roomKey, *state)
+	}
+	return err
+}
+
+// leadTeamPhase commits a phase change a captain asked for in the room, refusing it unless the user
+// leads one of the teams
+func leadTeamPhase(room *TeamRoom, roomKey string, userID primitive.ObjectID, phase string, expectedVersion int) error {
+	state, err := services.LeadTeamDebatePhase(room.DebateID, userID, expectedVersion, phase)
+	if state != nil {
+		applyTeamDebateState(room, roomKey, *state)
+	}
+	return err
+}
+
+// syncTeamSpeaker records who holds the floor in the current phase
+func syncTeamSpeaker(room *TeamRoom, roomKey string) {
+	room.Mutex.Lock()
+	phase := room.CurrentPhase
+	version := room.StateVersion
+	room.Mutex.Unlock()
+
+	teamID, ok := speakingTeam(room, phase)
+	if !ok {
+		return
+	}
+	speaker := room.TurnManager.GetCurrentTurn(teamID)
+	if speaker.IsZero() {
+		return
+	}
+
+	state, err := services.SetTeamDebateSpeaker(room.DebateID, version, speaker)
+	if state != nil {
+		applyTeamDebateState(room, roomKey, *state)
+	}
+	if err != nil && !errors.Is(err, services.ErrTeamDebateNotActive) {
+		log.Printf("failed to record the speaker for debate %s: %v", roomKey, err)
+	}
+}
+
+// speakingTeam returns the team arguing the side that speaks in a phase
+func speakingTeam(room *TeamRoom, phase string) (primitive.ObjectID, bool) {
+	side := services.PhaseSide(room.Format, phase)
+	if side == "" {
+		return primitive.NilObjectID, false
+	}
+	if strings.EqualFold(teamRole(room, room.Team1ID), side) {
+		return room.Team1ID, true
+	}
+	return room.Team2ID, true
+}
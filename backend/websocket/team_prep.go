@@ -0,0 +1,133 @@
+package websocket
+
+import (
+	"errors"
+	"log"
+
+	"arguehub/models"
+	"arguehub/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// prepHistoryLimit is how many prep channel messages a member receives when joining
+const prepHistoryLimit = 100
+
+// broadcastToTeam sends a message to the connected members of one team only
+func broadcastToTeam(room *TeamRoom, teamID primitive.ObjectID, roomKey string, payload any) {
+	for _, r := range snapshotTeamRecipients(room, nil) {
+		if r.TeamID == teamID {
+			if err := r.SafeWriteJSON(payload); err != nil {
+				log.Printf("Team WebSocket write error in room %s: %v", roomKey, err)
+			}
+		}
+	}
+}
+
+// sendPrepError reports a rejected prep room action to the member who sent it
+func sendPrepError(client *TeamClient, action, reason string) {
+	client.SafeWriteJSON(map[string]interface{}{
+		"type":   "prepError",
+		"action": action,
+		"error":  reason,
+	})
+}
+
+// sendTeamPrepState sends the team's prep room and recent prep messages to a member who just joined
+func sendTeamPrepState(room *TeamRoom, client *TeamClient) {
+	prep, err := services.GetTeamPrepRoom(room.DebateID, client.TeamID)
+	if err != nil {
+		log.Printf("failed to load prep room for team %s: %v", client.TeamID.Hex(), err)
+		return
+	}
+	messages, err := services.GetTeamPrepMessages(room.DebateID, client.TeamID, prepHistoryLimit)
+	if err != nil {
+		log.Printf("failed to load prep messages for team %s: %v", client.TeamID.Hex(), err)
+		messages = []models.TeamChatMessage{}
+	}
+	client.SafeWriteJSON(map[string]interface{}{
+		"type":     "prepState",
+		"prep":     prep,
+		"messages": messages,
+	})
+}
+
+// handlePrepMessage stores a message in the team's prep channel and relays it to teammates
+func handlePrepMessage(room *TeamRoom, message TeamMessage, client *TeamClient, roomKey string) {
+	saved, err := services.AddTeamPrepMessage(models.TeamChatMessage{
+		TeamID:      client.TeamID,
+		DebateID:    room.DebateID,
+		UserID:      client.UserID,
+		Email:       client.Email,
+		DisplayName: client.Username,
+		Message:     message.Content,
+	})
+	if err != nil {
+		sendPrepError(client, "prepMessage", err.Error())
+		return
+	}
+	broadcastToTeam(room, client.TeamID, roomKey, map[string]interface{}{
+		"type":    "prepMessage",
+		"message": saved,
+	})
+}
+
+// handlePrepNotes saves the team's shared notes. Edits are last-writer-wins, but an edit made
+// against an outdated version is rejected and the editor gets the current notes back.
+func handlePrepNotes(room *TeamRoom, message TeamMessage, client *TeamClient, roomKey string) {
+	prep, err := services.UpdateTeamPrepNotes(room.DebateID, client.TeamID, client.UserID, message.Version, message.Content)
+	if errors.Is(err, services.ErrPrepNotesConflict) {
+		client.SafeWriteJSON(map[string]interface{}{
+			"type":  "prepNotesConflict",
+			"error": err.Error(),
+			"prep":  prep,
+		})
+		return
+	}
+	if err != nil {
+		sendPrepError(client, "prepNotes", err.Error())
+		return
+	}
+	broadcastToTeam(room, client.TeamID, roomKey, map[string]interface{}{
+		"type":      "prepNotes",
+		"notes":     prep.Notes,
+		"version":   prep.NotesVersion,
+		"updatedBy": client.UserID.Hex(),
+		"username":  client.Username,
+	})
+}
+
+// handlePinArgument pins an argument in the team's prep room
+func handlePinArgument(room *TeamRoom, message TeamMessage, client *TeamClient, roomKey string) {
+	pin, err := services.PinTeamArgument(room.DebateID, client.TeamID, models.TeamPinnedArgument{
+		UserID:      client.UserID,
+		DisplayName: client.Username,
+		Text:        message.Content,
+		Phase:       message.Phase,
+	})
+	if err != nil {
+		sendPrepError(client, "pinArgument", err.Error())
+		return
+	}
+	broadcastToTeam(room, client.TeamID, roomKey, map[string]interface{}{
+		"type": "argumentPinned",
+		"pin":  pin,
+	})
+}
+
+// handleUnpinArgument removes a pinned argument from the team's prep room
+func handleUnpinArgument(room *TeamRoom, message TeamMessage, client *TeamClient, roomKey string) {
+	pinID, err := primitive.ObjectIDFromHex(message.PinID)
+	if err != nil {
+		sendPrepError(client, "unpinArgument", "Invalid pin ID")
+		return
+	}
+	if err := services.UnpinTeamArgument(room.DebateID, client.TeamID, pinID); err != nil {
+		sendPrepError(client, "unpinArgument", err.Error())
+		return
+	}
+	broadcastToTeam(room, client.TeamID, roomKey, map[string]interface{}{
+		"type":  "argumentUnpinned",
+		"pinId": message.PinID,
+	})
+}
@@ -0,0 +1,177 @@
+package websocket
+
+import (
+	"strings"
+	"time"
+
+	"arguehub/models"
+	"arguehub/services"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// speakingClockInterval is how often speaking members are charged and their remaining time broadcast
+	speakingClockInterval = time.Second
+	// speechIdleTimeout is how long a member can go without a speaking or transcript event before
+	// their speaking clock stops
+	speechIdleTimeout = 4 * time.Second
+)
+
+// configureSpeakingBudgets sizes each team's speaking budgets from the debate format and lineups
+func configureSpeakingBudgets(debate models.TeamDebate, turnManager *services.TeamTurnManager, tokenBucket *services.TokenBucketService) {
+	budget1, budget2 := services.TeamSpeakingBudgets(debate.Format,
+		debate.Team1Stance, len(turnManager.GetLineup(debate.Team1ID)),
+		debate.Team2Stance, len(turnManager.GetLineup(debate.Team2ID)))
+	tokenBucket.SetTeamBudget(debate.Team1ID, budget1)
+	tokenBucket.SetTeamBudget(debate.Team2ID, budget2)
+}
+
+// runTeamSpeakingClock charges speaking members once a second for as long as the room is open
+func runTeamSpeakingClock(room *TeamRoom, roomKey string) {
+	ticker := time.NewTicker(speakingClockInterval)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		if !teamRoomActive(roomKey, room) {
+			return
+		}
+		for _, client := range snapshotTeamRecipients(room, nil) {
+			room.Mutex.Lock()
+			speaking := client.IsSpeaking && !client.IsMuted
+			lastSpeech := client.LastSpeech
+			idle := speaking && now.Sub(lastSpeech) > speechIdleTimeout
+			if idle {
+				client.IsSpeaking = false
+			}
+			room.Mutex.Unlock()
+			if !speaking {
+				continue
+			}
+			if idle {
+				stopSpeakingClock(room, client, lastSpeech, roomKey)
+				continue
+			}
+			remaining := room.TokenBucket.ChargeSpeaking(client.TeamID, client.UserID, now)
+			broadcastSpeakingTime(room, client, remaining, roomKey)
+			if remaining <= 0 {
+				autoMuteTeamMember(room, client, roomKey)
+			}
+		}
+	}
+}
+
+// noteSpeechActivity records that a member is speaking and starts their clock if it is not running.
+// It returns false, after telling the member, when they have been muted for running out of time.
+func noteSpeechActivity(room *TeamRoom, client *TeamClient, roomKey string) bool {
+	room.Mutex.Lock()
+	muted := client.IsMuted
+	room.Mutex.Unlock()
+	if muted {
+		client.SafeWriteJSON(map[string]interface{}{
+			"type":    "autoMuted",
+			"userId":  client.UserID.Hex(),
+			"teamId":  client.TeamID.Hex(),
+			"isMuted": true,
+			"reason":  "Speaking time used up",
+		})
+		return false
+	}
+	if !startSpeakingClock(room, client) {
+		autoMuteTeamMember(room, client, roomKey)
+		return false
+	}
+
+	room.Mutex.Lock()
+	client.IsSpeaking = true
+	client.LastSpeech = time.Now()
+	room.Mutex.Unlock()
+	return true
+}
+
+// startSpeakingClock starts charging a member who began speaking during one of their team's
+// phases. It returns false when the member has no speaking time left.
+func startSpeakingClock(room *TeamRoom, client *TeamClient) bool {
+	room.Mutex.Lock()
+	phase := room.CurrentPhase
+	room.Mutex.Unlock()
+
+	side := services.PhaseSide(room.Format, phase)
+	if side == "" || !strings.EqualFold(teamRole(room, client.TeamID), side) {
+		return true
+	}
+	return room.TokenBucket.StartSpeaking(client.TeamID, client.UserID, time.Now())
+}
+
+// stopSpeakingClock stops charging a member at the given time and tells their team how much time
+// they have left
+func stopSpeakingClock(room *TeamRoom, client *TeamClient, at time.Time, roomKey string) {
+	remaining := room.TokenBucket.StopSpeaking(client.TeamID, client.UserID, at)
+	broadcastSpeakingTime(room, client, remaining, roomKey)
+}
+
+// pauseOffPhaseSpeakers stops the clock of members still speaking once their team's phase is over.
+// Their next speaking event starts it again if they speak in a later phase.
+func pauseOffPhaseSpeakers(room *TeamRoom, roomKey string) {
+	room.Mutex.Lock()
+	side := services.PhaseSide(room.Format, room.CurrentPhase)
+	room.Mutex.Unlock()
+
+	for _, client := range snapshotTeamRecipients(room, nil) {
+		if side != "" && strings.EqualFold(teamRole(room, client.TeamID), side) {
+			continue
+		}
+		room.Mutex.Lock()
+		speaking := client.IsSpeaking
+		client.IsSpeaking = false
+		room.Mutex.Unlock()
+		if speaking {
+			stopSpeakingClock(room, client, time.Now(), roomKey)
+		}
+	}
+}
+
+// broadcastSpeakingTime tells a member's team how much speaking time the member has left
+func broadcastSpeakingTime(room *TeamRoom, client *TeamClient, remaining int, roomKey string) {
+	room.Mutex.Lock()
+	client.Tokens = remaining
+	room.Mutex.Unlock()
+
+	broadcastToTeam(room, client.TeamID, roomKey, map[string]interface{}{
+		"type":          "speakingTime",
+		"userId":        client.UserID.Hex(),
+		"teamId":        client.TeamID.Hex(),
+		"remaining":     remaining,
+		"budget":        room.TokenBucket.GetCapacity(client.TeamID, client.UserID),
+		"spokenSeconds": room.TokenBucket.GetSpokenSeconds(client.TeamID, client.UserID),
+	})
+}
+
+// autoMuteTeamMember mutes a member who has used up their speaking time for the rest of the debate
+func autoMuteTeamMember(room *TeamRoom, client *TeamClient, roomKey string) {
+	room.Mutex.Lock()
+	if client.IsMuted {
+		room.Mutex.Unlock()
+		return
+	}
+	client.IsMuted = true
+	client.IsSpeaking = false
+	room.Mutex.Unlock()
+
+	room.TokenBucket.StopSpeaking(client.TeamID, client.UserID, time.Now())
+
+	broadcastAll(room, map[string]interface{}{
+		"type":     "autoMuted",
+		"userId":   client.UserID.Hex(),
+		"username": client.Username,
+		"teamId":   client.TeamID.Hex(),
+		"isMuted":  true,
+		"reason":   "Speaking time used up",
+	})
+	broadcastTeamStatus(room, client.TeamID, roomKey)
+}
+
+// isAutoMuted reports whether a member has been muted for running out of speaking time
+func isAutoMuted(room *TeamRoom, userID primitive.ObjectID, teamID primitive.ObjectID) bool {
+	return room.TokenBucket.GetCapacity(teamID, userID) > 0 && room.TokenBucket.GetRemainingTokens(teamID, userID) <= 0
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"arguehub/db"
+	"arguehub/internal/debate"
 	"arguehub/models"
 	"arguehub/services"
 	"arguehub/utils"
@@ -34,10 +35,21 @@ type TeamRoom struct {
 	// Room state for synchronization
 	CurrentTopic string
 	CurrentPhase string
+	Format       string // Debate format, which sets the phase sequence
 	Team1Role    string
 	Team2Role    string
 	Team1Ready   map[string]bool // userId -> ready status
 	Team2Ready   map[string]bool // userId -> ready status
+	// Bot team state, set when team 2 is played by bot personalities
+	BotTeamID  primitive.ObjectID
+	BotMembers []models.TeamMember
+	History    []models.Message // Speeches so far; "Bot" marks the bot team, "User" the human team
+	botPhase   string           // Last phase the bot team started speaking in
+	completing bool             // Set once the debate has been handed off for judging
+	// Captain controls
+	TimeoutsUsed map[string]int // teamId -> timeouts called
+	TimeoutUntil time.Time      // Phase changes are held until then while a timeout runs
+	StateVersion int            // Version of the stored turn state the room is in sync with
 }
 
 // TeamClient represents a connected team member
@@ -52,6 +64,7 @@ type TeamClient struct {
 	IsSpeaking   bool
 	PartialText  string
 	LastActivity time.Time
+	LastSpeech   time.Time // Last speaking or transcript event, used to stop the speaking clock
 	IsMuted      bool
 	Role         string // "for" or "against"
 	SpeechText   string
@@ -101,6 +114,12 @@ type TeamMessage struct {
 	Offer          map[string]any  `json:"offer,omitempty"`
 	Answer         map[string]any  `json:"answer,omitempty"`
 	Candidate      map[string]any  `json:"candidate,omitempty"`
+	Order          []string        `json:"order,omitempty"`     // Speaking order (user IDs) set by a captain
+	OutUserID      string          `json:"outUserId,omitempty"` // Member leaving in a substitution
+	InUserID       string          `json:"inUserId,omitempty"`  // Member coming in in a substitution
+	Version        int             `json:"version,omitempty"`   // Prep notes or turn state version a change is based on
+	PinID          string          `json:"pinId,omitempty"`
+	QuestionID     string          `json:"questionId,omitempty"` // Audience question being answered
 }
 
 var teamRooms = make(map[string]*TeamRoom)
@@ -200,19 +219,16 @@ func TeamWebsocketHandler(c *gin.Context) {
 	turnManager := services.NewTeamTurnManager()
 	tokenBucket := services.NewTokenBucketService()
 
-	// Initialize turn management for both teams
-	turnErr1 := turnManager.InitializeTeamTurns(debate.Team1ID)
-	turnErr2 := turnManager.InitializeTeamTurns(debate.Team2ID)
+	// Initialize turn management and token buckets for both teams
+	teamErr1 := initializeTeamResources(debate, debate.Team1ID, debate.Team1Members, turnManager, tokenBucket)
+	teamErr2 := initializeTeamResources(debate, debate.Team2ID, debate.Team2Members, turnManager, tokenBucket)
 
-	// Initialize token buckets for both teams
-	bucketErr1 := tokenBucket.InitializeTeamBuckets(debate.Team1ID)
-	bucketErr2 := tokenBucket.InitializeTeamBuckets(debate.Team2ID)
-
-	if turnErr1 != nil || turnErr2 != nil || bucketErr1 != nil || bucketErr2 != nil {
-		log.Printf("error initializing room resources: %v %v %v %v", turnErr1, turnErr2, bucketErr1, bucketErr2)
+	if teamErr1 != nil || teamErr2 != nil {
+		log.Printf("error initializing room resources: %v %v", teamErr1, teamErr2)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to initialize room resources"})
 		return
 	}
+	configureSpeakingBudgets(debate, turnManager, tokenBucket)
 
 	preparedRoom := &TeamRoom{
 		Clients:      make(map[*websocket.Conn]*TeamClient),
@@ -223,10 +239,20 @@ func TeamWebsocketHandler(c *gin.Context) {
 		TokenBucket:  tokenBucket,
 		CurrentTopic: debate.Topic,
 		CurrentPhase: "setup",
+		Format:       debate.Format,
 		Team1Role:    debate.Team1Stance,
 		Team2Role:    debate.Team2Stance,
 		Team1Ready:   make(map[string]bool),
 		Team2Ready:   make(map[string]bool),
+		TimeoutsUsed: make(map[string]int),
+	}
+	for teamID, used := range debate.TimeoutsUsed {
+		preparedRoom.TimeoutsUsed[teamID] = used
+	}
+	restoreTeamDebateState(preparedRoom, debate)
+	if debate.Mode == models.TeamDebateModeVsBots {
+		preparedRoom.BotTeamID = debate.Team2ID
+		preparedRoom.BotMembers = debate.Team2Members
 	}
 
 	// Insert room if absent
@@ -235,10 +261,15 @@ func TeamWebsocketHandler(c *gin.Context) {
 	if !exists {
 		teamRooms[roomKey] = preparedRoom
 		room = preparedRoom
+		go runTeamSpeakingClock(room, roomKey)
 	} else {
 		// discard prepared room; existing room will be used
 	}
 	teamRoomsMutex.Unlock()
+	if !exists && debate.Status == "active" && room.CurrentPhase != "setup" {
+		// Resuming a debate that was under way, so pick up its phase again
+		onTeamPhaseStarted(room, roomKey)
+	}
 
 	// Upgrade the connection
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
@@ -272,10 +303,10 @@ func TeamWebsocketHandler(c *gin.Context) {
 		IsSpeaking:   false,
 		PartialText:  "",
 		LastActivity: time.Now(),
-		IsMuted:      false,
+		IsMuted:      isAutoMuted(room, userObjectID, userTeamID),
 		Role:         "",
 		SpeechText:   "",
-		Tokens:       10, // Initial tokens
+		Tokens:       room.TokenBucket.GetRemainingTokens(userTeamID, userObjectID),
 	}
 
 	room.Mutex.Lock()
@@ -294,11 +325,13 @@ func TeamWebsocketHandler(c *gin.Context) {
 		"currentTurn": room.TurnManager.GetCurrentTurn(userTeamID).Hex(),
 		"tokens":      room.TokenBucket.GetRemainingTokens(userTeamID, userObjectID),
 	})
+	sendTeamPrepState(room, client)
 
 	// Send current room state to new joiner
 	room.Mutex.Lock()
 	currentTopic := room.CurrentTopic
 	currentPhase := room.CurrentPhase
+	stateVersion := room.StateVersion
 	team1Role := room.Team1Role
 	team2Role := room.Team2Role
 
@@ -327,6 +360,7 @@ func TeamWebsocketHandler(c *gin.Context) {
 		"type":              "stateSync",
 		"topic":             currentTopic,
 		"phase":             currentPhase,
+		"version":           stateVersion,
 		"team1Role":         team1Role,
 		"team2Role":         team2Role,
 		"team1Ready":        team1ReadyCount,
@@ -359,6 +393,13 @@ func TeamWebsocketHandler(c *gin.Context) {
 				teamRoomsMutex.Lock()
 				delete(teamRooms, roomKey)
 				teamRoomsMutex.Unlock()
+				forgetLiveFeed(roomKey)
+				GetDebateHub().CloseDebateLater(roomKey, func() bool {
+					teamRoomsMutex.Lock()
+					defer teamRoomsMutex.Unlock()
+					_, reopened := teamRooms[roomKey]
+					return reopened
+				})
 			}
 			room.Mutex.Unlock()
 
@@ -423,6 +464,24 @@ func TeamWebsocketHandler(c *gin.Context) {
 			handleTeamWebRTCAnswer(room, client, message)
 		case "candidate":
 			handleTeamWebRTCCandidate(room, client, message)
+		case "setSpeakingOrder":
+			handleSetSpeakingOrder(room, message, client, roomKey)
+		case "substitute":
+			handleTeamSubstitution(room, message, client, roomKey)
+		case "callTimeout":
+			handleTeamTimeout(room, client, roomKey)
+		case "prepMessage":
+			handlePrepMessage(room, message, client, roomKey)
+		case "prepNotes":
+			handlePrepNotes(room, message, client, roomKey)
+		case "pinArgument":
+			handlePinArgument(room, message, client, roomKey)
+		case "unpinArgument":
+			handleUnpinArgument(room, message, client, roomKey)
+		case "questionAnswered":
+			markAudienceQuestionAnswered(roomKey, message.QuestionID, func(payload map[string]interface{}) {
+				broadcastAll(room, payload)
+			})
 		case "leave":
 			handleTeamLeave(room, client, roomKey)
 		default:
@@ -436,6 +495,19 @@ func TeamWebsocketHandler(c *gin.Context) {
 	}
 }
 
+// initializeTeamResources sets up turn order and token buckets for one side of the debate.
+// Bot teams are not stored in the teams collection, so they are set up from the debate record.
+func initializeTeamResources(debate models.TeamDebate, teamID primitive.ObjectID, members []models.TeamMember, turnManager *services.TeamTurnManager, tokenBucket *services.TokenBucketService) error {
+	if debate.IsBotTeam(teamID) {
+		tokenBucket.InitializeMemberBuckets(teamID, members)
+		return turnManager.InitializeTurnOrder(teamID, members)
+	}
+	if err := turnManager.InitializeTeamTurns(teamID); err != nil {
+		return err
+	}
+	return tokenBucket.InitializeTeamBuckets(teamID)
+}
+
 // snapshotTeamRecipients returns a slice of team clients to send messages to, excluding the specified connection
 func snapshotTeamRecipients(room *TeamRoom, exclude *websocket.Conn) []*TeamClient {
 	room.Mutex.Lock()
@@ -572,13 +644,30 @@ func handleTeamDebateMessage(room *TeamRoom, conn *websocket.Conn, message TeamM
 			log.Printf("Team WebSocket write error in room %s: %v", roomKey, err)
 		}
 	}
+
+	publishChatFeed(roomKey, debate.ChatPayload{
+		UserID:    client.UserID.Hex(),
+		Username:  client.Username,
+		TeamID:    client.TeamID.Hex(),
+		Phase:     message.Phase,
+		Content:   message.Content,
+		Timestamp: message.Timestamp,
+	})
+	recordTeamSpeech(room, client, message.Phase, message.Content)
 }
 
 // handleTeamSpeakingIndicator handles speaking indicators
 func handleTeamSpeakingIndicator(room *TeamRoom, conn *websocket.Conn, message TeamMessage, client *TeamClient, roomKey string) {
-	room.Mutex.Lock()
-	client.IsSpeaking = message.IsSpeaking
-	room.Mutex.Unlock()
+	if message.IsSpeaking {
+		if !noteSpeechActivity(room, client, roomKey) {
+			return
+		}
+	} else {
+		room.Mutex.Lock()
+		client.IsSpeaking = false
+		room.Mutex.Unlock()
+		stopSpeakingClock(room, client, time.Now(), roomKey)
+	}
 
 	// Broadcast speaking indicator to all clients
 	for _, r := range snapshotTeamRecipients(room, conn) {
@@ -597,6 +686,10 @@ func handleTeamSpeakingIndicator(room *TeamRoom, conn *websocket.Conn, message T
 
 // handleTeamSpeechText handles speech-to-text conversion
 func handleTeamSpeechText(room *TeamRoom, conn *websocket.Conn, message TeamMessage, client *TeamClient, roomKey string) {
+	if !noteSpeechActivity(room, client, roomKey) {
+		return
+	}
+
 	room.Mutex.Lock()
 	client.SpeechText = message.SpeechText
 	room.Mutex.Unlock()
@@ -615,10 +708,54 @@ func handleTeamSpeechText(room *TeamRoom, conn *websocket.Conn, message TeamMess
 			log.Printf("Team WebSocket write error in room %s: %v", roomKey, err)
 		}
 	}
+
+	publishSpeechFeed(roomKey, debate.SpeechPayload{
+		UserID:   client.UserID.Hex(),
+		Username: client.Username,
+		TeamID:   client.TeamID.Hex(),
+		Side:     teamRole(room, client.TeamID),
+		Phase:    message.Phase,
+		Text:     message.SpeechText,
+	})
+	recordTeamSpeech(room, client, message.Phase, message.SpeechText)
+}
+
+// recordTeamSpeech stores a finished human speech or debate message on the debate record and in the room history
+func recordTeamSpeech(room *TeamRoom, client *TeamClient, phase, text string) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+
+	room.Mutex.Lock()
+	if phase == "" {
+		phase = room.CurrentPhase
+	}
+	if room.hasBotTeam() {
+		room.History = append(room.History, models.Message{Sender: "User", Text: text, Phase: phase})
+	}
+	room.Mutex.Unlock()
+	room.TurnManager.RecordContribution(client.TeamID, client.UserID, phase, text)
+
+	err := services.RecordTeamDebateSpeech(room.DebateID, models.TeamDebateSpeech{
+		TeamID:      client.TeamID,
+		UserID:      client.UserID,
+		DisplayName: client.Username,
+		Phase:       phase,
+		Text:        text,
+		Timestamp:   time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to record speech for debate %s: %v", room.DebateID.Hex(), err)
+	}
 }
 
 // handleTeamLiveTranscript handles live/interim transcript updates
 func handleTeamLiveTranscript(room *TeamRoom, conn *websocket.Conn, message TeamMessage, client *TeamClient, roomKey string) {
+	if !noteSpeechActivity(room, client, roomKey) {
+		return
+	}
+
 	// Broadcast live transcript to all clients
 	for _, r := range snapshotTeamRecipients(room, conn) {
 		response := map[string]interface{}{
@@ -635,35 +772,137 @@ func handleTeamLiveTranscript(room *TeamRoom, conn *websocket.Conn, message Team
 	}
 }
 
-// handleTeamPhaseChange handles phase changes
+// handleTeamPhaseChange moves the debate on when a captain or co-captain asks for the next phase
 func handleTeamPhaseChange(room *TeamRoom, conn *websocket.Conn, message TeamMessage, roomKey string) {
-	// Update room state
 	room.Mutex.Lock()
-	oldPhase := room.CurrentPhase
-	if message.Phase != "" {
-		room.CurrentPhase = message.Phase
-		log.Printf("[handleTeamPhaseChange] Phase changed from %s to %s", oldPhase, room.CurrentPhase)
-	} else {
+	sender := room.Clients[conn]
+	expectedVersion := room.StateVersion
+	if remaining := time.Until(room.TimeoutUntil); remaining > 0 {
+		room.Mutex.Unlock()
+		if sender != nil {
+			sender.SafeWriteJSON(map[string]interface{}{
+				"type":      "phaseChangeRejected",
+				"phase":     message.Phase,
+				"reason":    "A timeout is in progress",
+				"remaining": int(remaining.Seconds()) + 1,
+			})
+		}
+		return
+	}
+	room.Mutex.Unlock()
+
+	if message.Phase == "" {
 		log.Printf("[handleTeamPhaseChange] Received phase change message but Phase is empty")
+		return
 	}
-	currentPhase := room.CurrentPhase
+	if sender == nil {
+		return
+	}
+	// Clients that know the state version base their change on it, so stale changes are refused
+	if message.Version > 0 {
+		expectedVersion = message.Version
+	}
+
+	if err := leadTeamPhase(room, roomKey, sender.UserID, message.Phase, expectedVersion); err != nil {
+		log.Printf("[handleTeamPhaseChange] Phase change to %s refused in room %s: %v", message.Phase, roomKey, err)
+		room.Mutex.Lock()
+		currentPhase, version := room.CurrentPhase, room.StateVersion
+		room.Mutex.Unlock()
+		sender.SafeWriteJSON(map[string]interface{}{
+			"type":         "phaseChangeRejected",
+			"phase":        message.Phase,
+			"reason":       err.Error(),
+			"currentPhase": currentPhase,
+			"version":      version,
+		})
+	}
+}
+
+// onTeamPhaseStarted applies the captains' speaking orders for the new phase, then hands the
+// phase to the bot team or to the judge when it is theirs
+func onTeamPhaseStarted(room *TeamRoom, roomKey string) {
+	room.Mutex.Lock()
+	phase := room.CurrentPhase
 	room.Mutex.Unlock()
 
-	// Broadcast phase change to ALL clients (including sender for sync)
-	phaseMessage := TeamMessage{
-		Type:  "phaseChange",
-		Phase: currentPhase,
+	pauseOffPhaseSpeakers(room, roomKey)
+	if services.PhaseSide(room.Format, phase) != "" {
+		for _, teamID := range []primitive.ObjectID{room.Team1ID, room.Team2ID} {
+			room.TurnManager.StartPhase(teamID, phase)
+			broadcastTeamStatus(room, teamID, roomKey)
+		}
+		syncTeamSpeaker(room, roomKey)
 	}
-	recipients := snapshotTeamRecipients(room, nil)
-	for _, r := range recipients {
-		if err := r.SafeWriteJSON(phaseMessage); err != nil {
-			log.Printf("Team WebSocket write error in room %s: %v", roomKey, err)
-		} else {
-			log.Printf("[handleTeamPhaseChange] ✓ Phase change broadcasted: %s", currentPhase)
+
+	maybeStartBotTurn(room, roomKey)
+	maybeCompleteTeamDebate(room, roomKey)
+}
+
+// broadcastTeamStatus sends a team's speaking status and current speaker to its members. A bot
+// team has no members to tell, nor a team document to load the status from.
+func broadcastTeamStatus(room *TeamRoom, teamID primitive.ObjectID, roomKey string) {
+	if room.hasBotTeam() && teamID == room.BotTeamID {
+		return
+	}
+	teamStatus, statusErr := room.TokenBucket.GetTeamSpeakingStatus(teamID, room.TurnManager)
+	if statusErr != nil {
+		log.Printf("failed to load team status for team %s: %v", teamID.Hex(), statusErr)
+		teamStatus = map[string]interface{}{}
+	}
+	response := map[string]interface{}{
+		"type":        "teamStatus",
+		"teamStatus":  teamStatus,
+		"currentTurn": room.TurnManager.GetCurrentTurn(teamID).Hex(),
+	}
+	for _, r := range snapshotTeamRecipients(room, nil) {
+		if r.TeamID == teamID {
+			if err := r.SafeWriteJSON(response); err != nil {
+				log.Printf("Team WebSocket write error in room %s: %v", roomKey, err)
+			}
 		}
 	}
 }
 
+// maybeCompleteTeamDebate judges the debate once the room reaches the finished phase and
+// broadcasts the outcome to everyone in the room
+func maybeCompleteTeamDebate(room *TeamRoom, roomKey string) {
+	room.Mutex.Lock()
+	if room.CurrentPhase != "finished" || room.completing {
+		room.Mutex.Unlock()
+		return
+	}
+	room.completing = true
+	room.Mutex.Unlock()
+
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				log.Printf("[maybeCompleteTeamDebate] Panic while completing debate %s: %v", roomKey, r)
+				broadcastAll(room, map[string]interface{}{
+					"type":  "debateResultError",
+					"error": "Failed to judge the debate",
+				})
+			}
+		}()
+		debate, err := services.CompleteTeamDebate(room.DebateID)
+		if err != nil {
+			log.Printf("[maybeCompleteTeamDebate] Failed to complete debate %s: %v", roomKey, err)
+			broadcastAll(room, map[string]interface{}{
+				"type":  "debateResultError",
+				"error": "Failed to judge the debate",
+			})
+			return
+		}
+		broadcastAll(room, map[string]interface{}{
+			"type":           "debateResult",
+			"status":         debate.Status,
+			"winner":         debate.Winner,
+			"result":         debate.Result,
+			"speakerResults": debate.SpeakerResults,
+		})
+	}()
+}
+
 // handleTeamTopicChange handles topic changes
 func handleTeamTopicChange(room *TeamRoom, conn *websocket.Conn, message TeamMessage, roomKey string) {
 	// Update room state
@@ -698,6 +937,10 @@ func handleTeamRoleSelection(room *TeamRoom, conn *websocket.Conn, message TeamM
 		if clientTeamIDHex == team1IDHex {
 			room.Team1Role = message.Role
 			log.Printf("[handleTeamRoleSelection] Team1 role set to: %s by user %s", message.Role, client.UserID.Hex())
+			// Bots always take the other side
+			if room.hasBotTeam() {
+				room.Team2Role = oppositeRole(message.Role)
+			}
 		} else if clientTeamIDHex == team2IDHex {
 			room.Team2Role = message.Role
 			log.Printf("[handleTeamRoleSelection] Team2 role set to: %s by user %s", message.Role, client.UserID.Hex())
@@ -843,7 +1086,7 @@ func handleTeamReadyStatus(room *TeamRoom, conn *websocket.Conn, message TeamMes
 
 	// Check if all teams are ready and phase is still setup
 	allTeam1Ready := currentTeam1ReadyCount == currentTeam1MembersCount && currentTeam1MembersCount > 0
-	allTeam2Ready := currentTeam2ReadyCount == currentTeam2MembersCount && currentTeam2MembersCount > 0
+	allTeam2Ready := room.hasBotTeam() || (currentTeam2ReadyCount == currentTeam2MembersCount && currentTeam2MembersCount > 0)
 	allReady := allTeam1Ready && allTeam2Ready
 
 	log.Printf("[handleTeamReadyStatus] Ready check: Team1=%d/%d ready=%v, Team2=%d/%d ready=%v, AllReady=%v, Phase=%s",
@@ -890,26 +1133,24 @@ func handleTeamReadyStatus(room *TeamRoom, conn *websocket.Conn, message TeamMes
 			}
 
 			room.Mutex.Lock()
-			if room.CurrentPhase == "countdown" || room.CurrentPhase == "setup" {
-				room.CurrentPhase = "openingFor"
+			starting := room.CurrentPhase == "countdown" || room.CurrentPhase == "setup"
+			currentPhase, version := room.CurrentPhase, room.StateVersion
+			room.Mutex.Unlock()
 
-				// Broadcast phase change to ALL clients using proper TeamMessage format
-				phaseMessage := TeamMessage{
-					Type:  "phaseChange",
-					Phase: "openingFor",
-				}
-				for _, r := range room.Clients {
-					if err := r.SafeWriteJSON(phaseMessage); err != nil {
-						log.Printf("Team WebSocket write error in room %s: %v", roomKey, err)
-					} else {
-						log.Printf("[handleTeamReadyStatus] ✓ Phase change message sent to client")
-					}
+			if !starting {
+				log.Printf("[handleTeamReadyStatus] Phase already changed to %s, skipping", currentPhase)
+				return
+			}
+			if err := changeTeamPhase(room, roomKey, "openingFor", version); err != nil {
+				log.Printf("[handleTeamReadyStatus] Failed to start debate in room %s: %v", roomKey, err)
+				room.Mutex.Lock()
+				if room.CurrentPhase == "countdown" {
+					room.CurrentPhase = "setup"
 				}
-				log.Printf("[handleTeamReadyStatus] Debate started! Phase changed to openingFor for %d clients", len(room.Clients))
-			} else {
-				log.Printf("[handleTeamReadyStatus] Phase already changed to %s, skipping", room.CurrentPhase)
+				room.Mutex.Unlock()
+				return
 			}
-			room.Mutex.Unlock()
+			log.Printf("[handleTeamReadyStatus] Debate started! Phase changed to openingFor")
 		}()
 	} else {
 		log.Printf("[handleTeamReadyStatus] Not starting countdown: allReady=%v, phase=%s", allReady, room.CurrentPhase)
@@ -920,8 +1161,9 @@ func handleTeamReadyStatus(room *TeamRoom, conn *websocket.Conn, message TeamMes
 
 // handleTeamTurnRequest handles turn requests
 func handleTeamTurnRequest(room *TeamRoom, conn *websocket.Conn, message TeamMessage, client *TeamClient, roomKey string) {
-	// Attempt to consume tokens if the user is allowed to speak
-	canSpeak, remainingTokens := room.TokenBucket.TryConsumeForSpeaking(client.TeamID, client.UserID, room.TurnManager)
+	// Speaking time is charged while the member speaks, so granting the turn only checks it
+	canSpeak := room.TokenBucket.CanUserSpeak(client.TeamID, client.UserID, room.TurnManager)
+	remainingTokens := room.TokenBucket.GetRemainingTokens(client.TeamID, client.UserID)
 
 	if canSpeak {
 		// Update client tokens
@@ -976,6 +1218,7 @@ func handleTeamTurnRequest(room *TeamRoom, conn *websocket.Conn, message TeamMes
 func handleTeamTurnEnd(room *TeamRoom, conn *websocket.Conn, message TeamMessage, client *TeamClient, roomKey string) {
 	// Advance to next turn
 	nextUserID := room.TurnManager.NextTurn(client.TeamID)
+	syncTeamSpeaker(room, roomKey)
 
 	// Update team status
 	teamStatus, statusErr := room.TokenBucket.GetTeamSpeakingStatus(client.TeamID, room.TurnManager)
@@ -1041,7 +1284,7 @@ func handleCheckStart(room *TeamRoom, conn *websocket.Conn, roomKey string) {
 	}
 
 	allTeam1Ready := team1ReadyCount == team1MembersCount && team1MembersCount > 0
-	allTeam2Ready := team2ReadyCount == team2MembersCount && team2MembersCount > 0
+	allTeam2Ready := room.hasBotTeam() || (team2ReadyCount == team2MembersCount && team2MembersCount > 0)
 	allReady := allTeam1Ready && allTeam2Ready
 
 	log.Printf("[handleCheckStart] Check: Team1=%d/%d ready=%v, Team2=%d/%d ready=%v, AllReady=%v",
@@ -1082,24 +1325,24 @@ func handleCheckStart(room *TeamRoom, conn *websocket.Conn, roomKey string) {
 			}
 
 			room.Mutex.Lock()
-			if room.CurrentPhase == "countdown" || room.CurrentPhase == "setup" {
-				room.CurrentPhase = "openingFor"
+			starting := room.CurrentPhase == "countdown" || room.CurrentPhase == "setup"
+			currentPhase, version := room.CurrentPhase, room.StateVersion
+			room.Mutex.Unlock()
 
-				// Broadcast phase change to ALL clients
-				phaseMessage := TeamMessage{
-					Type:  "phaseChange",
-					Phase: "openingFor",
-				}
-				for _, r := range room.Clients {
-					if err := r.SafeWriteJSON(phaseMessage); err != nil {
-						log.Printf("Team WebSocket write error in room %s: %v", roomKey, err)
-					} else {
-						log.Printf("[handleCheckStart] ✓ Phase change sent")
-					}
+			if !starting {
+				log.Printf("[handleCheckStart] Phase already changed to %s, skipping", currentPhase)
+				return
+			}
+			if err := changeTeamPhase(room, roomKey, "openingFor", version); err != nil {
+				log.Printf("[handleCheckStart] Failed to start debate in room %s: %v", roomKey, err)
+				room.Mutex.Lock()
+				if room.CurrentPhase == "countdown" {
+					room.CurrentPhase = "setup"
 				}
-				log.Printf("[handleCheckStart] Debate started! Phase changed to openingFor")
+				room.Mutex.Unlock()
+				return
 			}
-			room.Mutex.Unlock()
+			log.Printf("[handleCheckStart] Debate started! Phase changed to openingFor")
 		}()
 	} else {
 		log.Printf("[handleCheckStart] Not all ready: Team1=%d/%d, Team2=%d/%d",
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"arguehub/db"
+	"arguehub/internal/debate"
 	"arguehub/services"
 	"arguehub/utils"
 
@@ -30,8 +31,10 @@ var upgrader = websocket.Upgrader{
 
 // Room represents a debate room with connected clients.
 type Room struct {
-	Clients map[*websocket.Conn]*Client
-	Mutex   sync.Mutex
+	Clients   map[*websocket.Conn]*Client
+	Mutex     sync.Mutex
+	Topic     string // Last topic the debaters agreed on, shown in the live directory
+	CreatedAt time.Time
 }
 
 // Client represents a connected client with user information
@@ -91,6 +94,7 @@ type Message struct {
 	CurrentTurn    string `json:"currentTurn,omitempty"`    // "for" or "against"
 	SpeechText     string `json:"speechText,omitempty"`     // Converted speech to text
 	LiveTranscript string `json:"liveTranscript,omitempty"` // Live/interim transcript
+	QuestionID     string `json:"questionId,omitempty"`     // Audience question being answered
 }
 
 type TypingIndicator struct {
@@ -270,7 +274,7 @@ func WebsocketHandler(c *gin.Context) {
 	// Create the room if it doesn't exist.
 	roomsMutex.Lock()
 	if _, exists := rooms[roomID]; !exists {
-		rooms[roomID] = &Room{Clients: make(map[*websocket.Conn]*Client)}
+		rooms[roomID] = &Room{Clients: make(map[*websocket.Conn]*Client), CreatedAt: time.Now()}
 	}
 	room := rooms[roomID]
 	roomsMutex.Unlock()
@@ -418,6 +422,13 @@ func WebsocketHandler(c *gin.Context) {
 				roomsMutex.Lock()
 				delete(rooms, roomID)
 				roomsMutex.Unlock()
+				forgetLiveFeed(roomID)
+				GetDebateHub().CloseDebateLater(roomID, func() bool {
+					roomsMutex.Lock()
+					defer roomsMutex.Unlock()
+					_, reopened := rooms[roomID]
+					return reopened
+				})
 			}
 			room.Mutex.Unlock()
 
@@ -461,7 +472,7 @@ func WebsocketHandler(c *gin.Context) {
 		case "liveTranscript":
 			handleLiveTranscript(room, conn, message, client, roomID)
 		case "phaseChange":
-			handlePhaseChange(room, conn, message, roomID)
+			handlePhaseChange(room, conn, message, client, roomID)
 		case "topicChange":
 			handleTopicChange(room, conn, message, roomID)
 		case "roleSelection":
@@ -474,6 +485,8 @@ func WebsocketHandler(c *gin.Context) {
 			handleUnmuteRequest(room, conn, message, client, roomID)
 		case "concede":
 			handleConcede(room, conn, message, client, roomID)
+		case "questionAnswered":
+			handleQuestionAnswered(room, message, client, roomID)
 		default:
 			if message.Type == "requestOffer" && client.IsSpectator {
 				var req map[string]interface{}
@@ -531,6 +544,16 @@ func handleChatMessage(room *Room, conn *websocket.Conn, message Message, client
 		if err := r.SafeWriteJSON(response); err != nil {
 		}
 	}
+
+	// Debater chat is part of the spectators' live feed
+	if !client.IsSpectator {
+		publishChatFeed(roomID, debate.ChatPayload{
+			UserID:    client.UserID,
+			Username:  client.Username,
+			Content:   message.Content,
+			Timestamp: message.Timestamp,
+		})
+	}
 }
 
 // handleTypingIndicator handles typing indicators
@@ -592,6 +615,16 @@ func handleSpeechText(room *Room, conn *websocket.Conn, message Message, client
 		if err := r.SafeWriteJSON(response); err != nil {
 		}
 	}
+
+	if !client.IsSpectator {
+		publishSpeechFeed(roomID, debate.SpeechPayload{
+			UserID:   client.UserID,
+			Username: client.Username,
+			Side:     client.Role,
+			Phase:    message.Phase,
+			Text:     message.SpeechText,
+		})
+	}
 }
 
 // handleLiveTranscript handles live/interim transcript updates
@@ -611,8 +644,12 @@ func handleLiveTranscript(room *Room, conn *websocket.Conn, message Message, cli
 	}
 }
 
-// handlePhaseChange handles phase changes
-func handlePhaseChange(room *Room, conn *websocket.Conn, message Message, roomID string) {
+// handlePhaseChange handles phase changes. Only debaters move the debate; spectators just watch it.
+func handlePhaseChange(room *Room, conn *websocket.Conn, message Message, client *Client, roomID string) {
+	if client.IsSpectator {
+		return
+	}
+
 	// Determine whose turn it is based on the phase
 	var currentTurn string
 	switch message.Phase {
@@ -651,10 +688,38 @@ func handlePhaseChange(room *Room, conn *websocket.Conn, message Message, roomID
 		if err := r.SafeWriteJSON(message); err != nil {
 		}
 	}
+
+	firstReport := publishPhaseFeed(roomID, debate.PhasePayload{Phase: message.Phase, Side: currentTurn})
+	trackAudienceDecision(roomID, "", message.Phase)
+	if firstReport {
+		pushAudienceQuestions(roomID, "", message.Phase, func(payload map[string]interface{}) {
+			for _, r := range snapshotRecipients(room, nil) {
+				r.SafeWriteJSON(payload)
+			}
+		})
+	}
+}
+
+// handleQuestionAnswered lets a debater mark an audience question as answered
+func handleQuestionAnswered(room *Room, message Message, client *Client, roomID string) {
+	if client.IsSpectator {
+		return
+	}
+	markAudienceQuestionAnswered(roomID, message.QuestionID, func(payload map[string]interface{}) {
+		for _, r := range snapshotRecipients(room, nil) {
+			r.SafeWriteJSON(payload)
+		}
+	})
 }
 
 // handleTopicChange handles topic changes
 func handleTopicChange(room *Room, conn *websocket.Conn, message Message, roomID string) {
+	room.Mutex.Lock()
+	if client, exists := room.Clients[conn]; exists && !client.IsSpectator {
+		room.Topic = strings.TrimSpace(message.Topic)
+	}
+	room.Mutex.Unlock()
+
 	// Broadcast topic change to other clients
 	for _, r := range snapshotRecipients(room, conn) {
 		if err := r.SafeWriteJSON(message); err != nil {
@@ -755,8 +820,12 @@ func getUserDetails(email string) (string, string, string, int, error) {
 	return user.ID.Hex(), user.DisplayName, user.AvatarURL, rating, nil
 }
 
-// handleConcede handles concede requests
+// handleConcede handles concede requests. Spectators have nothing to concede.
 func handleConcede(room *Room, conn *websocket.Conn, message Message, client *Client, roomID string) {
+	if client.IsSpectator {
+		return
+	}
+
 	// Broadcast concede message to all clients (including spectators)
 	broadcastMessage := Message{
 		Type:     "concede",
@@ -770,6 +839,7 @@ func handleConcede(room *Room, conn *websocket.Conn, message Message, client *Cl
 	for _, r := range snapshotRecipients(room, nil) {
 		r.SafeWriteJSON(broadcastMessage)
 	}
+	trackAudienceDecision(roomID, "", "finished")
 
 	// Find opponent
 	var opponent *Client